@@ -0,0 +1,49 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package dump
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// DOT writes the tree as a Graphviz DOT graph
+// The nodes without children are displayed as ellipses, the others as boxes.
+func DOT(w io.Writer, node parsley.Node, opts Options) error {
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString("digraph AST {\n")
+	if e := newEntry(node, opts); e != nil {
+		id := 0
+		writeDOT(bw, e, &id)
+	}
+	_, _ = bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+func writeDOT(w *bufio.Writer, e *entry, nextID *int) int {
+	id := *nextID
+	*nextID++
+
+	shape := "box"
+	if len(e.children) == 0 {
+		shape = "ellipse"
+	}
+	fmt.Fprintf(w, "  n%d [shape=%s, label=\"%s\"];\n", id, shape, dotEscaper.Replace(e.label()))
+
+	for _, child := range e.children {
+		childID := writeDOT(w, child, nextID)
+		fmt.Fprintf(w, "  n%d -> n%d;\n", id, childID)
+	}
+
+	return id
+}
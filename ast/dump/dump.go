@@ -0,0 +1,138 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package dump renders an AST as indented text, S-expression, JSON or Graphviz DOT for debugging
+package dump
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+)
+
+// Options contains the dump options
+type Options struct {
+	// FileSet is used to display the positions as line:column, the raw positions are displayed if it's nil
+	FileSet *parsley.FileSet
+	// HideEmpty hides the empty nodes and the non-terminal nodes without children
+	HideEmpty bool
+	// CollapseChains merges the non-terminal nodes which have a single child with their child,
+	// the tokens will be joined with " > "
+	CollapseChains bool
+}
+
+// entry is the common representation of a node for all output formats
+type entry struct {
+	token    string
+	value    interface{}
+	hasValue bool
+	schema   interface{}
+	err      parsley.Error
+	start    string
+	end      string
+	children []*entry
+}
+
+func newEntry(node parsley.Node, opts Options) *entry {
+	if node == nil || (opts.HideEmpty && isEmpty(node)) {
+		return nil
+	}
+
+	e := &entry{
+		token:  node.Token(),
+		schema: node.Schema(),
+		start:  position(opts.FileSet, node.Pos()),
+		end:    position(opts.FileSet, node.ReaderPos()),
+	}
+
+	switch n := node.(type) {
+	case parsley.LiteralNode:
+		e.value = n.Value()
+		e.hasValue = true
+	case parsley.ErrorNode:
+		e.err = n.Error()
+	case parsley.NonTerminalNode:
+		e.children = newEntries(n.Children(), opts)
+	case ast.NodeList:
+		e.children = newEntries(n, opts)
+	}
+
+	if opts.CollapseChains {
+		if _, ok := node.(parsley.NonTerminalNode); ok && len(e.children) == 1 {
+			child := e.children[0]
+			child.token = e.token + " > " + child.token
+			if child.schema == nil {
+				child.schema = e.schema
+			}
+			return child
+		}
+	}
+
+	return e
+}
+
+func newEntries(nodes []parsley.Node, opts Options) []*entry {
+	res := make([]*entry, 0, len(nodes))
+	for _, node := range nodes {
+		if e := newEntry(node, opts); e != nil {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+func isEmpty(node parsley.Node) bool {
+	switch n := node.(type) {
+	case ast.EmptyNode:
+		return true
+	case parsley.NonTerminalNode:
+		return len(n.Children()) == 0
+	default:
+		return false
+	}
+}
+
+func position(fs *parsley.FileSet, pos parsley.Pos) string {
+	if fs == nil {
+		return strconv.Itoa(int(pos))
+	}
+
+	switch p := fs.Position(pos).(type) {
+	case *text.Position:
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	default:
+		return p.String()
+	}
+}
+
+// label returns with a single-line description of the entry
+func (e *entry) label() string {
+	res := e.token
+	if e.hasValue {
+		res += " " + formatValue(e.value)
+	}
+	if e.err != nil {
+		res += " " + strconv.Quote(e.err.Error())
+	}
+	if e.schema != nil {
+		res += fmt.Sprintf(" (%v)", e.schema)
+	}
+	return res + " " + e.start + ".." + e.end
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case rune:
+		return strconv.QuoteRune(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package dump_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/ast/dump"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+// Let's dump the tree of a simple expression
+func Example() {
+	p := combinator.SeqOf(
+		terminal.Integer("integer"),
+		text.LeftTrim(terminal.Op("+"), text.WsSpaces),
+		text.LeftTrim(terminal.Integer("integer"), text.WsSpaces),
+	).Token("SUM")
+
+	f := text.NewFile("example.file", []byte("1 + 2"))
+	fs := parsley.NewFileSet(f)
+	ctx := parsley.NewContext(fs, text.NewReader(f))
+	node, err := parsley.Parse(ctx, combinator.Sentence(p))
+	if err != nil {
+		panic(err)
+	}
+
+	_ = dump.Text(os.Stdout, node, dump.Options{FileSet: fs})
+	_ = dump.SExpr(os.Stdout, node, dump.Options{})
+	// Output:
+	// SEQ 1:1..1:6
+	//   SUM 1:1..1:6
+	//     INTEGER 1 (integer) 1:1..1:2
+	//     + "+" 1:3..1:4
+	//     INTEGER 2 (integer) 1:5..1:6
+	//   EOF 1:6..1:6
+	// (SEQ (SUM (INTEGER 1) (+ "+") (INTEGER 2)) (EOF))
+}
+
+var _ = Describe("Dump", func() {
+
+	var (
+		node parsley.Node
+		opts dump.Options
+		buf  *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		opts = dump.Options{}
+		buf = &bytes.Buffer{}
+		node = ast.NewNonTerminalNode("ROOT", []parsley.Node{
+			ast.NewNonTerminalNode("WRAPPER", []parsley.Node{
+				ast.NewTerminalNode("string", "STRING", "a\"b", parsley.Pos(1), parsley.Pos(5)),
+			}, nil),
+			ast.EmptyNode(5),
+			ast.NewEmptyNonTerminalNode("LIST", parsley.Pos(5), nil),
+			ast.NewErrorNode(parsley.NewErrorf(parsley.Pos(5), "some error"), parsley.Pos(5), parsley.Pos(7)),
+		}, nil)
+	})
+
+	Describe("Text", func() {
+		It("should write all nodes", func() {
+			Expect(dump.Text(buf, node, opts)).To(Succeed())
+			Expect(buf.String()).To(Equal(`ROOT 1..7
+  WRAPPER 1..5
+    STRING "a\"b" (string) 1..5
+  EMPTY 5..5
+  LIST 5..5
+  ERROR "some error" 5..7
+`))
+		})
+
+		It("should hide the empty nodes", func() {
+			opts.HideEmpty = true
+			Expect(dump.Text(buf, node, opts)).To(Succeed())
+			Expect(buf.String()).To(Equal(`ROOT 1..7
+  WRAPPER 1..5
+    STRING "a\"b" (string) 1..5
+  ERROR "some error" 5..7
+`))
+		})
+
+		It("should collapse the single-child chains", func() {
+			opts.CollapseChains = true
+			opts.HideEmpty = true
+			Expect(dump.Text(buf, node, opts)).To(Succeed())
+			Expect(buf.String()).To(Equal(`ROOT 1..7
+  WRAPPER > STRING "a\"b" (string) 1..5
+  ERROR "some error" 5..7
+`))
+		})
+
+		It("should display the line and column if a file set is given", func() {
+			f := text.NewFile("testfile", []byte("abc\ndefgh"))
+			opts.FileSet = parsley.NewFileSet(f)
+			Expect(dump.Text(buf, ast.NewTerminalNode(nil, "X", 1, f.Pos(5), f.Pos(7)), opts)).To(Succeed())
+			Expect(buf.String()).To(Equal("X 1 2:2..2:4\n"))
+		})
+
+		It("should not write anything for a hidden root node", func() {
+			opts.HideEmpty = true
+			Expect(dump.Text(buf, ast.EmptyNode(1), opts)).To(Succeed())
+			Expect(buf.String()).To(BeEmpty())
+		})
+	})
+
+	Describe("SExpr", func() {
+		It("should write the tree as an S-expression", func() {
+			opts.HideEmpty = true
+			Expect(dump.SExpr(buf, node, opts)).To(Succeed())
+			Expect(buf.String()).To(Equal(`(ROOT (WRAPPER (STRING "a\"b")) (ERROR "some error"))` + "\n"))
+		})
+
+		It("should remove the spaces from the collapsed tokens", func() {
+			opts.HideEmpty = true
+			opts.CollapseChains = true
+			Expect(dump.SExpr(buf, node, opts)).To(Succeed())
+			Expect(buf.String()).To(Equal(`(ROOT (WRAPPER>STRING "a\"b") (ERROR "some error"))` + "\n"))
+		})
+	})
+
+	Describe("JSON", func() {
+		It("should write the tree as JSON", func() {
+			opts.HideEmpty = true
+			Expect(dump.JSON(buf, node, opts)).To(Succeed())
+
+			var res map[string]interface{}
+			Expect(json.Unmarshal(buf.Bytes(), &res)).To(Succeed())
+			Expect(res).To(Equal(map[string]interface{}{
+				"token": "ROOT",
+				"start": "1",
+				"end":   "7",
+				"children": []interface{}{
+					map[string]interface{}{
+						"token": "WRAPPER",
+						"start": "1",
+						"end":   "5",
+						"children": []interface{}{
+							map[string]interface{}{
+								"token":  "STRING",
+								"value":  "a\"b",
+								"schema": "string",
+								"start":  "1",
+								"end":    "5",
+							},
+						},
+					},
+					map[string]interface{}{
+						"token": "ERROR",
+						"error": "some error",
+						"start": "5",
+						"end":   "7",
+					},
+				},
+			}))
+		})
+
+		It("should write null for a hidden root node", func() {
+			opts.HideEmpty = true
+			Expect(dump.JSON(buf, ast.EmptyNode(1), opts)).To(Succeed())
+			Expect(buf.String()).To(Equal("null\n"))
+		})
+	})
+
+	Describe("DOT", func() {
+		It("should write the tree as a Graphviz graph", func() {
+			opts.HideEmpty = true
+			Expect(dump.DOT(buf, node, opts)).To(Succeed())
+			Expect(buf.String()).To(Equal(`digraph AST {
+  n0 [shape=box, label="ROOT 1..7"];
+  n1 [shape=box, label="WRAPPER 1..5"];
+  n2 [shape=ellipse, label="STRING \"a\\\"b\" (string) 1..5"];
+  n1 -> n2;
+  n0 -> n1;
+  n3 [shape=ellipse, label="ERROR \"some error\" 5..7"];
+  n0 -> n3;
+}
+`))
+		})
+	})
+})
@@ -0,0 +1,63 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package dump
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+type jsonNode struct {
+	Token    string      `json:"token"`
+	Value    interface{} `json:"value,omitempty"`
+	Schema   string      `json:"schema,omitempty"`
+	Error    string      `json:"error,omitempty"`
+	Start    string      `json:"start"`
+	End      string      `json:"end"`
+	Children []*jsonNode `json:"children,omitempty"`
+}
+
+// JSON writes the tree as an indented JSON document
+// Every node has a token, start and end field and optionally a value, schema, error and children field.
+// The schema is converted to a string.
+func JSON(w io.Writer, node parsley.Node, opts Options) error {
+	var root *jsonNode
+	if e := newEntry(node, opts); e != nil {
+		root = newJSONNode(e)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(root)
+}
+
+func newJSONNode(e *entry) *jsonNode {
+	n := &jsonNode{
+		Token: e.token,
+		Value: e.value,
+		Start: e.start,
+		End:   e.end,
+	}
+	if e.schema != nil {
+		n.Schema = fmt.Sprintf("%v", e.schema)
+	}
+	if e.err != nil {
+		n.Error = e.err.Error()
+	}
+	if e.value != nil {
+		if r, ok := e.value.(rune); ok {
+			n.Value = string(r)
+		}
+	}
+	for _, child := range e.children {
+		n.Children = append(n.Children, newJSONNode(child))
+	}
+	return n
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package dump
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+// SExpr writes the tree as a single-line S-expression
+// A terminal node is written as (TOKEN value), an error node as (TOKEN "message") and a non-terminal node as
+// (TOKEN child1 child2 ...).
+// The positions and the schemas are not included.
+func SExpr(w io.Writer, node parsley.Node, opts Options) error {
+	bw := bufio.NewWriter(w)
+	if e := newEntry(node, opts); e != nil {
+		writeSExpr(bw, e)
+	}
+	_ = bw.WriteByte('\n')
+	return bw.Flush()
+}
+
+func writeSExpr(w *bufio.Writer, e *entry) {
+	_ = w.WriteByte('(')
+	_, _ = w.WriteString(strings.Replace(e.token, " ", "", -1))
+	if e.hasValue {
+		_ = w.WriteByte(' ')
+		_, _ = w.WriteString(formatValue(e.value))
+	}
+	if e.err != nil {
+		_ = w.WriteByte(' ')
+		_, _ = w.WriteString(formatValue(e.err.Error()))
+	}
+	for _, child := range e.children {
+		_ = w.WriteByte(' ')
+		writeSExpr(w, child)
+	}
+	_ = w.WriteByte(')')
+}
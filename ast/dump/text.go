@@ -0,0 +1,34 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package dump
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+// Text writes the tree as indented text, one node per line
+// Every line contains the token, the value, the schema and the start and end position of the node.
+func Text(w io.Writer, node parsley.Node, opts Options) error {
+	bw := bufio.NewWriter(w)
+	if e := newEntry(node, opts); e != nil {
+		writeText(bw, e, 0)
+	}
+	return bw.Flush()
+}
+
+func writeText(w *bufio.Writer, e *entry, depth int) {
+	_, _ = w.WriteString(strings.Repeat("  ", depth))
+	_, _ = w.WriteString(e.label())
+	_ = w.WriteByte('\n')
+	for _, child := range e.children {
+		writeText(w, child, depth+1)
+	}
+}
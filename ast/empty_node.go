@@ -35,6 +35,11 @@ func (e EmptyNode) ReaderPos() parsley.Pos {
 	return parsley.Pos(e)
 }
 
+// ShiftPos returns with a new node moved by the shifter's delta
+func (e EmptyNode) ShiftPos(s *parsley.Shifter) parsley.Node {
+	return EmptyNode(s.Pos(parsley.Pos(e)))
+}
+
 // String returns with a string representation of the node
 func (e EmptyNode) String() string {
 	return fmt.Sprintf("%s{%d}", e.Token(), e.Pos())
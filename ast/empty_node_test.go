@@ -40,5 +40,10 @@ var _ = Describe("EmptyNode", func() {
 		It("String() should return with NIL", func() {
 			Expect(node.String()).To(Equal("EMPTY{1}"))
 		})
+
+		It("ShiftPos() should return with a moved node", func() {
+			res := node.ShiftPos(parsley.NewShifter(2))
+			Expect(res).To(Equal(ast.EmptyNode(3)))
+		})
 	})
 })
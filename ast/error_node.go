@@ -0,0 +1,78 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package ast
+
+import (
+	"fmt"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+// ErrorNode represents a part of the input which couldn't be parsed
+// The parser recovered from the error, so the node covers all the skipped input.
+type ErrorNode struct {
+	err       parsley.Error
+	pos       parsley.Pos
+	readerPos parsley.Pos
+}
+
+// NewErrorNode creates a new ErrorNode instance
+func NewErrorNode(err parsley.Error, pos parsley.Pos, readerPos parsley.Pos) *ErrorNode {
+	return &ErrorNode{
+		err:       err,
+		pos:       pos,
+		readerPos: readerPos,
+	}
+}
+
+// Token returns with ERROR
+func (e *ErrorNode) Token() string {
+	return "ERROR"
+}
+
+// Schema returns nil
+func (e *ErrorNode) Schema() interface{} {
+	return nil
+}
+
+// Value returns with the recovered error, as an error node can not be evaluated
+func (e *ErrorNode) Value(userCtx interface{}) (interface{}, parsley.Error) {
+	return nil, e.err
+}
+
+// Error returns with the recovered error
+func (e *ErrorNode) Error() parsley.Error {
+	return e.err
+}
+
+// Pos returns the position
+func (e *ErrorNode) Pos() parsley.Pos {
+	return e.pos
+}
+
+// ReaderPos returns the position of the first character immediately after the skipped input
+func (e *ErrorNode) ReaderPos() parsley.Pos {
+	return e.readerPos
+}
+
+// SetReaderPos changes the reader position
+func (e *ErrorNode) SetReaderPos(f func(parsley.Pos) parsley.Pos) {
+	e.readerPos = f(e.readerPos)
+}
+
+// ShiftPos moves the node and its error by the shifter's delta
+func (e *ErrorNode) ShiftPos(s *parsley.Shifter) parsley.Node {
+	e.err = s.Error(e.err)
+	e.pos = s.Pos(e.pos)
+	e.readerPos = s.Pos(e.readerPos)
+	return e
+}
+
+// String returns with a string representation of the node
+func (e *ErrorNode) String() string {
+	return fmt.Sprintf("%s{%s, %d..%d}", e.Token(), e.err, e.pos, e.readerPos)
+}
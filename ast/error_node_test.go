@@ -0,0 +1,76 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package ast_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/parsley"
+)
+
+var _ = Describe("ErrorNode", func() {
+	var (
+		node      *ast.ErrorNode
+		err       = parsley.NewErrorf(parsley.Pos(1), "some error")
+		pos       = parsley.Pos(1)
+		readerPos = parsley.Pos(2)
+	)
+
+	JustBeforeEach(func() {
+		node = ast.NewErrorNode(err, pos, readerPos)
+	})
+
+	Describe("Methods", func() {
+		It("Token() should return with ERROR", func() {
+			Expect(node.Token()).To(Equal("ERROR"))
+		})
+
+		It("Schema() should return nil", func() {
+			Expect(node.Schema()).To(BeNil())
+		})
+
+		It("Value() should return with the error", func() {
+			value, valueErr := node.Value(nil)
+			Expect(value).To(BeNil())
+			Expect(valueErr).To(Equal(err))
+		})
+
+		It("Error() should return with the error", func() {
+			Expect(node.Error()).To(Equal(err))
+		})
+
+		It("Pos() should return with the position", func() {
+			Expect(node.Pos()).To(Equal(pos))
+		})
+
+		It("ReaderPos() should return with the reader position", func() {
+			Expect(node.ReaderPos()).To(Equal(readerPos))
+		})
+
+		It("SetReaderPos() should modify the reader position", func() {
+			node.SetReaderPos(func(pos parsley.Pos) parsley.Pos {
+				return parsley.Pos(pos + 1)
+			})
+			Expect(node.ReaderPos()).To(Equal(parsley.Pos(3)))
+		})
+
+		It("ShiftPos() should move the node and the error", func() {
+			res := node.ShiftPos(parsley.NewShifter(2))
+			Expect(res).To(BeIdenticalTo(node))
+			Expect(node.Pos()).To(Equal(parsley.Pos(3)))
+			Expect(node.ReaderPos()).To(Equal(parsley.Pos(4)))
+			Expect(node.Error().Pos()).To(Equal(parsley.Pos(3)))
+			Expect(node.Error().Error()).To(Equal("some error"))
+		})
+
+		It("String() should return with a readable representation", func() {
+			Expect(node.String()).To(Equal("ERROR{some error, 1..2}"))
+		})
+	})
+})
@@ -48,6 +48,7 @@ func Nil() ast.InterpreterFunc {
 
 // Array can be used to create an array from a list of nodes, where values and separators are following
 // each-other
+// The trailing separator (SepEndBy) and the terminating separators (EndBy) are skipped as well.
 func Array() ast.InterpreterFunc {
 	return ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
 		nodes := node.Children()
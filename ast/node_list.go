@@ -39,6 +39,12 @@ func (nl NodeList) SetReaderPos(f func(parsley.Pos) parsley.Pos) {
 	}
 }
 
+// ShiftPos moves all nodes by the shifter's delta
+func (nl NodeList) ShiftPos(s *parsley.Shifter) parsley.Node {
+	s.Nodes(nl)
+	return nl
+}
+
 // Walk runs the given function on the first node
 func (nl NodeList) Walk(f func(n parsley.Node) bool) bool {
 	return parsley.Walk(nl[0], f)
@@ -177,4 +177,18 @@ var _ = Describe("NodeList", func() {
 			Expect(n2.SetReaderPosCallCount()).To(Equal(1))
 		})
 	})
+
+	Describe("ShiftPos", func() {
+		It("should move all nodes", func() {
+			n1 := ast.NewTerminalNode("", "N1", "a", parsley.Pos(1), parsley.Pos(2))
+			n2 := ast.EmptyNode(2)
+			nl = ast.NodeList([]parsley.Node{n1, n2})
+
+			res := nl.ShiftPos(parsley.NewShifter(2))
+
+			Expect(res).To(Equal(nl))
+			Expect(n1.Pos()).To(Equal(parsley.Pos(3)))
+			Expect(nl[1]).To(Equal(ast.EmptyNode(4)))
+		})
+	})
 })
@@ -126,6 +126,19 @@ func (n *NonTerminalNode) SetReaderPos(f func(parsley.Pos) parsley.Pos) {
 	n.readerPos = f(n.readerPos)
 }
 
+// SetPos amends the position using the given function, e.g. if the first child is not the first in the input
+func (n *NonTerminalNode) SetPos(f func(parsley.Pos) parsley.Pos) {
+	n.pos = f(n.pos)
+}
+
+// ShiftPos moves the node and all its children by the shifter's delta
+func (n *NonTerminalNode) ShiftPos(s *parsley.Shifter) parsley.Node {
+	n.pos = s.Pos(n.pos)
+	n.readerPos = s.Pos(n.readerPos)
+	s.Nodes(n.children)
+	return n
+}
+
 // String returns with a string representation of the node
 func (n *NonTerminalNode) String() string {
 	return fmt.Sprintf("%s{%s, %d..%d}", n.token, n.children, n.pos, n.readerPos)
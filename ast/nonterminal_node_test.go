@@ -121,6 +121,26 @@ var _ = Describe("NonTerminalNode", func() {
 				Expect(node.ReaderPos()).To(Equal(parsley.Pos(3)))
 			})
 
+			It("SetPos() should modify the position", func() {
+				node.SetPos(func(pos parsley.Pos) parsley.Pos {
+					return parsley.Pos(pos - 1)
+				})
+				Expect(node.Pos()).To(Equal(parsley.Pos(0)))
+			})
+
+			It("ShiftPos() should move the node and the children", func() {
+				child := ast.NewTerminalNode("", "CHILD", "x", parsley.Pos(1), parsley.Pos(2))
+				node = ast.NewNonTerminalNode(token, []parsley.Node{child, ast.NodeList{child}}, interpreter)
+
+				res := node.ShiftPos(parsley.NewShifter(2))
+
+				Expect(res).To(BeIdenticalTo(node))
+				Expect(node.Pos()).To(Equal(parsley.Pos(3)))
+				Expect(node.ReaderPos()).To(Equal(parsley.Pos(4)))
+				Expect(child.Pos()).To(Equal(parsley.Pos(3)), "a shared child should be moved only once")
+				Expect(child.ReaderPos()).To(Equal(parsley.Pos(4)))
+			})
+
 			It("Children() should return with the children", func() {
 				Expect(node.Children()).To(Equal(children))
 			})
@@ -68,6 +68,13 @@ func (t *TerminalNode) SetReaderPos(f func(parsley.Pos) parsley.Pos) {
 	t.readerPos = f(t.readerPos)
 }
 
+// ShiftPos moves the node by the shifter's delta
+func (t *TerminalNode) ShiftPos(s *parsley.Shifter) parsley.Node {
+	t.pos = s.Pos(t.pos)
+	t.readerPos = s.Pos(t.readerPos)
+	return t
+}
+
 // String returns with a string representation of the node
 func (t *TerminalNode) String() string {
 	return fmt.Sprintf("%s{%v, %d..%d}", t.token, t.value, t.pos, t.readerPos)
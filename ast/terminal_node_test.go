@@ -57,6 +57,13 @@ var _ = Describe("TerminalNode", func() {
 			Expect(node.ReaderPos()).To(Equal(parsley.Pos(3)))
 		})
 
+		It("ShiftPos() should move the node", func() {
+			res := node.ShiftPos(parsley.NewShifter(2))
+			Expect(res).To(BeIdenticalTo(node))
+			Expect(node.Pos()).To(Equal(parsley.Pos(3)))
+			Expect(node.ReaderPos()).To(Equal(parsley.Pos(4)))
+		})
+
 		It("String() should return with a readable representation", func() {
 			Expect(node.String()).To(Equal("TEST{some value, 1..2}"))
 		})
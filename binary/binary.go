@@ -0,0 +1,18 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package binary defines a binary input reader and the binary terminal parsers.
+package binary
+
+// ByteOrder defines the byte order of multi-byte values
+type ByteOrder int
+
+const (
+	// BigEndian means the most significant byte comes first
+	BigEndian ByteOrder = iota
+	// LittleEndian means the least significant byte comes first
+	LittleEndian
+)
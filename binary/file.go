@@ -0,0 +1,72 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package binary
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+// File is a binary file
+type File struct {
+	filename string
+	data     []byte
+	len      int
+	offset   int
+}
+
+// NewFile creates a new binary file
+func NewFile(filename string, data []byte) *File {
+	return &File{
+		filename: filename,
+		data:     data,
+		len:      len(data),
+		offset:   1,
+	}
+}
+
+// ReadFile reads a binary file from the filesystem
+func ReadFile(filename string) (*File, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("can not read %s", filename)
+	}
+	return NewFile(filename, data), nil
+}
+
+// Filename returns with the file name
+func (f *File) Filename() string {
+	return f.filename
+}
+
+// Len returns with the file length in bytes
+func (f *File) Len() int {
+	return f.len
+}
+
+// SetOffset sets the global offset of the file in the file set
+func (f *File) SetOffset(offset int) {
+	f.offset = offset
+}
+
+// Position returns with the byte offset position for the given local position
+func (f *File) Position(pos int) parsley.Position {
+	if pos > f.len {
+		return parsley.NilPosition
+	}
+	return &Position{
+		Filename: f.filename,
+		Offset:   pos,
+	}
+}
+
+// Pos returns with the global position for the given local position
+func (f *File) Pos(pos int) parsley.Pos {
+	return parsley.Pos(f.offset + pos)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package binary_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/binary"
+	"github.com/conflowio/parsley/parsley"
+)
+
+var _ = Describe("File", func() {
+
+	var (
+		fs   *parsley.FileSet
+		f    *binary.File
+		data []byte
+	)
+
+	JustBeforeEach(func() {
+		f = binary.NewFile("testfile", data)
+		fs = parsley.NewFileSet(f)
+	})
+
+	BeforeEach(func() {
+		data = []byte{0x01, '\r', '\n', 0xff}
+	})
+
+	It("should implement the parsley.File interface", func() {
+		var _ parsley.File = &binary.File{}
+	})
+
+	It("should implement the parsley.NamedFile interface", func() {
+		var _ parsley.NamedFile = &binary.File{}
+	})
+
+	It("should return the byte range without lines", func() {
+		r, ok := fs.Range(f.Pos(1), f.Pos(3))
+		Expect(ok).To(BeTrue())
+		Expect(r.String()).To(Equal("testfile:1-3"))
+	})
+
+	Describe("Position()", func() {
+		It("should return with the position for an offset", func() {
+			Expect(f.Position(0)).To(Equal(binary.NewPosition("testfile", 0)))
+			Expect(f.Position(3)).To(Equal(binary.NewPosition("testfile", 3)))
+			Expect(f.Position(4)).To(Equal(binary.NewPosition("testfile", 4)))
+		})
+
+		It("should return nil position for an invalid offset", func() {
+			Expect(f.Position(5)).To(Equal(parsley.NilPosition))
+		})
+	})
+
+	Describe("Len()", func() {
+		Context("when data is empty", func() {
+			BeforeEach(func() {
+				data = []byte{}
+			})
+
+			It("should return zero", func() {
+				Expect(f.Len()).To(Equal(0))
+			})
+		})
+
+		It("should return the data length without altering line endings", func() {
+			Expect(f.Len()).To(Equal(4))
+		})
+	})
+
+	Context("ReadFile", func() {
+		var (
+			readFileErr error
+			filename    string
+		)
+
+		JustBeforeEach(func() {
+			f, readFileErr = binary.ReadFile(filename)
+		})
+
+		Context("reading an existing file", func() {
+			var tmpDir string
+
+			BeforeEach(func() {
+				var err error
+				tmpDir, err = os.MkdirTemp("", "parsley-test-")
+				Expect(err).ToNot(HaveOccurred())
+				filename = filepath.Join(tmpDir, "testfile")
+				Expect(os.WriteFile(filename, []byte{0x01, 0x02, 0x03}, 0600)).To(Succeed())
+			})
+
+			AfterEach(func() {
+				if tmpDir != "" {
+					os.RemoveAll(tmpDir)
+				}
+			})
+
+			It("should succeed", func() {
+				Expect(readFileErr).ToNot(HaveOccurred())
+				Expect(f.Len()).To(Equal(3))
+				Expect(f.Position(2).String()).To(Equal(filename + ":0x2"))
+			})
+		})
+
+		Context("reading a non-existing file", func() {
+			BeforeEach(func() {
+				filename = "/tmp/non-existing-filename"
+			})
+
+			It("should throw an error", func() {
+				Expect(readFileErr).To(MatchError("can not read /tmp/non-existing-filename"))
+			})
+		})
+	})
+
+	Describe("Pos()", func() {
+		It("should return with a global position", func() {
+			pos := f.Pos(3)
+			Expect(pos).To(Equal(parsley.Pos(4)))
+			Expect(fs.Position(pos)).To(Equal(binary.NewPosition("testfile", 3)))
+		})
+	})
+})
@@ -0,0 +1,34 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package binary
+
+import (
+	"fmt"
+)
+
+// Position is a byte offset in a binary file
+type Position struct {
+	Filename string
+	Offset   int
+}
+
+// NewPosition creates a new binary position
+func NewPosition(filename string, offset int) *Position {
+	return &Position{
+		Filename: filename,
+		Offset:   offset,
+	}
+}
+
+// String returns with the filename and the hexadecimal offset
+func (pos Position) String() string {
+	if pos.Filename != "" {
+		return fmt.Sprintf("%s:0x%x", pos.Filename, pos.Offset)
+	}
+
+	return fmt.Sprintf("0x%x", pos.Offset)
+}
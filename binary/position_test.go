@@ -0,0 +1,50 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package binary_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/binary"
+	"github.com/conflowio/parsley/parsley"
+)
+
+var _ = Describe("Position", func() {
+	var (
+		pos      *binary.Position
+		offset   int
+		filename string
+	)
+
+	BeforeEach(func() {
+		offset = 26
+		filename = "testfile"
+	})
+
+	JustBeforeEach(func() {
+		pos = binary.NewPosition(filename, offset)
+	})
+
+	It("should implement the parsley.Position interface", func() {
+		var _ parsley.Position = binary.Position{}
+	})
+
+	It("should return with a string containing all information", func() {
+		Expect(pos.String()).To(Equal("testfile:0x1a"))
+	})
+
+	Context("no filename", func() {
+		BeforeEach(func() {
+			filename = ""
+		})
+
+		It("should return with a string without the filename", func() {
+			Expect(pos.String()).To(Equal("0x1a"))
+		})
+	})
+})
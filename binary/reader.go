@@ -0,0 +1,138 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package binary
+
+import (
+	"bytes"
+	encoding_binary "encoding/binary"
+	"fmt"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+// Reader is a byte-oriented reader over a binary file
+type Reader struct {
+	file *File
+}
+
+// NewReader creates a new binary reader
+func NewReader(file *File) *Reader {
+	return &Reader{
+		file: file,
+	}
+}
+
+// ReadBytes reads the next n bytes, it returns nil if there is not enough input left
+func (r *Reader) ReadBytes(pos parsley.Pos, n int) (parsley.Pos, []byte) {
+	if n < 0 {
+		panic("ReadBytes() should not be called with a negative length")
+	}
+
+	cur := int(pos) - r.file.offset
+	if n > r.file.len-cur {
+		return pos, nil
+	}
+
+	return r.file.Pos(cur + n), r.file.data[cur : cur+n]
+}
+
+// MatchBytes matches the given byte sequence
+func (r *Reader) MatchBytes(pos parsley.Pos, b []byte) (parsley.Pos, bool) {
+	if len(b) == 0 {
+		panic("MatchBytes() should not be called with an empty byte slice")
+	}
+
+	cur := int(pos) - r.file.offset
+	if len(b) > r.file.len-cur {
+		return pos, false
+	}
+
+	if bytes.HasPrefix(r.file.data[cur:], b) {
+		return r.file.Pos(cur + len(b)), true
+	}
+	return pos, false
+}
+
+// ReadUint reads an unsigned integer of the given size (1-8 bytes)
+func (r *Reader) ReadUint(pos parsley.Pos, size int, order ByteOrder) (parsley.Pos, uint64, bool) {
+	if size < 1 || size > 8 {
+		panic(fmt.Sprintf("ReadUint() was called with an invalid size: %d", size))
+	}
+
+	readerPos, b := r.ReadBytes(pos, size)
+	if b == nil {
+		return pos, 0, false
+	}
+
+	var value uint64
+	if order == LittleEndian {
+		for i := size - 1; i >= 0; i-- {
+			value = value<<8 | uint64(b[i])
+		}
+	} else {
+		for i := 0; i < size; i++ {
+			value = value<<8 | uint64(b[i])
+		}
+	}
+
+	return readerPos, value, true
+}
+
+// ReadUvarint reads an unsigned varint as encoded by encoding/binary
+func (r *Reader) ReadUvarint(pos parsley.Pos) (parsley.Pos, uint64, bool) {
+	cur := int(pos) - r.file.offset
+	if cur >= r.file.len {
+		return pos, 0, false
+	}
+
+	value, n := encoding_binary.Uvarint(r.file.data[cur:])
+	if n <= 0 {
+		return pos, 0, false
+	}
+
+	return r.file.Pos(cur + n), value, true
+}
+
+// ReadVarint reads a zig-zag encoded signed varint as encoded by encoding/binary
+func (r *Reader) ReadVarint(pos parsley.Pos) (parsley.Pos, int64, bool) {
+	cur := int(pos) - r.file.offset
+	if cur >= r.file.len {
+		return pos, 0, false
+	}
+
+	value, n := encoding_binary.Varint(r.file.data[cur:])
+	if n <= 0 {
+		return pos, 0, false
+	}
+
+	return r.file.Pos(cur + n), value, true
+}
+
+// Remaining returns with the number of remaining bytes
+func (r *Reader) Remaining(pos parsley.Pos) int {
+	return r.file.len - (int(pos) - r.file.offset)
+}
+
+// IsEOF returns true if we reached the end of the input
+func (r *Reader) IsEOF(pos parsley.Pos) bool {
+	return int(pos)-r.file.offset >= r.file.len
+}
+
+// DescribeInput returns with the next byte in hexadecimal format
+func (r *Reader) DescribeInput(pos parsley.Pos) string {
+	cur := int(pos) - r.file.offset
+	if cur >= r.file.len {
+		return "end of input"
+	}
+
+	return fmt.Sprintf("0x%02x", r.file.data[cur])
+}
+
+// Pos returns with the global position for the given local position
+func (r *Reader) Pos(cur int) parsley.Pos {
+	return r.file.Pos(cur)
+}
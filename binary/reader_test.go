@@ -0,0 +1,155 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package binary_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/binary"
+	"github.com/conflowio/parsley/parsley"
+)
+
+var _ = Describe("Reader", func() {
+
+	var (
+		f    *binary.File
+		r    *binary.Reader
+		data []byte
+	)
+
+	BeforeEach(func() {
+		data = []byte{0x01, 0x02, 0x03, 0x04}
+	})
+
+	JustBeforeEach(func() {
+		f = binary.NewFile("testfile", data)
+		parsley.NewFileSet(f)
+		r = binary.NewReader(f)
+	})
+
+	It("should implement the parsley.Reader interface", func() {
+		var _ parsley.Reader = r
+	})
+
+	It("should implement the parsley.InputDescriber interface", func() {
+		var _ parsley.InputDescriber = r
+	})
+
+	Describe("ReadBytes()", func() {
+		It("should read the next bytes", func() {
+			pos, b := r.ReadBytes(f.Pos(1), 2)
+			Expect(b).To(Equal([]byte{0x02, 0x03}))
+			Expect(pos).To(Equal(f.Pos(3)))
+		})
+
+		It("should return nil if there is not enough input", func() {
+			pos, b := r.ReadBytes(f.Pos(3), 2)
+			Expect(b).To(BeNil())
+			Expect(pos).To(Equal(f.Pos(3)))
+		})
+
+		It("should panic for a negative length", func() {
+			Expect(func() { r.ReadBytes(f.Pos(0), -1) }).To(Panic())
+		})
+	})
+
+	Describe("MatchBytes()", func() {
+		It("should match the given bytes", func() {
+			pos, found := r.MatchBytes(f.Pos(1), []byte{0x02, 0x03})
+			Expect(found).To(BeTrue())
+			Expect(pos).To(Equal(f.Pos(3)))
+		})
+
+		It("should not match different bytes", func() {
+			pos, found := r.MatchBytes(f.Pos(1), []byte{0x02, 0x04})
+			Expect(found).To(BeFalse())
+			Expect(pos).To(Equal(f.Pos(1)))
+		})
+
+		It("should not match if there is not enough input", func() {
+			pos, found := r.MatchBytes(f.Pos(3), []byte{0x04, 0x05})
+			Expect(found).To(BeFalse())
+			Expect(pos).To(Equal(f.Pos(3)))
+		})
+
+		It("should panic for an empty slice", func() {
+			Expect(func() { r.MatchBytes(f.Pos(0), nil) }).To(Panic())
+		})
+	})
+
+	DescribeTable("ReadUint()",
+		func(size int, order binary.ByteOrder, expectedValue uint64, expectedOk bool, endPos int) {
+			pos, value, ok := r.ReadUint(f.Pos(0), size, order)
+			Expect(ok).To(Equal(expectedOk))
+			Expect(value).To(Equal(expectedValue))
+			Expect(pos).To(Equal(f.Pos(endPos)))
+		},
+		Entry("1 byte", 1, binary.BigEndian, uint64(0x01), true, 1),
+		Entry("2 bytes, big-endian", 2, binary.BigEndian, uint64(0x0102), true, 2),
+		Entry("2 bytes, little-endian", 2, binary.LittleEndian, uint64(0x0201), true, 2),
+		Entry("3 bytes, big-endian", 3, binary.BigEndian, uint64(0x010203), true, 3),
+		Entry("4 bytes, big-endian", 4, binary.BigEndian, uint64(0x01020304), true, 4),
+		Entry("4 bytes, little-endian", 4, binary.LittleEndian, uint64(0x04030201), true, 4),
+		Entry("not enough input", 8, binary.BigEndian, uint64(0), false, 0),
+	)
+
+	It("ReadUint() should panic for an invalid size", func() {
+		Expect(func() { r.ReadUint(f.Pos(0), 9, binary.BigEndian) }).To(Panic())
+	})
+
+	Describe("ReadUvarint() and ReadVarint()", func() {
+		BeforeEach(func() {
+			data = []byte{0xac, 0x02, 0x03, 0x80}
+		})
+
+		It("should read an unsigned varint", func() {
+			pos, value, ok := r.ReadUvarint(f.Pos(0))
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal(uint64(300)))
+			Expect(pos).To(Equal(f.Pos(2)))
+		})
+
+		It("should read a signed varint", func() {
+			pos, value, ok := r.ReadVarint(f.Pos(2))
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal(int64(-2)))
+			Expect(pos).To(Equal(f.Pos(3)))
+		})
+
+		It("should not match a truncated varint", func() {
+			pos, _, ok := r.ReadUvarint(f.Pos(3))
+			Expect(ok).To(BeFalse())
+			Expect(pos).To(Equal(f.Pos(3)))
+		})
+
+		It("should not match at the end of the input", func() {
+			_, _, ok := r.ReadVarint(f.Pos(4))
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("Remaining() and IsEOF()", func() {
+		It("should return with the remaining bytes", func() {
+			Expect(r.Remaining(f.Pos(1))).To(Equal(3))
+			Expect(r.IsEOF(f.Pos(1))).To(BeFalse())
+			Expect(r.Remaining(f.Pos(4))).To(Equal(0))
+			Expect(r.IsEOF(f.Pos(4))).To(BeTrue())
+		})
+	})
+
+	Describe("DescribeInput()", func() {
+		It("should describe the next byte", func() {
+			Expect(r.DescribeInput(f.Pos(3))).To(Equal("0x04"))
+		})
+
+		It("should describe the end of input", func() {
+			Expect(r.DescribeInput(f.Pos(4))).To(Equal("end of input"))
+		})
+	})
+})
@@ -0,0 +1,60 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package terminal
+
+import (
+	"fmt"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/binary"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// BitFields matches bit fields with the given widths, starting from the most significant bit of the first byte
+// The total width must be a multiple of 8. The value is an []uint64 containing the field values in order.
+func BitFields(schema interface{}, widths ...int) parser.Func {
+	if len(widths) == 0 {
+		panic("BitFields() should be called with at least one field width")
+	}
+
+	var total int
+	for _, w := range widths {
+		if w < 1 || w > 64 {
+			panic(fmt.Sprintf("BitFields() was called with an invalid field width: %d", w))
+		}
+		total += w
+	}
+	if total%8 != 0 {
+		panic(fmt.Sprintf("BitFields() was called with %d bits in total, it must be a multiple of 8", total))
+	}
+
+	size := total / 8
+	notFoundErr := parsley.NotFoundError(fmt.Sprintf("%d-byte bit fields", size))
+
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		br := ctx.Reader().(*binary.Reader)
+		readerPos, b := br.ReadBytes(pos, size)
+		if b == nil {
+			return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
+		}
+
+		values := make([]uint64, len(widths))
+		bit := 0
+		for i, w := range widths {
+			var value uint64
+			for j := 0; j < w; j++ {
+				value = value<<1 | uint64(b[bit/8]>>(7-uint(bit%8))&1)
+				bit++
+			}
+			values[i] = value
+		}
+
+		return ast.NewTerminalNode(schema, "BITFIELDS", values, pos, readerPos), data.EmptyIntSet, nil
+	}).Trace("BITFIELDS")
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package terminal_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/binary/terminal"
+	"github.com/conflowio/parsley/parsley"
+)
+
+var _ = Describe("BitFields", func() {
+
+	It("should match the bit fields", func() {
+		p := terminal.BitFields("flags", 1, 3, 12)
+		f, res, err := parse(p, []byte{0xb1, 0x23, 0xff}, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Token()).To(Equal("BITFIELDS"))
+		Expect(res.Schema()).To(Equal("flags"))
+		Expect(res.(parsley.LiteralNode).Value()).To(Equal([]uint64{1, 3, 0x123}))
+		Expect(res.ReaderPos()).To(Equal(f.Pos(2)))
+	})
+
+	It("should not match if there is not enough input", func() {
+		p := terminal.BitFields("flags", 4, 12)
+		f, res, err := parse(p, []byte{0xb1}, 0)
+		Expect(res).To(BeNil())
+		Expect(err.Cause()).To(MatchError("was expecting 2-byte bit fields"))
+		Expect(err.Pos()).To(Equal(f.Pos(0)))
+	})
+
+	It("should panic if the total width is not a multiple of 8", func() {
+		Expect(func() { terminal.BitFields("flags", 3, 4) }).To(Panic())
+	})
+
+	It("should panic for an invalid width", func() {
+		Expect(func() { terminal.BitFields("flags", 0, 8) }).To(Panic())
+	})
+})
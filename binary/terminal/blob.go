@@ -0,0 +1,57 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package terminal
+
+import (
+	"fmt"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/binary"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// Blob matches a byte sequence prefixed by its length as a fixed-width unsigned integer of the given size
+// The value is the byte slice without the length prefix.
+func Blob(schema interface{}, lengthSize int, order binary.ByteOrder) parser.Func {
+	if lengthSize < 1 || lengthSize > 8 {
+		panic(fmt.Sprintf("Blob() was called with an invalid length size: %d", lengthSize))
+	}
+
+	return blob(schema, func(br *binary.Reader, pos parsley.Pos) (parsley.Pos, uint64, bool) {
+		return br.ReadUint(pos, lengthSize, order)
+	})
+}
+
+// UvarintBlob matches a byte sequence prefixed by its length as an unsigned varint
+// The value is the byte slice without the length prefix.
+func UvarintBlob(schema interface{}) parser.Func {
+	return blob(schema, func(br *binary.Reader, pos parsley.Pos) (parsley.Pos, uint64, bool) {
+		return br.ReadUvarint(pos)
+	})
+}
+
+func blob(schema interface{}, readLength func(*binary.Reader, parsley.Pos) (parsley.Pos, uint64, bool)) parser.Func {
+	notFoundErr := parsley.NotFoundError("length-prefixed data")
+
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		br := ctx.Reader().(*binary.Reader)
+		dataPos, length, ok := readLength(br, pos)
+		if !ok {
+			return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
+		}
+
+		remaining := br.Remaining(dataPos)
+		if length > uint64(remaining) {
+			return nil, data.EmptyIntSet, parsley.NewErrorf(dataPos, "was expecting %d bytes, found %d", length, remaining)
+		}
+
+		readerPos, value := br.ReadBytes(dataPos, int(length))
+		return ast.NewTerminalNode(schema, "BLOB", value, pos, readerPos), data.EmptyIntSet, nil
+	}).Trace("BLOB")
+}
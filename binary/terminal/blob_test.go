@@ -0,0 +1,66 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package terminal_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/binary"
+	"github.com/conflowio/parsley/binary/terminal"
+	"github.com/conflowio/parsley/parsley"
+)
+
+var _ = Describe("Blob", func() {
+
+	var p = terminal.Blob("blob", 2, binary.LittleEndian)
+
+	It("should match the length-prefixed data", func() {
+		f, res, err := parse(p, []byte{0x03, 0x00, 'a', 'b', 'c', 'd'}, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Token()).To(Equal("BLOB"))
+		Expect(res.Schema()).To(Equal("blob"))
+		Expect(res.(parsley.LiteralNode).Value()).To(Equal([]byte("abc")))
+		Expect(res.Pos()).To(Equal(f.Pos(0)))
+		Expect(res.ReaderPos()).To(Equal(f.Pos(5)))
+	})
+
+	It("should match empty data", func() {
+		f, res, err := parse(p, []byte{0x00, 0x00}, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.(parsley.LiteralNode).Value()).To(Equal([]byte{}))
+		Expect(res.ReaderPos()).To(Equal(f.Pos(2)))
+	})
+
+	It("should not match if the length prefix is missing", func() {
+		f, res, err := parse(p, []byte{0x03}, 0)
+		Expect(res).To(BeNil())
+		Expect(err.Cause()).To(MatchError("was expecting length-prefixed data"))
+		Expect(err.Pos()).To(Equal(f.Pos(0)))
+	})
+
+	It("should return an error if the data is truncated", func() {
+		f, res, err := parse(p, []byte{0x03, 0x00, 'a'}, 0)
+		Expect(res).To(BeNil())
+		Expect(err.Cause()).To(MatchError("was expecting 3 bytes, found 1"))
+		Expect(err.Pos()).To(Equal(f.Pos(2)))
+	})
+
+	It("should panic for an invalid length size", func() {
+		Expect(func() { terminal.Blob("blob", 9, binary.BigEndian) }).To(Panic())
+	})
+})
+
+var _ = Describe("UvarintBlob", func() {
+
+	It("should match the length-prefixed data", func() {
+		f, res, err := parse(terminal.UvarintBlob("blob"), []byte{0x02, 'a', 'b', 'c'}, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.(parsley.LiteralNode).Value()).To(Equal([]byte("ab")))
+		Expect(res.ReaderPos()).To(Equal(f.Pos(3)))
+	})
+})
@@ -0,0 +1,52 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package terminal_test
+
+import (
+	"fmt"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/binary"
+	"github.com/conflowio/parsley/binary/terminal"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// Let's define a parser for a simple file header: magic bytes, a version number and a list of names
+func Example() {
+	header := ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+		children := node.Children()
+		version, _ := parsley.EvaluateNode(userCtx, children[1])
+		names, _ := parsley.EvaluateNode(userCtx, children[2])
+		return fmt.Sprintf("v%d %s", version, names), nil
+	})
+
+	names := ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+		var res []string
+		for _, child := range node.Children() {
+			val, _ := parsley.EvaluateNode(userCtx, child)
+			res = append(res, string(val.([]byte)))
+		}
+		return res, nil
+	})
+
+	p := combinator.SeqOf(
+		terminal.Magic([]byte("HDR")),
+		terminal.Uint("version", 2, binary.LittleEndian),
+		combinator.Many(terminal.UvarintBlob("name")).Bind(names),
+	).Bind(header)
+
+	input := []byte{'H', 'D', 'R', 0x02, 0x00, 0x03, 'f', 'o', 'o', 0x03, 'b', 'a', 'r'}
+	r := binary.NewReader(binary.NewFile("example.bin", input))
+	ctx := parsley.NewContext(parsley.NewFileSet(), r)
+	value, err := parsley.Evaluate(ctx, combinator.Sentence(p))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(value)
+	// Output: v2 [foo bar]
+}
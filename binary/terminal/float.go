@@ -0,0 +1,43 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package terminal
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/binary"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// Float matches an IEEE 754 floating point number of the given size (4 or 8 bytes), the value is a float64
+func Float(schema interface{}, size int, order binary.ByteOrder) parser.Func {
+	if size != 4 && size != 8 {
+		panic(fmt.Sprintf("Float() was called with an invalid size: %d", size))
+	}
+
+	notFoundErr := parsley.NotFoundError(fmt.Sprintf("float%d", size*8))
+
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		br := ctx.Reader().(*binary.Reader)
+		readerPos, bits, ok := br.ReadUint(pos, size, order)
+		if !ok {
+			return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
+		}
+
+		var value float64
+		if size == 4 {
+			value = float64(math.Float32frombits(uint32(bits)))
+		} else {
+			value = math.Float64frombits(bits)
+		}
+		return ast.NewTerminalNode(schema, "FLOAT", value, pos, readerPos), data.EmptyIntSet, nil
+	}).Trace("FLOAT")
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package terminal_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/binary"
+	"github.com/conflowio/parsley/binary/terminal"
+	"github.com/conflowio/parsley/parsley"
+)
+
+var _ = Describe("Float", func() {
+
+	DescribeTable("should match",
+		func(size int, order binary.ByteOrder, input []byte, value float64) {
+			f, res, err := parse(terminal.Float("float", size, order), input, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Token()).To(Equal("FLOAT"))
+			Expect(res.Schema()).To(Equal("float"))
+			Expect(res.(parsley.LiteralNode).Value()).To(Equal(value))
+			Expect(res.ReaderPos()).To(Equal(f.Pos(size)))
+		},
+		Entry("float32 big-endian", 4, binary.BigEndian, []byte{0x3f, 0xc0, 0x00, 0x00}, 1.5),
+		Entry("float32 little-endian", 4, binary.LittleEndian, []byte{0x00, 0x00, 0xc0, 0xbf}, -1.5),
+		Entry("float64 big-endian", 8, binary.BigEndian, []byte{0x40, 0x09, 0x21, 0xfb, 0x54, 0x44, 0x2d, 0x18}, 3.141592653589793),
+	)
+
+	It("should not match if there is not enough input", func() {
+		_, res, err := parse(terminal.Float("float", 8, binary.BigEndian), []byte{0x01, 0x02, 0x03, 0x04}, 0)
+		Expect(res).To(BeNil())
+		Expect(err.Cause()).To(MatchError("was expecting float64"))
+	})
+
+	It("should panic for an invalid size", func() {
+		Expect(func() { terminal.Float("float", 2, binary.BigEndian) }).To(Panic())
+	})
+})
@@ -0,0 +1,52 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package terminal
+
+import (
+	"fmt"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/binary"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// Uint matches a fixed-width unsigned integer of the given size (1-8 bytes), the value is an uint64
+func Uint(schema interface{}, size int, order binary.ByteOrder) parser.Func {
+	if size < 1 || size > 8 {
+		panic(fmt.Sprintf("Uint() was called with an invalid size: %d", size))
+	}
+
+	notFoundErr := parsley.NotFoundError(fmt.Sprintf("uint%d", size*8))
+
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		br := ctx.Reader().(*binary.Reader)
+		if readerPos, value, ok := br.ReadUint(pos, size, order); ok {
+			return ast.NewTerminalNode(schema, "UINT", value, pos, readerPos), data.EmptyIntSet, nil
+		}
+		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
+	}).Trace("UINT")
+}
+
+// Int matches a fixed-width two's complement signed integer of the given size (1-8 bytes), the value is an int64
+func Int(schema interface{}, size int, order binary.ByteOrder) parser.Func {
+	if size < 1 || size > 8 {
+		panic(fmt.Sprintf("Int() was called with an invalid size: %d", size))
+	}
+
+	notFoundErr := parsley.NotFoundError(fmt.Sprintf("int%d", size*8))
+	shift := uint(64 - size*8)
+
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		br := ctx.Reader().(*binary.Reader)
+		if readerPos, value, ok := br.ReadUint(pos, size, order); ok {
+			return ast.NewTerminalNode(schema, "INT", int64(value<<shift)>>shift, pos, readerPos), data.EmptyIntSet, nil
+		}
+		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
+	}).Trace("INT")
+}
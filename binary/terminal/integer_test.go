@@ -0,0 +1,73 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package terminal_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/binary"
+	"github.com/conflowio/parsley/binary/terminal"
+	"github.com/conflowio/parsley/parsley"
+)
+
+var _ = Describe("Uint", func() {
+
+	DescribeTable("should match",
+		func(size int, order binary.ByteOrder, input []byte, value uint64, endPos int) {
+			f, res, err := parse(terminal.Uint("uint", size, order), input, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Token()).To(Equal("UINT"))
+			Expect(res.Schema()).To(Equal("uint"))
+			Expect(res.(parsley.LiteralNode).Value()).To(Equal(value))
+			Expect(res.Pos()).To(Equal(f.Pos(0)))
+			Expect(res.ReaderPos()).To(Equal(f.Pos(endPos)))
+		},
+		Entry("uint8", 1, binary.BigEndian, []byte{0xff, 0x01}, uint64(0xff), 1),
+		Entry("uint16 big-endian", 2, binary.BigEndian, []byte{0x12, 0x34}, uint64(0x1234), 2),
+		Entry("uint16 little-endian", 2, binary.LittleEndian, []byte{0x12, 0x34}, uint64(0x3412), 2),
+		Entry("uint32 big-endian", 4, binary.BigEndian, []byte{0x12, 0x34, 0x56, 0x78}, uint64(0x12345678), 4),
+		Entry("uint64 little-endian", 8, binary.LittleEndian, []byte{1, 2, 3, 4, 5, 6, 7, 8}, uint64(0x0807060504030201), 8),
+	)
+
+	It("should not match if there is not enough input", func() {
+		f, res, err := parse(terminal.Uint("uint", 4, binary.BigEndian), []byte{0x01, 0x02, 0x03}, 0)
+		Expect(res).To(BeNil())
+		Expect(err.Cause()).To(MatchError("was expecting uint32"))
+		Expect(err.Pos()).To(Equal(f.Pos(0)))
+	})
+
+	It("should panic for an invalid size", func() {
+		Expect(func() { terminal.Uint("uint", 0, binary.BigEndian) }).To(Panic())
+	})
+})
+
+var _ = Describe("Int", func() {
+
+	DescribeTable("should match",
+		func(size int, order binary.ByteOrder, input []byte, value int64) {
+			_, res, err := parse(terminal.Int("int", size, order), input, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Token()).To(Equal("INT"))
+			Expect(res.Schema()).To(Equal("int"))
+			Expect(res.(parsley.LiteralNode).Value()).To(Equal(value))
+		},
+		Entry("int8 positive", 1, binary.BigEndian, []byte{0x7f}, int64(127)),
+		Entry("int8 negative", 1, binary.BigEndian, []byte{0xff}, int64(-1)),
+		Entry("int16 big-endian", 2, binary.BigEndian, []byte{0xff, 0xfe}, int64(-2)),
+		Entry("int16 little-endian", 2, binary.LittleEndian, []byte{0x00, 0x80}, int64(-32768)),
+		Entry("int24 big-endian", 3, binary.BigEndian, []byte{0x80, 0x00, 0x00}, int64(-8388608)),
+		Entry("int64 big-endian", 8, binary.BigEndian, []byte{0, 0, 0, 0, 0, 0, 0x01, 0x00}, int64(256)),
+	)
+
+	It("should not match if there is not enough input", func() {
+		_, res, err := parse(terminal.Int("int", 2, binary.BigEndian), []byte{0x01}, 0)
+		Expect(res).To(BeNil())
+		Expect(err.Cause()).To(MatchError("was expecting int16"))
+	})
+})
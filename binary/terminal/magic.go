@@ -0,0 +1,35 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package terminal
+
+import (
+	"fmt"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/binary"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// Magic matches the given byte sequence (e.g. a file signature), the value is the matched byte slice
+func Magic(magic []byte) parser.Func {
+	if len(magic) == 0 {
+		panic("Magic() should not be called with an empty byte slice")
+	}
+
+	magic = append([]byte(nil), magic...)
+	notFoundErr := parsley.NotFoundError(fmt.Sprintf("magic bytes 0x%x", magic))
+
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		br := ctx.Reader().(*binary.Reader)
+		if readerPos, found := br.MatchBytes(pos, magic); found {
+			return ast.NewTerminalNode(nil, "MAGIC", magic, pos, readerPos), data.EmptyIntSet, nil
+		}
+		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
+	}).Trace("MAGIC")
+}
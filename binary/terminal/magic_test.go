@@ -0,0 +1,40 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package terminal_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/binary/terminal"
+	"github.com/conflowio/parsley/parsley"
+)
+
+var _ = Describe("Magic", func() {
+
+	var p = terminal.Magic([]byte{0x89, 'P', 'N', 'G'})
+
+	It("should match the magic bytes", func() {
+		f, res, err := parse(p, []byte{0x89, 'P', 'N', 'G', 0x0d}, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Token()).To(Equal("MAGIC"))
+		Expect(res.Schema()).To(BeNil())
+		Expect(res.(parsley.LiteralNode).Value()).To(Equal([]byte{0x89, 'P', 'N', 'G'}))
+		Expect(res.ReaderPos()).To(Equal(f.Pos(4)))
+	})
+
+	It("should not match different bytes", func() {
+		f, res, err := parse(p, []byte{0x89, 'P', 'N', 'X'}, 0)
+		Expect(res).To(BeNil())
+		Expect(err.Cause()).To(MatchError("was expecting magic bytes 0x89504e47"))
+		Expect(err.Pos()).To(Equal(f.Pos(0)))
+	})
+
+	It("should panic for an empty byte slice", func() {
+		Expect(func() { terminal.Magic(nil) }).To(Panic())
+	})
+})
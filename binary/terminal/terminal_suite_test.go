@@ -0,0 +1,32 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package terminal_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/binary"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parsley"
+)
+
+func TestTerminal(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Binary Terminal Suite")
+}
+
+func parse(p parsley.Parser, input []byte, startPos int) (*binary.File, parsley.Node, parsley.Error) {
+	f := binary.NewFile("testfile", input)
+	fs := parsley.NewFileSet(f)
+	ctx := parsley.NewContext(fs, binary.NewReader(f))
+	res, curtailingParsers, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(startPos))
+	Expect(curtailingParsers).To(Equal(data.EmptyIntSet))
+	return f, res, err
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package terminal
+
+import (
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/binary"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// Uvarint matches an unsigned varint (as encoded by encoding/binary), the value is an uint64
+func Uvarint(schema interface{}) parser.Func {
+	notFoundErr := parsley.NotFoundError("uvarint")
+
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		br := ctx.Reader().(*binary.Reader)
+		if readerPos, value, ok := br.ReadUvarint(pos); ok {
+			return ast.NewTerminalNode(schema, "UVARINT", value, pos, readerPos), data.EmptyIntSet, nil
+		}
+		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
+	}).Trace("UVARINT")
+}
+
+// Varint matches a zig-zag encoded signed varint (as encoded by encoding/binary), the value is an int64
+func Varint(schema interface{}) parser.Func {
+	notFoundErr := parsley.NotFoundError("varint")
+
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		br := ctx.Reader().(*binary.Reader)
+		if readerPos, value, ok := br.ReadVarint(pos); ok {
+			return ast.NewTerminalNode(schema, "VARINT", value, pos, readerPos), data.EmptyIntSet, nil
+		}
+		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
+	}).Trace("VARINT")
+}
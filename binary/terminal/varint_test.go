@@ -0,0 +1,51 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package terminal_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/binary/terminal"
+	"github.com/conflowio/parsley/parsley"
+)
+
+var _ = Describe("Uvarint", func() {
+
+	It("should match an unsigned varint", func() {
+		f, res, err := parse(terminal.Uvarint("uvarint"), []byte{0xac, 0x02, 0x01}, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Token()).To(Equal("UVARINT"))
+		Expect(res.Schema()).To(Equal("uvarint"))
+		Expect(res.(parsley.LiteralNode).Value()).To(Equal(uint64(300)))
+		Expect(res.ReaderPos()).To(Equal(f.Pos(2)))
+	})
+
+	It("should not match a truncated varint", func() {
+		f, res, err := parse(terminal.Uvarint("uvarint"), []byte{0xac}, 0)
+		Expect(res).To(BeNil())
+		Expect(err.Cause()).To(MatchError("was expecting uvarint"))
+		Expect(err.Pos()).To(Equal(f.Pos(0)))
+	})
+})
+
+var _ = Describe("Varint", func() {
+
+	It("should match a signed varint", func() {
+		f, res, err := parse(terminal.Varint("varint"), []byte{0x03}, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Token()).To(Equal("VARINT"))
+		Expect(res.(parsley.LiteralNode).Value()).To(Equal(int64(-2)))
+		Expect(res.ReaderPos()).To(Equal(f.Pos(1)))
+	})
+
+	It("should not match at the end of the input", func() {
+		_, res, err := parse(terminal.Varint("varint"), []byte{}, 0)
+		Expect(res).To(BeNil())
+		Expect(err.Cause()).To(MatchError("was expecting varint"))
+	})
+})
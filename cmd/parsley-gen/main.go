@@ -0,0 +1,86 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// parsley-gen generates Go parser code from a grammar file or from an annotated Go grammar builder
+//
+// It's meant to be used with go generate:
+//
+//	//go:generate go run github.com/conflowio/parsley/cmd/parsley-gen -grammar calc.grammar -start sum -output parser.go
+//	//go:generate go run github.com/conflowio/parsley/cmd/parsley-gen -builder grammar.go -start sum -output parser.go
+//
+// The Go builders are read by grammar.ReadBuilder, if the file contains multiple builders you can select one with -name.
+//
+// The package name is taken from the GOPACKAGE environment variable set by go generate, or you can set it with -package.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/conflowio/parsley/grammar"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "parsley-gen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("parsley-gen", flag.ContinueOnError)
+	grammarFile := flags.String("grammar", "", "the grammar file")
+	builderFile := flags.String("builder", "", "the Go file containing an annotated grammar builder")
+	builderName := flags.String("name", "", "the name of the grammar builder function or variable")
+	start := flags.String("start", "", "the start rule (required)")
+	output := flags.String("output", "", "the output file, the code is written to stdout if empty")
+	packageName := flags.String("package", os.Getenv("GOPACKAGE"), "the package name of the generated file")
+	funcName := flags.String("func", "NewParser", "the name of the generated constructor function")
+	terminals := flags.String("terminals", "", "comma-separated list of the custom terminals passed to the constructor")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if (*grammarFile == "") == (*builderFile == "") || *start == "" {
+		flags.Usage()
+		return fmt.Errorf("the -start flag and either the -grammar or the -builder flag are required")
+	}
+
+	var g *grammar.Grammar
+	var err error
+	if *grammarFile != "" {
+		g, err = grammar.ReadFile(*grammarFile)
+	} else {
+		g, err = grammar.ReadBuilder(*builderFile, *builderName)
+	}
+	if err != nil {
+		return err
+	}
+
+	opts := grammar.GenerateOptions{
+		Package:  *packageName,
+		FuncName: *funcName,
+		Start:    *start,
+	}
+	if *terminals != "" {
+		opts.Terminals = strings.Split(*terminals, ",")
+	}
+
+	code, err := g.Generate(opts)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+
+	return os.WriteFile(*output, code, 0644)
+}
@@ -23,17 +23,24 @@ func Any(parsers ...parsley.Parser) parser.Func {
 		cp := data.EmptyIntSet
 		var res parsley.Node
 		var err parsley.Error
+		ctx.EnterBacktrackPoint(pos)
 		for _, p := range parsers {
 			ctx.RegisterCall()
 			res2, cp2, err2 := p.Parse(ctx, leftRecCtx, pos)
 			cp = cp.Union(cp2)
+			if res2 == nil && parsley.IsCutError(err2) {
+				ctx.ExitBacktrackPoint()
+				return nil, cp, err2
+			}
 			res = ast.AppendNode(res, res2)
+			ctx.RegisterNotFoundError(err2)
 			if err2 != nil && (err == nil || err2.Pos() >= err.Pos()) {
 				if err2.Pos() > pos || !parsley.IsNotFoundError(err2) {
 					err = err2
 				}
 			}
 		}
+		ctx.ExitBacktrackPoint()
 
 		if res == nil {
 			return nil, cp, err
@@ -42,5 +49,5 @@ func Any(parsers ...parsley.Parser) parser.Func {
 		ctx.SetError(err)
 
 		return res, cp, nil
-	})
+	}).Trace("ANY")
 }
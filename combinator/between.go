@@ -0,0 +1,143 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator
+
+import (
+	"fmt"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// BetweenParser matches a parser between an opening and a closing delimiter, see Between
+type BetweenParser struct {
+	open    parsley.Parser
+	p       parsley.Parser
+	close   parsley.Parser
+	closers []parsley.Parser
+	traceID int
+}
+
+// Between matches the parser between the open and close parsers (e.g. brackets) and returns with the inner node only
+// The reader position of the inner node is moved after the closing delimiter.
+// If the closing delimiter is missing at the end of the input then an "unclosed '[' opened at 1:5" error is returned.
+// If the closing delimiter of an enclosing delimiter or one of the closers (see Closers) is found instead, e.g. "{ [ }",
+// then the error will be "unclosed '[' opened at 1:3, found '}'".
+func Between(open parsley.Parser, p parsley.Parser, close parsley.Parser) *BetweenParser {
+	return &BetweenParser{open: open, p: p, close: close, traceID: parsley.NewTraceID()}
+}
+
+// Closers sets the closing delimiters of the other delimited values in the grammar
+// These are reported as mismatched delimiters even if they don't belong to an enclosing delimiter, e.g. "[ }".
+func (b *BetweenParser) Closers(closers ...parsley.Parser) *BetweenParser {
+	b.closers = closers
+	return b
+}
+
+// Parse parses the given input
+func (b *BetweenParser) Parse(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	if ctx.Tracer() == nil {
+		return b.parse(ctx, leftRecCtx, pos)
+	}
+	return parsley.Trace(b.traceID, "BETWEEN", false, parser.Func(b.parse), ctx, leftRecCtx, pos)
+}
+
+func (b *BetweenParser) parse(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	openNode, cp, err := b.open.Parse(ctx, leftRecCtx, pos)
+	if openNode == nil {
+		return nil, cp, err
+	}
+	openNode = firstNode(openNode)
+
+	innerPos := openNode.ReaderPos()
+	if innerPos > pos {
+		leftRecCtx = data.EmptyIntMap
+	}
+
+	delimiter := parsley.Delimiter{Name: fmt.Sprintf("'%s'", openNode.Token()), Pos: openNode.Pos(), Close: b.close}
+	ctx.RegisterCall()
+	ctx.OpenDelimiter(delimiter)
+	res, cp2, err := b.p.Parse(ctx, leftRecCtx, innerPos)
+	ctx.CloseDelimiter()
+	if innerPos == pos {
+		cp = cp.Union(cp2)
+	}
+	if res == nil {
+		return nil, cp, err
+	}
+
+	nodes := nodeList(res)
+	ctx.EnterBacktrackPoint(lowestReaderPos(nodes))
+	defer ctx.ExitBacktrackPoint()
+
+	var result parsley.Node
+	var closeErr parsley.Error
+	for _, node := range nodes {
+		ctx.RegisterCall()
+		closeNode, _, err := b.close.Parse(ctx, data.EmptyIntMap, node.ReaderPos())
+		if closeNode == nil {
+			ctx.RegisterNotFoundError(err)
+			if err != nil && (closeErr == nil || err.Pos() > closeErr.Pos()) {
+				closeErr = err
+			}
+			continue
+		}
+
+		readerPos := firstNode(closeNode).ReaderPos()
+		result = ast.AppendNode(result, ast.SetReaderPos(node, func(parsley.Pos) parsley.Pos { return readerPos }))
+	}
+
+	if result == nil {
+		return nil, cp, b.unclosedError(ctx, delimiter, closeErr)
+	}
+
+	return result, cp, nil
+}
+
+// unclosedError converts the error of the closing delimiter if the input ended or a different delimiter was closed
+// The closing delimiters of the enclosing delimiters are tried first, then the closers of the parser.
+func (b *BetweenParser) unclosedError(ctx *parsley.Context, delimiter parsley.Delimiter, err parsley.Error) parsley.Error {
+	if err == nil || !parsley.IsNotFoundError(err) || parsley.IsCutError(err) {
+		return err
+	}
+
+	openedAt := ctx.FileSet().Position(delimiter.Pos)
+
+	if ctx.Reader().IsEOF(err.Pos()) {
+		return parsley.NewErrorf(err.Pos(), "unclosed %s opened at %s", delimiter.Name, openedAt)
+	}
+
+	notFoundErrs := ctx.NotFoundErrors()
+	defer ctx.SetNotFoundErrors(notFoundErrs)
+
+	enclosing := ctx.OpenDelimiters()
+	candidates := make([]parsley.Parser, 0, len(enclosing)+len(b.closers))
+	for i := len(enclosing) - 1; i >= 0; i-- {
+		candidates = append(candidates, enclosing[i].Close)
+	}
+	candidates = append(candidates, b.closers...)
+
+	for _, close := range candidates {
+		closeNode, _, _ := close.Parse(ctx, data.EmptyIntMap, err.Pos())
+		if closeNode == nil {
+			continue
+		}
+		return parsley.NewErrorf(err.Pos(), "unclosed %s opened at %s, found '%s'", delimiter.Name, openedAt, firstNode(closeNode).Token())
+	}
+
+	return err
+}
+
+// nodeList returns with the alternative results of a parser
+func nodeList(node parsley.Node) ast.NodeList {
+	if nodes, ok := node.(ast.NodeList); ok {
+		return nodes
+	}
+	return ast.NodeList{node}
+}
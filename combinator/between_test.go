@@ -0,0 +1,276 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/ast/interpreter"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/parsley/parsleyfakes"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+// Let's define nested integer lists. The errors for the missing closing brackets point to the opening bracket.
+func ExampleBetween() {
+	var value parser.Func
+	list := combinator.Between(
+		terminal.Rune('['),
+		combinator.SepBy(&value, terminal.Rune(',')).Bind(interpreter.Array()),
+		terminal.Rune(']'),
+	)
+	value = combinator.Choice(terminal.Integer("integer"), list)
+
+	for _, input := range []string{"[1,[2,3]]", "[1,[2,3]"} {
+		f := text.NewFile("example.file", []byte(input))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		value, err := parsley.Evaluate(ctx, combinator.Sentence(&value))
+		fmt.Println(value, err)
+	}
+	// Output:
+	// [1 [2 3]] <nil>
+	// <nil> failed to parse the input: unclosed '[' opened at example.file:1:1 at example.file:1:9
+}
+
+var _ = Describe("Between", func() {
+
+	var (
+		p                       *combinator.BetweenParser
+		open, q, close          *parsleyfakes.FakeParser
+		r                       *parsleyfakes.FakeReader
+		ctx                     *parsley.Context
+		leftRecCtx              data.IntMap
+		pos                     parsley.Pos
+		openCP, qCP             data.IntSet
+		openRes, qRes, closeRes parsley.Node
+		openErr, qErr, closeErr parsley.Error
+		closers                 []parsley.Parser
+		delimiters              []parsley.Delimiter
+		res                     parsley.Node
+		cp                      data.IntSet
+		err                     parsley.Error
+	)
+
+	BeforeEach(func() {
+		position := &parsleyfakes.FakePosition{}
+		position.StringReturns("1:2")
+		f := &parsleyfakes.FakeFile{}
+		f.LenReturns(10)
+		f.PositionReturns(position)
+		r = &parsleyfakes.FakeReader{}
+		ctx = parsley.NewContext(parsley.NewFileSet(f), r)
+
+		open = &parsleyfakes.FakeParser{}
+		q = &parsleyfakes.FakeParser{}
+		close = &parsleyfakes.FakeParser{}
+		leftRecCtx = data.NewIntMap(map[int]int{1: 2})
+		pos = parsley.Pos(2)
+
+		openCP = data.NewIntSet(1)
+		qCP = data.NewIntSet(2)
+		openRes = ast.NewTerminalNode(nil, "(", "(", pos, pos+1)
+		qRes = ast.NewTerminalNode(nil, "a", "a", pos+1, pos+2)
+		closeRes = ast.NewTerminalNode(nil, ")", ")", pos+2, pos+3)
+		openErr = nil
+		qErr = nil
+		closeErr = nil
+		closers = nil
+		delimiters = nil
+	})
+
+	JustBeforeEach(func() {
+		open.ParseReturnsOnCall(0, openRes, openCP, openErr)
+		q.ParseStub = func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+			delimiters = ctx.OpenDelimiters()
+			return qRes, qCP, qErr
+		}
+		close.ParseReturnsOnCall(0, closeRes, data.EmptyIntSet, closeErr)
+
+		p = combinator.Between(open, q, close)
+		if closers != nil {
+			p.Closers(closers...)
+		}
+		res, cp, err = p.Parse(ctx, leftRecCtx, pos)
+	})
+
+	It("should return the inner node with the reader position after the closing delimiter", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Token()).To(Equal("a"))
+		Expect(res.Pos()).To(Equal(pos + 1))
+		Expect(res.ReaderPos()).To(Equal(pos + 3))
+	})
+
+	It("should call the parsers after each other", func() {
+		passedCtx, passedLeftRecCtx, passedPos := open.ParseArgsForCall(0)
+		Expect(passedCtx).To(BeEquivalentTo(ctx))
+		Expect(passedLeftRecCtx).To(BeEquivalentTo(leftRecCtx))
+		Expect(passedPos).To(Equal(pos))
+
+		_, passedLeftRecCtx, passedPos = q.ParseArgsForCall(0)
+		Expect(passedLeftRecCtx).To(BeEquivalentTo(data.EmptyIntMap))
+		Expect(passedPos).To(Equal(pos + 1))
+
+		_, passedLeftRecCtx, passedPos = close.ParseArgsForCall(0)
+		Expect(passedLeftRecCtx).To(BeEquivalentTo(data.EmptyIntMap))
+		Expect(passedPos).To(Equal(pos + 2))
+	})
+
+	It("should only return the curtailing parsers of the opening delimiter", func() {
+		Expect(cp).To(Equal(openCP))
+	})
+
+	It("should register the delimiter while parsing the inner node", func() {
+		Expect(delimiters).To(Equal([]parsley.Delimiter{{Name: "'('", Pos: pos, Close: close}}))
+		Expect(ctx.OpenDelimiters()).To(BeEmpty())
+	})
+
+	Context("when the opening delimiter matches the empty input", func() {
+		BeforeEach(func() {
+			openRes = ast.NewTerminalNode(nil, "(", "", pos, pos)
+			qRes = ast.NewTerminalNode(nil, "a", "a", pos, pos+2)
+		})
+
+		It("should pass the left recursion context to the inner parser", func() {
+			_, passedLeftRecCtx, passedPos := q.ParseArgsForCall(0)
+			Expect(passedLeftRecCtx).To(BeEquivalentTo(leftRecCtx))
+			Expect(passedPos).To(Equal(pos))
+		})
+
+		It("should merge the curtailing parsers", func() {
+			Expect(cp).To(Equal(openCP.Union(qCP)))
+		})
+	})
+
+	Context("when the opening delimiter doesn't match", func() {
+		BeforeEach(func() {
+			openRes = nil
+			openErr = parsley.NewErrorf(pos, "open error")
+		})
+
+		It("should return the error of the opening delimiter", func() {
+			Expect(res).To(BeNil())
+			Expect(cp).To(Equal(openCP))
+			Expect(err).To(Equal(openErr))
+			Expect(q.ParseCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the inner parser doesn't match", func() {
+		BeforeEach(func() {
+			qRes = nil
+			qErr = parsley.NewErrorf(pos+1, "inner error")
+		})
+
+		It("should return the error of the inner parser", func() {
+			Expect(res).To(BeNil())
+			Expect(err).To(Equal(qErr))
+			Expect(close.ParseCallCount()).To(Equal(0))
+			Expect(ctx.OpenDelimiters()).To(BeEmpty())
+		})
+	})
+
+	Context("when the inner parser returns multiple results", func() {
+		BeforeEach(func() {
+			qRes = ast.NodeList{
+				ast.NewTerminalNode(nil, "a", "a", pos+1, pos+2),
+				ast.NewTerminalNode(nil, "b", "b", pos+1, pos+3),
+			}
+		})
+
+		It("should try the closing delimiter after every result", func() {
+			Expect(close.ParseCallCount()).To(Equal(2))
+			_, _, passedPos := close.ParseArgsForCall(1)
+			Expect(passedPos).To(Equal(pos + 3))
+		})
+
+		It("should only return the closed results", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Token()).To(Equal("a"))
+			Expect(res.ReaderPos()).To(Equal(pos + 3))
+		})
+	})
+
+	Context("when the closing delimiter doesn't match", func() {
+		BeforeEach(func() {
+			closeRes = nil
+			closeErr = parsley.NewError(pos+2, parsley.NotFoundError(`")"`))
+		})
+
+		It("should return the error of the closing delimiter", func() {
+			Expect(res).To(BeNil())
+			Expect(err).To(MatchError(`was expecting ")"`))
+			Expect(err.Pos()).To(Equal(pos + 2))
+		})
+
+		Context("when the input ended", func() {
+			BeforeEach(func() {
+				r.IsEOFReturns(true)
+			})
+
+			It("should report the opening delimiter", func() {
+				Expect(err).To(MatchError("unclosed '(' opened at 1:2"))
+				Expect(err.Pos()).To(Equal(pos + 2))
+			})
+		})
+
+		Context("when an enclosing delimiter is closed", func() {
+			var outerClose *parsleyfakes.FakeParser
+
+			BeforeEach(func() {
+				outerClose = &parsleyfakes.FakeParser{}
+				outerClose.ParseReturns(ast.NewTerminalNode(nil, "]", "]", pos+2, pos+3), data.EmptyIntSet, nil)
+				ctx.OpenDelimiter(parsley.Delimiter{Name: "'['", Pos: pos - 1, Close: outerClose})
+			})
+
+			It("should report the mismatched closing delimiter", func() {
+				Expect(err).To(MatchError("unclosed '(' opened at 1:2, found ']'"))
+				Expect(err.Pos()).To(Equal(pos + 2))
+
+				_, passedLeftRecCtx, passedPos := outerClose.ParseArgsForCall(0)
+				Expect(passedLeftRecCtx).To(BeEquivalentTo(data.EmptyIntMap))
+				Expect(passedPos).To(Equal(pos + 2))
+			})
+		})
+
+		Context("when one of the closers is found", func() {
+			BeforeEach(func() {
+				brace := &parsleyfakes.FakeParser{}
+				brace.ParseReturns(ast.NewTerminalNode(nil, "}", "}", pos+2, pos+3), data.EmptyIntSet, nil)
+				closers = []parsley.Parser{&parsleyfakes.FakeParser{}, brace}
+			})
+
+			It("should report the mismatched closing delimiter", func() {
+				Expect(err).To(MatchError("unclosed '(' opened at 1:2, found '}'"))
+				Expect(err.Pos()).To(Equal(pos + 2))
+			})
+
+			It("should keep the not found errors of the closing delimiter", func() {
+				Expect(ctx.NotFoundErrors()).To(Equal(parsley.NotFoundErrors{Pos: pos + 2, Names: []string{`")"`}}))
+			})
+		})
+
+		Context("when it returns a cut error", func() {
+			BeforeEach(func() {
+				closeErr = parsley.NewCutError(parsley.NewErrorf(pos+2, "cut error"))
+				r.IsEOFReturns(true)
+			})
+
+			It("should return the cut error", func() {
+				Expect(parsley.IsCutError(err)).To(BeTrue())
+				Expect(err).To(MatchError("cut error"))
+			})
+		})
+	})
+})
@@ -0,0 +1,144 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator
+
+import (
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// ChainOp matches the operator and returns with a terminal node where the value is the given interpreter
+// It can be used to define the operators for ChainLeft and ChainRight.
+func ChainOp(op parsley.Parser, interpreter parsley.Interpreter) parser.Func {
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		res, cp, err := op.Parse(ctx, leftRecCtx, pos)
+		if res == nil {
+			return nil, cp, err
+		}
+
+		res = firstNode(res)
+		return ast.NewTerminalNode(res.Schema(), res.Token(), interpreter, res.Pos(), res.ReaderPos()), cp, err
+	}).Trace("CHAIN_OP")
+}
+
+// ChainLeft matches one or more operands separated by the operator and groups them from the left:
+// a - b - c = (a - b) - c
+//
+// The input is parsed iteratively, so no left recursion or memoization is needed. The result is the operand itself
+// or nested ast.NonTerminalNodes with the left operand, operator and right operand children. The interpreter of a
+// node is the value of the operator node if it's a parsley.Interpreter (see ChainOp).
+func ChainLeft(operand parsley.Parser, op parsley.Parser) parser.Func {
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		operands, ops, cp, err := parseChain(ctx, leftRecCtx, pos, operand, op)
+		if operands == nil {
+			return nil, cp, err
+		}
+
+		node := operands[0]
+		for i, opNode := range ops {
+			node = newChainNode(node, opNode, operands[i+1])
+		}
+		return node, cp, err
+	}).Trace("CHAIN_LEFT")
+}
+
+// ChainRight matches one or more operands separated by the operator and groups them from the right:
+// a ^ b ^ c = a ^ (b ^ c)
+//
+// The input is parsed iteratively, so no recursion is needed. The result is the operand itself or nested
+// ast.NonTerminalNodes with the left operand, operator and right operand children. The interpreter of a node is the
+// value of the operator node if it's a parsley.Interpreter (see ChainOp).
+func ChainRight(operand parsley.Parser, op parsley.Parser) parser.Func {
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		operands, ops, cp, err := parseChain(ctx, leftRecCtx, pos, operand, op)
+		if operands == nil {
+			return nil, cp, err
+		}
+
+		node := operands[len(operands)-1]
+		for i := len(ops) - 1; i >= 0; i-- {
+			node = newChainNode(operands[i], ops[i], node)
+		}
+		return node, cp, err
+	}).Trace("CHAIN_RIGHT")
+}
+
+// parseChain returns with the operands and the operators between them
+// If an operator matches, but there is no valid operand after it then the error is saved in the context and the chain
+// ends before the operator, except for cut errors which are returned.
+func parseChain(
+	ctx *parsley.Context,
+	leftRecCtx data.IntMap,
+	pos parsley.Pos,
+	operand parsley.Parser,
+	op parsley.Parser,
+) ([]parsley.Node, []parsley.Node, data.IntSet, parsley.Error) {
+	ctx.RegisterCall()
+	first, cp, err := operand.Parse(ctx, leftRecCtx, pos)
+	if first == nil {
+		return nil, nil, cp, err
+	}
+	ctx.SetError(err)
+
+	parseAt := func(p parsley.Parser, pos parsley.Pos) (parsley.Node, parsley.Error) {
+		ctx.RegisterCall()
+		node, _, err := p.Parse(ctx, data.EmptyIntMap, pos)
+		ctx.RegisterNotFoundError(err)
+		if node == nil {
+			return nil, err
+		}
+		ctx.SetError(err)
+		return firstNode(node), nil
+	}
+
+	operands := []parsley.Node{firstNode(first)}
+	var ops []parsley.Node
+	for {
+		// The chain ends after the last operand if the next operator or operand is missing
+		readerPos := operands[len(operands)-1].ReaderPos()
+		ctx.EnterBacktrackPoint(readerPos)
+		opNode, err := parseAt(op, readerPos)
+		if opNode == nil {
+			ctx.ExitBacktrackPoint()
+			if parsley.IsCutError(err) {
+				return nil, nil, cp, err
+			}
+			return operands, ops, cp, nil
+		}
+
+		right, err := parseAt(operand, opNode.ReaderPos())
+		ctx.ExitBacktrackPoint()
+		if right == nil {
+			if parsley.IsCutError(err) {
+				return nil, nil, cp, err
+			}
+			ctx.SetError(err)
+			return operands, ops, cp, nil
+		}
+
+		ops = append(ops, opNode)
+		operands = append(operands, right)
+	}
+}
+
+func newChainNode(left parsley.Node, opNode parsley.Node, right parsley.Node) parsley.Node {
+	var interpreter parsley.Interpreter
+	if literal, ok := opNode.(parsley.LiteralNode); ok {
+		interpreter, _ = literal.Value().(parsley.Interpreter)
+	}
+	return ast.NewNonTerminalNode(TokenInfixOp, []parsley.Node{left, opNode, right}, interpreter)
+}
+
+// firstNode returns with the first result if the parser returned multiple results
+func firstNode(node parsley.Node) parsley.Node {
+	if nodes, ok := node.(ast.NodeList); ok {
+		return nodes[0]
+	}
+	return node
+}
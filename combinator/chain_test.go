@@ -0,0 +1,355 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator_test
+
+import (
+	"fmt"
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/parsley/parsleyfakes"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+func binaryOp(f func(a, b int64) int64) ast.InterpreterFunc {
+	return ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+		children := node.Children()
+		a, err := parsley.EvaluateNode(userCtx, children[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := parsley.EvaluateNode(userCtx, children[2])
+		if err != nil {
+			return nil, err
+		}
+		return f(a.(int64), b.(int64)), nil
+	})
+}
+
+// Let's define a calculator with subtraction (left-associative) and exponentiation (right-associative)
+func ExampleChainLeft() {
+	integer := text.LeftTrim(terminal.Integer("integer"), text.WsSpaces)
+	pow := combinator.ChainRight(
+		integer,
+		combinator.ChainOp(text.LeftTrim(terminal.Op("^"), text.WsSpaces), binaryOp(func(a, b int64) int64 {
+			return int64(math.Pow(float64(a), float64(b)))
+		})),
+	)
+	sub := combinator.ChainLeft(
+		pow,
+		combinator.ChainOp(text.LeftTrim(terminal.Op("-"), text.WsSpaces), binaryOp(func(a, b int64) int64 {
+			return a - b
+		})),
+	)
+
+	for _, input := range []string{"10 - 3 - 2", "2 ^ 3 ^ 2", "100 - 2 ^ 3 ^ 2 - 1"} {
+		f := text.NewFile("example.file", []byte(input))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		value, err := parsley.Evaluate(ctx, combinator.Sentence(sub))
+		fmt.Println(value, err)
+	}
+	// Output:
+	// 5 <nil>
+	// 512 <nil>
+	// -413 <nil>
+}
+
+var _ = Describe("ChainOp", func() {
+
+	var (
+		op          *parsleyfakes.FakeParser
+		interpreter *parsleyfakes.FakeInterpreter
+		ctx         *parsley.Context
+		leftRecCtx  data.IntMap
+		pos         parsley.Pos
+		opRes       parsley.Node
+		opCP        data.IntSet
+		opErr       parsley.Error
+		res         parsley.Node
+		cp          data.IntSet
+		err         parsley.Error
+	)
+
+	BeforeEach(func() {
+		ctx = parsley.NewContext(parsley.NewFileSet(), &parsleyfakes.FakeReader{})
+		op = &parsleyfakes.FakeParser{}
+		interpreter = &parsleyfakes.FakeInterpreter{}
+		leftRecCtx = data.NewIntMap(map[int]int{1: 2})
+		pos = parsley.Pos(1)
+		opRes = ast.NewTerminalNode("schema", "-", "-", pos, pos+1)
+		opCP = data.NewIntSet(1)
+		opErr = nil
+	})
+
+	JustBeforeEach(func() {
+		op.ParseReturnsOnCall(0, opRes, opCP, opErr)
+		res, cp, err = combinator.ChainOp(op, interpreter).Parse(ctx, leftRecCtx, pos)
+	})
+
+	It("should return the operator with the interpreter as value", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cp).To(Equal(opCP))
+		Expect(res).To(Equal(ast.NewTerminalNode("schema", "-", interpreter, pos, pos+1)))
+
+		passedCtx, passedLeftRecCtx, passedPos := op.ParseArgsForCall(0)
+		Expect(passedCtx).To(BeEquivalentTo(ctx))
+		Expect(passedLeftRecCtx).To(BeEquivalentTo(leftRecCtx))
+		Expect(passedPos).To(Equal(pos))
+	})
+
+	Context("when a tracer is set", func() {
+		var tracer *memoTracer
+
+		BeforeEach(func() {
+			tracer = &memoTracer{}
+			ctx.SetTracer(tracer)
+		})
+
+		It("should report the call", func() {
+			Expect(tracer.results["CHAIN_OP"]).To(HaveLen(1))
+			Expect(tracer.results["CHAIN_OP"][0].Node).To(Equal(res))
+		})
+	})
+
+	Context("when the operator returns multiple results", func() {
+		BeforeEach(func() {
+			opRes = ast.NodeList{
+				ast.NewTerminalNode("schema", "-", "-", pos, pos+1),
+				ast.NewTerminalNode("schema", "--", "--", pos, pos+2),
+			}
+		})
+
+		It("should use the first result", func() {
+			Expect(res).To(Equal(ast.NewTerminalNode("schema", "-", interpreter, pos, pos+1)))
+		})
+	})
+
+	Context("when the operator doesn't match", func() {
+		BeforeEach(func() {
+			opRes = nil
+			opErr = parsley.NewErrorf(pos, "some error")
+		})
+
+		It("should return the error", func() {
+			Expect(res).To(BeNil())
+			Expect(cp).To(Equal(opCP))
+			Expect(err).To(Equal(opErr))
+		})
+	})
+})
+
+var _ = Describe("Chain", func() {
+
+	var (
+		operand, op *parsleyfakes.FakeParser
+		ctx         *parsley.Context
+		leftRecCtx  data.IntMap
+		pos         parsley.Pos
+		a, b, c     parsley.Node
+		minus       []parsley.Node
+		opNotFound  parsley.Error
+	)
+
+	BeforeEach(func() {
+		ctx = parsley.NewContext(parsley.NewFileSet(), &parsleyfakes.FakeReader{})
+		operand = &parsleyfakes.FakeParser{}
+		op = &parsleyfakes.FakeParser{}
+		leftRecCtx = data.NewIntMap(map[int]int{1: 2})
+		pos = parsley.Pos(1)
+
+		// a-b-c
+		a = ast.NewTerminalNode(nil, "LETTER", "a", pos, pos+1)
+		b = ast.NewTerminalNode(nil, "LETTER", "b", pos+2, pos+3)
+		c = ast.NewTerminalNode(nil, "LETTER", "c", pos+4, pos+5)
+		minus = []parsley.Node{
+			ast.NewTerminalNode(nil, "-", "-", pos+1, pos+2),
+			ast.NewTerminalNode(nil, "-", "-", pos+3, pos+4),
+		}
+		opNotFound = parsley.NewError(pos+5, parsley.NotFoundError(`"-"`))
+
+		operand.ParseReturnsOnCall(0, a, data.NewIntSet(1), nil)
+		operand.ParseReturnsOnCall(1, b, data.NewIntSet(2), nil)
+		operand.ParseReturnsOnCall(2, c, data.NewIntSet(3), nil)
+		op.ParseReturnsOnCall(0, minus[0], data.NewIntSet(4), nil)
+		op.ParseReturnsOnCall(1, minus[1], data.NewIntSet(5), nil)
+		op.ParseReturnsOnCall(2, nil, data.NewIntSet(6), opNotFound)
+	})
+
+	Describe("ChainLeft", func() {
+		var (
+			res parsley.Node
+			cp  data.IntSet
+			err parsley.Error
+		)
+
+		JustBeforeEach(func() {
+			res, cp, err = combinator.ChainLeft(operand, op).Parse(ctx, leftRecCtx, pos)
+		})
+
+		It("should nest the nodes from the left", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Token()).To(Equal(combinator.TokenInfixOp))
+			Expect(res.Pos()).To(Equal(pos))
+			Expect(res.ReaderPos()).To(Equal(pos + 5))
+
+			children := res.(parsley.NonTerminalNode).Children()
+			Expect(children[0].Token()).To(Equal(combinator.TokenInfixOp))
+			Expect(children[0].(parsley.NonTerminalNode).Children()).To(Equal([]parsley.Node{a, minus[0], b}))
+			Expect(children[1]).To(Equal(minus[1]))
+			Expect(children[2]).To(Equal(c))
+		})
+
+		It("should call the parsers after each other", func() {
+			passedCtx, passedLeftRecCtx, passedPos := operand.ParseArgsForCall(0)
+			Expect(passedCtx).To(BeEquivalentTo(ctx))
+			Expect(passedLeftRecCtx).To(BeEquivalentTo(leftRecCtx))
+			Expect(passedPos).To(Equal(pos))
+
+			for i := 0; i < 3; i++ {
+				_, passedLeftRecCtx, passedPos = op.ParseArgsForCall(i)
+				Expect(passedLeftRecCtx).To(BeEquivalentTo(data.EmptyIntMap))
+				Expect(passedPos).To(Equal(pos + parsley.Pos(2*i+1)))
+			}
+
+			for i := 1; i < 3; i++ {
+				_, passedLeftRecCtx, passedPos = operand.ParseArgsForCall(i)
+				Expect(passedLeftRecCtx).To(BeEquivalentTo(data.EmptyIntMap))
+				Expect(passedPos).To(Equal(pos + parsley.Pos(2*i)))
+			}
+		})
+
+		It("should only return the curtailing parsers of the first operand", func() {
+			Expect(cp).To(Equal(data.NewIntSet(1)))
+		})
+
+		It("should register the error of the missing operator", func() {
+			Expect(ctx.NotFoundErrors()).To(Equal(parsley.NotFoundErrors{Pos: pos + 5, Names: []string{`"-"`}}))
+		})
+
+		Context("when there is no operator", func() {
+			BeforeEach(func() {
+				op.ParseReturnsOnCall(0, nil, data.EmptyIntSet, parsley.NewError(pos+1, parsley.NotFoundError(`"-"`)))
+			})
+
+			It("should return the operand", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res).To(Equal(a))
+				Expect(ctx.NotFoundErrors()).To(Equal(parsley.NotFoundErrors{Pos: pos + 1, Names: []string{`"-"`}}))
+			})
+		})
+
+		Context("when the first operand doesn't match", func() {
+			var operandErr parsley.Error
+
+			BeforeEach(func() {
+				operandErr = parsley.NewError(pos, parsley.NotFoundError("letter"))
+				operand.ParseReturnsOnCall(0, nil, data.NewIntSet(1), operandErr)
+			})
+
+			It("should return the error of the operand", func() {
+				Expect(res).To(BeNil())
+				Expect(cp).To(Equal(data.NewIntSet(1)))
+				Expect(err).To(Equal(operandErr))
+				Expect(op.ParseCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the operand after an operator is missing", func() {
+			var operandErr parsley.Error
+
+			BeforeEach(func() {
+				operandErr = parsley.NewError(pos+4, parsley.NotFoundError("letter"))
+				operand.ParseReturnsOnCall(2, nil, data.EmptyIntSet, operandErr)
+			})
+
+			It("should stop before the operator", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.ReaderPos()).To(Equal(pos + 3))
+				Expect(ctx.Error()).To(Equal(operandErr))
+			})
+		})
+
+		Context("when the operator returns a cut error", func() {
+			BeforeEach(func() {
+				op.ParseReturnsOnCall(1, nil, data.EmptyIntSet, parsley.NewCutError(parsley.NewErrorf(pos+4, "cut error")))
+			})
+
+			It("should return the cut error", func() {
+				Expect(res).To(BeNil())
+				Expect(parsley.IsCutError(err)).To(BeTrue())
+			})
+		})
+
+		Context("when the operand after an operator returns a cut error", func() {
+			BeforeEach(func() {
+				operand.ParseReturnsOnCall(1, nil, data.EmptyIntSet, parsley.NewCutError(parsley.NewErrorf(pos+2, "cut error")))
+			})
+
+			It("should return the cut error", func() {
+				Expect(res).To(BeNil())
+				Expect(parsley.IsCutError(err)).To(BeTrue())
+			})
+		})
+
+		Context("when the operator has an interpreter", func() {
+			var interpreter *parsleyfakes.FakeInterpreter
+
+			BeforeEach(func() {
+				interpreter = &parsleyfakes.FakeInterpreter{}
+				minus[0] = ast.NewTerminalNode(nil, "-", interpreter, pos+1, pos+2)
+				op.ParseReturnsOnCall(0, minus[0], data.EmptyIntSet, nil)
+			})
+
+			It("should use it for the node", func() {
+				_, _ = parsley.EvaluateNode(nil, res.(parsley.NonTerminalNode).Children()[0])
+				Expect(interpreter.EvalCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the input is long", func() {
+			BeforeEach(func() {
+				operand.ParseStub = func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+					return ast.NewTerminalNode(nil, "LETTER", "a", pos, pos+1), data.EmptyIntSet, nil
+				}
+				op.ParseStub = func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+					if op.ParseCallCount() > 1000 {
+						return nil, data.EmptyIntSet, nil
+					}
+					return ast.NewTerminalNode(nil, "-", "-", pos, pos+1), data.EmptyIntSet, nil
+				}
+			})
+
+			It("should parse it without recursion", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.ReaderPos()).To(Equal(pos + 2001))
+				Expect(operand.ParseCallCount()).To(Equal(1001))
+			})
+		})
+	})
+
+	Describe("ChainRight", func() {
+		It("should nest the nodes from the right", func() {
+			res, _, err := combinator.ChainRight(operand, op).Parse(ctx, leftRecCtx, pos)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Pos()).To(Equal(pos))
+			Expect(res.ReaderPos()).To(Equal(pos + 5))
+
+			children := res.(parsley.NonTerminalNode).Children()
+			Expect(children[0]).To(Equal(a))
+			Expect(children[1]).To(Equal(minus[0]))
+			Expect(children[2].Token()).To(Equal(combinator.TokenInfixOp))
+			Expect(children[2].(parsley.NonTerminalNode).Children()).To(Equal([]parsley.Node{b, minus[1], c}))
+		})
+	})
+})
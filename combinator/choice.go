@@ -21,10 +21,17 @@ func Choice(parsers ...parsley.Parser) parser.Func {
 	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
 		cp := data.EmptyIntSet
 		var err parsley.Error
+		ctx.EnterBacktrackPoint(pos)
 		for _, p := range parsers {
 			ctx.RegisterCall()
 			node, cp2, err2 := p.Parse(ctx, leftRecCtx, pos)
 			cp = cp.Union(cp2)
+			ctx.RegisterNotFoundError(err2)
+
+			if node == nil && parsley.IsCutError(err2) {
+				ctx.ExitBacktrackPoint()
+				return nil, cp, err2
+			}
 
 			if err2 != nil && (err == nil || err2.Pos() >= err.Pos()) {
 				if err2.Pos() > pos || !parsley.IsNotFoundError(err2) {
@@ -32,11 +39,13 @@ func Choice(parsers ...parsley.Parser) parser.Func {
 				}
 			}
 			if node != nil {
+				ctx.ExitBacktrackPoint()
 				ctx.SetError(err)
 				return node, cp, nil
 			}
 		}
+		ctx.ExitBacktrackPoint()
 
 		return nil, cp, err
-	})
+	}).Trace("CHOICE")
 }
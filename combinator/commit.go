@@ -0,0 +1,28 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator
+
+import (
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// Commit applies the parser and if it matches then the parsing won't go back before the end of the match
+// The cached results are deleted and a streamed input can be discarded before the end of the match (see
+// parsley.Context.Commit), so it should only be used where no other parser would read the same input again,
+// e.g. for the items in Many(Commit(line)).
+// A streamed input (see text.NewStreamFile) is only discarded by Commit, without it the whole input is kept in memory.
+func Commit(p parsley.Parser) parser.Func {
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		res, cp, err := p.Parse(ctx, leftRecCtx, pos)
+		if res != nil {
+			ctx.Commit(res.ReaderPos())
+		}
+		return res, cp, err
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+// Let's define a parser which sums the integers on each line of a streamed input.
+// The lines are committed, so the reader can discard the input which was already processed.
+func ExampleCommit() {
+	sum := ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+		var res int64
+		for _, node := range node.Children() {
+			val, _ := parsley.EvaluateNode(userCtx, node.(parsley.NonTerminalNode).Children()[0])
+			res += val.(int64)
+		}
+		return res, nil
+	})
+
+	line := combinator.SeqOf(terminal.Integer("int64"), terminal.Rune('\n'))
+	p := combinator.Many(combinator.Commit(line)).Bind(sum)
+
+	f := text.NewStreamFile("stdin", strings.NewReader("1\n2\n3\n"))
+	ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+	value, err := parsley.Evaluate(ctx, combinator.Sentence(p))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%T %v\n", value, value)
+	// Output: int64 6
+}
+
+var _ = Describe("Commit", func() {
+	var (
+		ctx      *parsley.Context
+		f        *text.File
+		p        parsley.Parser
+		res      parsley.Node
+		parseErr parsley.Error
+	)
+
+	BeforeEach(func() {
+		f = text.NewFile("test", []byte("ab"))
+		ctx = parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		ctx.ResultCache().Save(1, f.Pos(0), &parsley.Result{})
+		ctx.ResultCache().Save(1, f.Pos(1), &parsley.Result{})
+		p = nil
+	})
+
+	JustBeforeEach(func() {
+		res, _, parseErr = combinator.Commit(p).Parse(ctx, data.EmptyIntMap, f.Pos(0))
+	})
+
+	Context("when the parser matches", func() {
+		BeforeEach(func() {
+			p = terminal.Rune('a')
+		})
+
+		It("should return the result", func() {
+			Expect(parseErr).ToNot(HaveOccurred())
+			Expect(res.ReaderPos()).To(Equal(f.Pos(1)))
+		})
+
+		It("should commit the context to the end of the match", func() {
+			Expect(ctx.CommittedPos()).To(Equal(f.Pos(1)))
+			_, found := ctx.ResultCache().Get(1, f.Pos(0), data.EmptyIntMap)
+			Expect(found).To(BeFalse())
+			_, found = ctx.ResultCache().Get(1, f.Pos(1), data.EmptyIntMap)
+			Expect(found).To(BeTrue())
+		})
+	})
+
+	Context("when the parser doesn't match", func() {
+		BeforeEach(func() {
+			p = parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+				return nil, data.EmptyIntSet, parsley.NewErrorf(pos, "some error")
+			})
+		})
+
+		It("should return the error and not commit", func() {
+			Expect(res).To(BeNil())
+			Expect(parseErr).To(MatchError("some error"))
+			Expect(ctx.CommittedPos()).To(Equal(parsley.Pos(0)))
+		})
+	})
+})
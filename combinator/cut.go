@@ -0,0 +1,30 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator
+
+import (
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parsley"
+)
+
+type cut struct{}
+
+// Cut returns with a marker parser which stops backtracking in a sequence
+// It matches the empty input. If any parser after the cut fails then the sequence returns with a cut error (see
+// parsley.NewCutError), so the enclosing Choice, Any, Optional and sequences won't try any other alternatives and
+// the error is reported at the position where the parsing actually failed. With the CachePolicy.EvictBacktracked
+// cache policy the cached results before the cut can be evicted (see parsley.Context.Cut).
+func Cut() parsley.Parser {
+	return cut{}
+}
+
+// Parse returns with an empty node
+func (c cut) Parse(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	ctx.Cut(pos)
+	return ast.EmptyNode(pos), data.EmptyIntSet, nil
+}
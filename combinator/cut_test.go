@@ -0,0 +1,138 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+// Let's define a list of integers or lists. After the opening bracket we know that we are parsing a list, so if the
+// list is invalid then the error should be reported there and no other alternatives should be tried.
+func ExampleCut() {
+	var value parser.Func
+	list := combinator.SeqOf(
+		terminal.Rune('['),
+		combinator.Cut(),
+		combinator.SepBy(&value, terminal.Rune(',')),
+		terminal.Rune(']'),
+	)
+	value = combinator.Choice(list, terminal.Integer("integer"), terminal.Regexp(nil, "ANY", "anything", `\[.*`, 0))
+
+	f := text.NewFile("example.file", []byte("[1,[2 3]]"))
+	ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+	_, err := parsley.Parse(ctx, combinator.Sentence(&value))
+	fmt.Println(err)
+	// Output: failed to parse the input: was expecting "," or "]", found "3" at example.file:1:6
+}
+
+var _ = Describe("Cut", func() {
+	var (
+		f   *text.File
+		ctx *parsley.Context
+		a   parsley.Parser
+		b   parsley.Parser
+		c   parsley.Parser
+	)
+
+	BeforeEach(func() {
+		f = text.NewFile("testfile", []byte("ac"))
+		ctx = parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		a = terminal.Rune('a')
+		b = terminal.Rune('b')
+		c = terminal.Rune('c')
+	})
+
+	It("should match the empty input", func() {
+		node, cp, err := combinator.Cut().Parse(ctx, data.EmptyIntMap, f.Pos(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cp).To(Equal(data.EmptyIntSet))
+		Expect(node).To(Equal(ast.EmptyNode(f.Pos(1))))
+	})
+
+	It("should return a cut error if a parser fails after the cut", func() {
+		node, _, err := combinator.SeqOf(a, combinator.Cut(), b).Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(node).To(BeNil())
+		Expect(parsley.IsCutError(err)).To(BeTrue())
+		Expect(err).To(MatchError(`was expecting "b"`))
+		Expect(err.Pos()).To(Equal(f.Pos(1)))
+	})
+
+	It("should return a normal error if a parser fails before the cut", func() {
+		_, _, err := combinator.SeqOf(b, combinator.Cut(), c).Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(err).To(HaveOccurred())
+		Expect(parsley.IsCutError(err)).To(BeFalse())
+	})
+
+	It("should not override the cut error with the sequence name", func() {
+		p := combinator.SeqOf(combinator.Cut(), b).Name("foo")
+		_, _, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(err).To(MatchError(`was expecting "b"`))
+	})
+
+	It("should stop Choice from trying the other alternatives", func() {
+		p := combinator.Choice(combinator.SeqOf(a, combinator.Cut(), b), combinator.SeqOf(a, c))
+		node, _, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(node).To(BeNil())
+		Expect(parsley.IsCutError(err)).To(BeTrue())
+	})
+
+	It("should not affect Choice without a cut", func() {
+		p := combinator.Choice(combinator.SeqOf(a, b), combinator.SeqOf(a, c))
+		node, _, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(node).ToNot(BeNil())
+	})
+
+	It("should stop Any from trying the other alternatives", func() {
+		p := combinator.Any(combinator.SeqOf(a, combinator.Cut(), b), combinator.SeqOf(a, c))
+		node, _, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(node).To(BeNil())
+		Expect(parsley.IsCutError(err)).To(BeTrue())
+	})
+
+	It("should be returned by Optional", func() {
+		p := combinator.Optional(combinator.SeqOf(a, combinator.Cut(), b))
+		node, _, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(node).To(BeNil())
+		Expect(parsley.IsCutError(err)).To(BeTrue())
+	})
+
+	It("should be returned by the enclosing sequences", func() {
+		p := combinator.SeqOf(combinator.Many(combinator.SeqOf(a, combinator.Cut(), b)), c)
+		node, _, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(node).To(BeNil())
+		Expect(parsley.IsCutError(err)).To(BeTrue())
+		Expect(err.Pos()).To(Equal(f.Pos(1)))
+	})
+
+	It("should not return a cut error if the sequence allows the shorter match", func() {
+		node, _, err := combinator.SeqTry(a, combinator.Cut(), b).Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(node.ReaderPos()).To(Equal(f.Pos(1)))
+	})
+
+	It("should not return a cut error if a curtailed parser fails", func() {
+		curtailed := parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+			return nil, data.NewIntSet(1), nil
+		})
+		node, cp, err := combinator.SeqOf(a, combinator.Cut(), curtailed).Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(node).To(BeNil())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cp).To(Equal(data.EmptyIntSet))
+	})
+})
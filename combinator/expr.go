@@ -0,0 +1,396 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator
+
+import (
+	"math"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// Node tokens used by the Expr combinator
+const (
+	TokenPrefixOp  = "PREFIX_OP"
+	TokenInfixOp   = "INFIX_OP"
+	TokenPostfixOp = "POSTFIX_OP"
+	TokenTernaryOp = "TERNARY_OP"
+	TokenCall      = "CALL"
+	TokenIndex     = "INDEX"
+)
+
+// Associativity defines how infix operators with the same precedence are grouped
+type Associativity int
+
+// Associativity values
+const (
+	// LeftAssoc groups the operators from the left: a - b - c = (a - b) - c
+	LeftAssoc Associativity = iota
+	// RightAssoc groups the operators from the right: a ^ b ^ c = a ^ (b ^ c)
+	RightAssoc
+	// NonAssoc doesn't allow to chain the operators: a < b < c is invalid
+	NonAssoc
+)
+
+type exprOpKind int
+
+const (
+	prefixOp exprOpKind = iota
+	infixOp
+	postfixOp
+	ternaryOp
+	callOp
+	indexOp
+)
+
+type exprOperator struct {
+	kind        exprOpKind
+	op          parsley.Parser
+	sep         parsley.Parser
+	close       parsley.Parser
+	precedence  int
+	assoc       Associativity
+	interpreter parsley.Interpreter
+}
+
+// Expression is an operator-precedence (Pratt) expression parser
+//
+// Operators with a higher precedence bind more tightly. If multiple operators can match at the same position
+// (e.g. "<" and "<=") then the operators are tried in the order they were defined, so the longer ones should come
+// first. The operator parsers should skip the whitespaces if needed (see text.LeftTrim).
+//
+// The result nodes are ast.NonTerminalNodes with the operator's interpreter and with the following children:
+//   - prefix: operator, operand
+//   - infix: left operand, operator, right operand
+//   - postfix: operand, operator
+//   - ternary: condition, first operator, first operand, second operator, second operand
+//   - call: callee, opening node, arguments separated by the separator nodes, closing node
+//   - index: operand, opening node, index, closing node
+type Expression struct {
+	operand   parsley.Parser
+	prefix    []*exprOperator
+	postfix   []*exprOperator
+	customErr error
+}
+
+// Expr creates a new expression parser with the given operand parser
+// The operand parser should also match the parenthesised expressions if needed.
+func Expr(operand parsley.Parser) *Expression {
+	if operand == nil {
+		panic("operand parser must not be nil")
+	}
+	return &Expression{operand: operand}
+}
+
+// Name overrides the returned error if its position is the same as the reader's position
+// The error will be: "was expecting <name>"
+func (e *Expression) Name(name string) *Expression {
+	e.customErr = parsley.NotFoundError(name)
+	return e
+}
+
+// Prefix adds a prefix operator, e.g. -a or !a
+func (e *Expression) Prefix(op parsley.Parser, precedence int, interpreter parsley.Interpreter) *Expression {
+	e.prefix = append(e.prefix, &exprOperator{
+		kind: prefixOp, op: op, precedence: precedence, interpreter: interpreter,
+	})
+	return e
+}
+
+// Infix adds an infix operator, e.g. a + b
+func (e *Expression) Infix(op parsley.Parser, precedence int, assoc Associativity, interpreter parsley.Interpreter) *Expression {
+	e.postfix = append(e.postfix, &exprOperator{
+		kind: infixOp, op: op, precedence: precedence, assoc: assoc, interpreter: interpreter,
+	})
+	return e
+}
+
+// Postfix adds a postfix operator, e.g. a++
+func (e *Expression) Postfix(op parsley.Parser, precedence int, interpreter parsley.Interpreter) *Expression {
+	e.postfix = append(e.postfix, &exprOperator{
+		kind: postfixOp, op: op, precedence: precedence, interpreter: interpreter,
+	})
+	return e
+}
+
+// Ternary adds a right-associative ternary operator, e.g. a ? b : c
+// The middle operand can be any expression.
+func (e *Expression) Ternary(op1 parsley.Parser, op2 parsley.Parser, precedence int, interpreter parsley.Interpreter) *Expression {
+	e.postfix = append(e.postfix, &exprOperator{
+		kind: ternaryOp, op: op1, sep: op2, precedence: precedence, assoc: RightAssoc, interpreter: interpreter,
+	})
+	return e
+}
+
+// Call adds a function call postfix operator with a separated argument list, e.g. f(a, b)
+func (e *Expression) Call(open parsley.Parser, sep parsley.Parser, close parsley.Parser, precedence int, interpreter parsley.Interpreter) *Expression {
+	e.postfix = append(e.postfix, &exprOperator{
+		kind: callOp, op: open, sep: sep, close: close, precedence: precedence, interpreter: interpreter,
+	})
+	return e
+}
+
+// Index adds an index postfix operator, e.g. a[i]
+func (e *Expression) Index(open parsley.Parser, close parsley.Parser, precedence int, interpreter parsley.Interpreter) *Expression {
+	e.postfix = append(e.postfix, &exprOperator{
+		kind: indexOp, op: open, close: close, precedence: precedence, interpreter: interpreter,
+	})
+	return e
+}
+
+// Parse parses the given input
+func (e *Expression) Parse(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	p := &expression{
+		Expression:        e,
+		ctx:               ctx,
+		pos:               pos,
+		curtailingParsers: data.EmptyIntSet,
+	}
+
+	notFoundErrs := ctx.NotFoundErrors()
+	res, err, _ := p.parse(leftRecCtx, pos, math.MinInt32)
+	if err != nil && e.customErr != nil && err.Pos() == pos && parsley.IsNotFoundError(err) && !parsley.IsCutError(err) {
+		err = parsley.NewError(pos, e.customErr)
+		ctx.OverrideNotFoundErrors(notFoundErrs, err)
+	}
+
+	return res, p.curtailingParsers, err
+}
+
+type expression struct {
+	*Expression
+	ctx               *parsley.Context
+	pos               parsley.Pos
+	curtailingParsers data.IntSet
+}
+
+// parse parses an expression where all the operators have at least the given precedence
+// If there is no operand at all then it also returns with the position where the operand was expected.
+func (p *expression) parse(leftRecCtx data.IntMap, pos parsley.Pos, minPrecedence int) (parsley.Node, parsley.Error, parsley.Pos) {
+	left, err, missingPos := p.parseOperand(leftRecCtx, pos)
+	if left == nil {
+		return nil, err, missingPos
+	}
+
+	nonAssocPrecedence := math.MinInt32
+	for {
+		node, op := p.parsePostfix(left, minPrecedence, nonAssocPrecedence)
+		if node == nil {
+			return left, nil, parsley.NilPos
+		}
+
+		nonAssocPrecedence = math.MinInt32
+		if op.kind == infixOp && op.assoc == NonAssoc {
+			nonAssocPrecedence = op.precedence
+		}
+		left = node
+	}
+}
+
+// parseOperand parses an operand with optional prefix operators
+func (p *expression) parseOperand(leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, parsley.Error, parsley.Pos) {
+	var err parsley.Error
+	missingPos := pos
+	p.ctx.EnterBacktrackPoint(pos)
+	for _, op := range p.prefix {
+		opNode, opErr := p.parseAt(op.op, leftRecCtx, pos)
+		if opNode == nil {
+			if opErr != nil && parsley.IsNotFoundError(opErr) && (missingPos == pos || opErr.Pos() < missingPos) {
+				missingPos = opErr.Pos()
+			}
+			err = p.furthestErr(err, opErr)
+			continue
+		}
+
+		operand, operandErr := p.parseAfter(opNode, op.precedence)
+		if operand != nil {
+			p.ctx.ExitBacktrackPoint()
+			return ast.NewNonTerminalNode(TokenPrefixOp, []parsley.Node{opNode, operand}, op.interpreter), nil, parsley.NilPos
+		}
+		err = p.furthestErr(err, operandErr)
+		missingPos = parsley.NilPos
+	}
+	p.ctx.ExitBacktrackPoint()
+
+	node, operandErr := p.parseAt(p.operand, leftRecCtx, pos)
+	if node != nil {
+		return node, nil, parsley.NilPos
+	}
+
+	if operandErr != nil && (!parsley.IsNotFoundError(operandErr) || operandErr.Pos() > missingPos) {
+		missingPos = parsley.NilPos
+	}
+
+	return nil, p.furthestErr(err, operandErr), missingPos
+}
+
+// parseAfter parses the operand after an operator
+// If there is no operand at all then a "missing operand" error is returned.
+func (p *expression) parseAfter(opNode parsley.Node, minPrecedence int) (parsley.Node, parsley.Error) {
+	node, err, missingPos := p.parse(data.EmptyIntMap, opNode.ReaderPos(), minPrecedence)
+	if node == nil && missingPos != parsley.NilPos {
+		return nil, parsley.NewErrorf(missingPos, "missing operand after '%s'", opNode.Token())
+	}
+	return node, err
+}
+
+// parsePostfix tries to apply the infix and postfix operators on the left operand
+// If an operator matches but the rest of the expression is invalid then the error is saved in the context and the
+// next operator is tried. It returns nil if no operator can be applied.
+func (p *expression) parsePostfix(left parsley.Node, minPrecedence int, nonAssocPrecedence int) (parsley.Node, *exprOperator) {
+	pos := left.ReaderPos()
+	p.ctx.EnterBacktrackPoint(pos)
+	defer p.ctx.ExitBacktrackPoint()
+
+	for _, op := range p.postfix {
+		if op.precedence < minPrecedence || (op.kind == infixOp && op.assoc == NonAssoc && op.precedence == nonAssocPrecedence) {
+			continue
+		}
+
+		opNode, _ := p.parseAt(op.op, data.EmptyIntMap, pos)
+		if opNode == nil {
+			continue
+		}
+
+		var node parsley.Node
+		var err parsley.Error
+		switch op.kind {
+		case postfixOp:
+			node = ast.NewNonTerminalNode(TokenPostfixOp, []parsley.Node{left, opNode}, op.interpreter)
+		case infixOp:
+			node, err = p.parseInfix(op, left, opNode)
+		case ternaryOp:
+			node, err = p.parseTernary(op, left, opNode)
+		case callOp:
+			node, err = p.parseCall(op, left, opNode)
+		case indexOp:
+			node, err = p.parseIndex(op, left, opNode)
+		}
+
+		if node != nil {
+			return node, op
+		}
+		p.ctx.SetError(err)
+	}
+
+	return nil, nil
+}
+
+func (p *expression) parseInfix(op *exprOperator, left parsley.Node, opNode parsley.Node) (parsley.Node, parsley.Error) {
+	rightPrecedence := op.precedence + 1
+	if op.assoc == RightAssoc {
+		rightPrecedence = op.precedence
+	}
+
+	right, err := p.parseAfter(opNode, rightPrecedence)
+	if right == nil {
+		return nil, err
+	}
+
+	return ast.NewNonTerminalNode(TokenInfixOp, []parsley.Node{left, opNode, right}, op.interpreter), nil
+}
+
+func (p *expression) parseTernary(op *exprOperator, cond parsley.Node, op1Node parsley.Node) (parsley.Node, parsley.Error) {
+	node1, err := p.parseAfter(op1Node, math.MinInt32)
+	if node1 == nil {
+		return nil, err
+	}
+
+	op2Node, err := p.parseAt(op.sep, data.EmptyIntMap, node1.ReaderPos())
+	if op2Node == nil {
+		return nil, err
+	}
+
+	node2, err := p.parseAfter(op2Node, op.precedence)
+	if node2 == nil {
+		return nil, err
+	}
+
+	return ast.NewNonTerminalNode(TokenTernaryOp, []parsley.Node{cond, op1Node, node1, op2Node, node2}, op.interpreter), nil
+}
+
+func (p *expression) parseCall(op *exprOperator, callee parsley.Node, openNode parsley.Node) (parsley.Node, parsley.Error) {
+	children := []parsley.Node{callee, openNode}
+
+	closeNode, closeErr := p.parseAt(op.close, data.EmptyIntMap, openNode.ReaderPos())
+	if closeNode != nil {
+		return ast.NewNonTerminalNode(TokenCall, append(children, closeNode), op.interpreter), nil
+	}
+
+	prev := openNode
+	for {
+		arg, err := p.parseAfter(prev, math.MinInt32)
+		if arg == nil {
+			if prev == openNode {
+				return nil, p.furthestErr(closeErr, err)
+			}
+			return nil, err
+		}
+		children = append(children, arg)
+
+		closeNode, closeErr = p.parseAt(op.close, data.EmptyIntMap, arg.ReaderPos())
+		if closeNode != nil {
+			return ast.NewNonTerminalNode(TokenCall, append(children, closeNode), op.interpreter), nil
+		}
+
+		sepNode, sepErr := p.parseAt(op.sep, data.EmptyIntMap, arg.ReaderPos())
+		if sepNode == nil {
+			return nil, p.furthestErr(closeErr, sepErr)
+		}
+		children = append(children, sepNode)
+		prev = sepNode
+	}
+}
+
+func (p *expression) parseIndex(op *exprOperator, operand parsley.Node, openNode parsley.Node) (parsley.Node, parsley.Error) {
+	index, err := p.parseAfter(openNode, math.MinInt32)
+	if index == nil {
+		return nil, err
+	}
+
+	closeNode, err := p.parseAt(op.close, data.EmptyIntMap, index.ReaderPos())
+	if closeNode == nil {
+		return nil, err
+	}
+
+	return ast.NewNonTerminalNode(TokenIndex, []parsley.Node{operand, openNode, index, closeNode}, op.interpreter), nil
+}
+
+// parseAt runs the given parser and returns with the first result
+// The curtailing parsers are only kept if the parser was called at the starting position.
+func (p *expression) parseAt(parser parsley.Parser, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, parsley.Error) {
+	p.ctx.RegisterCall()
+	node, cp, err := parser.Parse(p.ctx, leftRecCtx, pos)
+	if pos == p.pos {
+		p.curtailingParsers = p.curtailingParsers.Union(cp)
+	}
+	p.ctx.RegisterNotFoundError(err)
+
+	node = firstNode(node)
+
+	if node != nil && err != nil {
+		p.ctx.SetError(err)
+	}
+
+	return node, err
+}
+
+// furthestErr returns with the error with the highest position, but a cut error is always preferred
+func (p *expression) furthestErr(err1 parsley.Error, err2 parsley.Error) parsley.Error {
+	if cut1, cut2 := parsley.IsCutError(err1), parsley.IsCutError(err2); cut1 != cut2 {
+		if cut1 {
+			return err1
+		}
+		return err2
+	}
+
+	if err1 == nil || err2 != nil && err2.Pos() >= err1.Pos() {
+		return err2
+	}
+	return err1
+}
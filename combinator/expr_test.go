@@ -0,0 +1,182 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator_test
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/ast/interpreter"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+// Let's define a calculator with the usual precedence rules
+func ExampleExpr() {
+	binary := func(f func(a, b float64) float64) ast.InterpreterFunc {
+		return func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+			a, _ := parsley.EvaluateNode(userCtx, node.Children()[0])
+			b, _ := parsley.EvaluateNode(userCtx, node.Children()[2])
+			return f(a.(float64), b.(float64)), nil
+		}
+	}
+	neg := ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+		a, _ := parsley.EvaluateNode(userCtx, node.Children()[1])
+		return -a.(float64), nil
+	})
+	op := func(op string) parsley.Parser {
+		return text.LeftTrim(terminal.Op(op), text.WsSpaces)
+	}
+
+	var value parser.Func
+	expr := combinator.Expr(&value).
+		Prefix(op("-"), 3, neg).
+		Infix(op("+"), 1, combinator.LeftAssoc, binary(func(a, b float64) float64 { return a + b })).
+		Infix(op("-"), 1, combinator.LeftAssoc, binary(func(a, b float64) float64 { return a - b })).
+		Infix(op("*"), 2, combinator.LeftAssoc, binary(func(a, b float64) float64 { return a * b })).
+		Infix(op("/"), 2, combinator.LeftAssoc, binary(func(a, b float64) float64 { return a / b })).
+		Infix(op("^"), 4, combinator.RightAssoc, binary(math.Pow))
+
+	value = text.LeftTrim(combinator.Choice(
+		terminal.Float("number"),
+		combinator.SeqOf(terminal.Rune('('), expr, text.LeftTrim(terminal.Rune(')'), text.WsSpaces)).
+			Bind(interpreter.Select(1)),
+	), text.WsSpaces)
+
+	f := text.NewFile("example.file", []byte("-2.0 ^ 2.0 + 3.0 * (4.0 - 1.0) / 2.0"))
+	r := text.NewReader(f)
+	ctx := parsley.NewContext(parsley.NewFileSet(f), r)
+	result, err := parsley.Evaluate(ctx, combinator.Sentence(expr))
+	fmt.Println(result, err)
+	// Output: 0.5 <nil>
+}
+
+var _ = Describe("Expr", func() {
+
+	// str returns with a string representation of the node, e.g. (1 + (2 * 3))
+	str := ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+		var parts []string
+		for _, child := range node.Children() {
+			value, err := parsley.EvaluateNode(userCtx, child)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, fmt.Sprint(value))
+		}
+		return "(" + strings.Join(parts, " ") + ")", nil
+	})
+
+	op := func(op string) parsley.Parser {
+		return text.LeftTrim(terminal.Op(op), text.WsSpaces)
+	}
+
+	var expr *combinator.Expression
+
+	BeforeEach(func() {
+		var value parser.Func
+		expr = combinator.Expr(&value).
+			Ternary(op("?"), op(":"), 1, str).
+			Infix(op("=="), 2, combinator.NonAssoc, str).
+			Infix(op("<"), 2, combinator.NonAssoc, str).
+			Infix(op("+"), 3, combinator.LeftAssoc, str).
+			Infix(op("-"), 3, combinator.LeftAssoc, str).
+			Infix(op("*"), 4, combinator.LeftAssoc, str).
+			Prefix(op("-"), 5, str).
+			Prefix(op("!"), 5, str).
+			Infix(op("^"), 6, combinator.RightAssoc, str).
+			Postfix(op("++"), 7, str).
+			Call(op("("), op(","), op(")"), 8, str).
+			Index(op("["), op("]"), 8, str)
+
+		value = text.LeftTrim(combinator.Choice(
+			terminal.Integer("integer"),
+			terminal.Regexp("identifier", "ID", "identifier", "[a-z]+", 0),
+			combinator.SeqOf(terminal.Rune('('), expr, text.LeftTrim(terminal.Rune(')'), text.WsSpaces)).
+				Bind(interpreter.Select(1)),
+		), text.WsSpaces)
+	})
+
+	evaluate := func(input string) (interface{}, error) {
+		f := text.NewFile("testfile", []byte(input))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		return parsley.Evaluate(ctx, combinator.Sentence(expr))
+	}
+
+	DescribeTable("should parse expressions",
+		func(input string, expected string) {
+			value, err := evaluate(input)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fmt.Sprint(value)).To(Equal(expected))
+		},
+		Entry("operand", "1", "1"),
+		Entry("infix", "1 + 2", "(1 + 2)"),
+		Entry("left associativity", "1 - 2 - 3", "((1 - 2) - 3)"),
+		Entry("right associativity", "1 ^ 2 ^ 3", "(1 ^ (2 ^ 3))"),
+		Entry("precedence", "1 + 2 * 3 - 4", "((1 + (2 * 3)) - 4)"),
+		Entry("parentheses", "(1 + 2) * 3", "((1 + 2) * 3)"),
+		Entry("prefix", "-1 * 2", "((- 1) * 2)"),
+		Entry("multiple prefix operators", "!-a", "(! (- a))"),
+		Entry("prefix with higher precedence operator", "-a ^ 2", "(- (a ^ 2))"),
+		Entry("postfix", "a++ * 2", "((a ++) * 2)"),
+		Entry("ternary", "a ? 1 : b ? 2 : 3", "(a ? 1 : (b ? 2 : 3))"),
+		Entry("ternary with lower precedence", "a == 1 ? b + 1 : 2", "((a == 1) ? (b + 1) : 2)"),
+		Entry("call without arguments", "f()", "(f ( ))"),
+		Entry("call with arguments", "f(1, a + 2)(3)", "((f ( 1 , (a + 2) )) ( 3 ))"),
+		Entry("index", "a[1 + 2][b]", "((a [ (1 + 2) ]) [ b ])"),
+		Entry("mixed", "-f(1)[2]++ < 3", "((- (((f ( 1 )) [ 2 ]) ++)) < 3)"),
+	)
+
+	DescribeTable("should return an error for invalid expressions",
+		func(input string, expected string) {
+			_, err := evaluate(input)
+			Expect(err).To(MatchError("failed to parse the input: " + expected))
+		},
+		Entry("missing operand", "1 +", "missing operand after '+' at testfile:1:4"),
+		Entry("missing operand in a sub-expression", "1 + (2 * )", "missing operand after '*' at testfile:1:10"),
+		Entry("missing prefix operand", "- ", "missing operand after '-' at testfile:1:3"),
+		Entry("missing call argument", "f(1, )", "missing operand after ',' at testfile:1:6"),
+		Entry("missing closing bracket", "a[1", `was expecting "?", "==", "<", "+", "-", "*", "^", "++", "(", "[" or "]", found end of input at testfile:1:4`),
+		Entry("missing ternary operator", "a ? 1", `was expecting "?", "==", "<", "+", "-", "*", "^", "++", "(", "[" or ":", found end of input at testfile:1:6`),
+		Entry("chained non-associative operators", "1 < 2 < 3", "was expecting the end of input at testfile:1:6"),
+	)
+
+	It("should return with a not found error if there is no operand", func() {
+		_, err := evaluate(")")
+		Expect(err).To(MatchError(`failed to parse the input: was expecting "-", "!", integer value, identifier or "(", found ")" at testfile:1:1`))
+	})
+
+	It("should override the error with the name", func() {
+		expr.Name("expression")
+		_, err := evaluate(")")
+		Expect(err).To(MatchError(`failed to parse the input: was expecting expression, found ")" at testfile:1:1`))
+	})
+
+	It("should not return the curtailing parsers after the first position", func() {
+		curtailing := parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+			node, _, err := terminal.Integer("integer").Parse(ctx, leftRecCtx, pos)
+			return node, data.NewIntSet(int(pos)), err
+		})
+		f := text.NewFile("testfile", []byte("1+2"))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		p := combinator.Expr(curtailing).Infix(terminal.Op("+"), 1, combinator.LeftAssoc, str)
+
+		res, cp, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Token()).To(Equal(combinator.TokenInfixOp))
+		Expect(cp).To(Equal(data.NewIntSet(int(f.Pos(0)))))
+	})
+})
@@ -0,0 +1,65 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator
+
+import (
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// And matches if the parser matches at the current position, but it doesn't consume any input
+// It returns with an empty node on success and with the parser's error otherwise.
+func And(p parsley.Parser) parser.Func {
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		notFoundErrs := ctx.NotFoundErrors()
+		ctx.EnterBacktrackPoint(pos)
+		node, cp, err := p.Parse(ctx, leftRecCtx, pos)
+		ctx.ExitBacktrackPoint()
+		if node == nil {
+			return nil, cp, err
+		}
+
+		// The expected inputs of the parser are irrelevant as the input after the predicate is parsed again
+		ctx.SetNotFoundErrors(notFoundErrs)
+
+		return ast.EmptyNode(pos), cp, nil
+	}).Trace("AND")
+}
+
+// Not matches if the parser doesn't match at the current position and it doesn't consume any input
+// It returns with an empty node on success. If the parser matches then a not found error is returned:
+// "was expecting anything but <matched input>".
+func Not(p parsley.Parser) parser.Func {
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		notFoundErrs := ctx.NotFoundErrors()
+		ctx.EnterBacktrackPoint(pos)
+		node, cp, _ := p.Parse(ctx, leftRecCtx, pos)
+		ctx.ExitBacktrackPoint()
+		if node == nil {
+			// The inputs expected by the parser are exactly the ones we don't want
+			ctx.SetNotFoundErrors(notFoundErrs)
+			return ast.EmptyNode(pos), cp, nil
+		}
+
+		err := parsley.NewError(pos, parsley.NotFoundError("anything but "+describeMatch(ctx, node)))
+		ctx.OverrideNotFoundErrors(notFoundErrs, err)
+
+		return nil, cp, err
+	}).Trace("NOT")
+}
+
+// describeMatch returns with a short description of the input matched by the node
+// If the reader can't describe the input then the token of the node is used.
+func describeMatch(ctx *parsley.Context, node parsley.Node) string {
+	if describer, ok := ctx.Reader().(parsley.MatchDescriber); ok {
+		return describer.DescribeMatch(node.Pos(), node.ReaderPos())
+	}
+
+	return firstNode(node).Token()
+}
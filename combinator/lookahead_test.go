@@ -0,0 +1,140 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+// Let's define an identifier parser which doesn't accept the reserved words
+func ExampleNot() {
+	reserved := combinator.Choice(
+		terminal.Word(nil, "if", "if"),
+		terminal.Word(nil, "else", "else"),
+	)
+	ident := combinator.SeqOf(
+		combinator.Not(reserved),
+		terminal.Regexp(nil, "ID", "identifier", "[a-z]+", 0),
+	)
+
+	for _, input := range []string{"foo", "iffy", "if"} {
+		f := text.NewFile("example.file", []byte(input))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		_, err := parsley.Parse(ctx, combinator.Sentence(ident))
+		fmt.Println(err)
+	}
+	// Output:
+	// <nil>
+	// <nil>
+	// failed to parse the input: was expecting anything but "if", found "if" at example.file:1:1
+}
+
+var _ = Describe("And", func() {
+	var (
+		f   *text.File
+		ctx *parsley.Context
+	)
+
+	BeforeEach(func() {
+		f = text.NewFile("testfile", []byte("ab"))
+		ctx = parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+	})
+
+	It("should return an empty node if the parser matches", func() {
+		node, _, err := combinator.And(terminal.Rune('a')).Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(node).To(Equal(ast.EmptyNode(f.Pos(0))))
+	})
+
+	It("should not consume the input", func() {
+		p := combinator.SeqOf(combinator.And(terminal.Rune('a')), terminal.Rune('a'), terminal.Rune('b'))
+		node, _, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(node.ReaderPos()).To(Equal(f.Pos(2)))
+	})
+
+	It("should return the parser's error if the parser doesn't match", func() {
+		node, _, err := combinator.And(terminal.Rune('b')).Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(node).To(BeNil())
+		Expect(err).To(MatchError(`was expecting "b"`))
+		Expect(err.Pos()).To(Equal(f.Pos(0)))
+	})
+
+	It("should return the curtailing parsers", func() {
+		p := parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+			return ast.EmptyNode(pos), data.NewIntSet(1), nil
+		})
+		_, cp, _ := combinator.And(p).Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(cp).To(Equal(data.NewIntSet(1)))
+	})
+
+	It("should keep the expected inputs registered before", func() {
+		ctx.RegisterNotFoundError(parsley.NewError(f.Pos(0), parsley.NotFoundError("x")))
+		p := combinator.And(combinator.SeqOf(terminal.Rune('a'), combinator.Optional(terminal.Rune('c'))))
+		_, _, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ctx.NotFoundErrors()).To(Equal(parsley.NotFoundErrors{Pos: f.Pos(0), Names: []string{"x"}}))
+	})
+})
+
+var _ = Describe("Not", func() {
+	var (
+		f   *text.File
+		ctx *parsley.Context
+	)
+
+	BeforeEach(func() {
+		f = text.NewFile("testfile", []byte("ab"))
+		ctx = parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+	})
+
+	It("should return an empty node if the parser doesn't match", func() {
+		node, _, err := combinator.Not(terminal.Rune('b')).Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(node).To(Equal(ast.EmptyNode(f.Pos(0))))
+	})
+
+	It("should not register the inputs expected by the parser", func() {
+		_, _, err := combinator.Not(terminal.Rune('b')).Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ctx.NotFoundErrors().Names).To(BeEmpty())
+	})
+
+	It("should return a not found error if the parser matches", func() {
+		node, _, err := combinator.Not(terminal.Rune('a')).Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(node).To(BeNil())
+		Expect(err).To(MatchError(`was expecting anything but "a"`))
+		Expect(err.Pos()).To(Equal(f.Pos(0)))
+		Expect(ctx.NotFoundErrors()).To(Equal(parsley.NotFoundErrors{Pos: f.Pos(0), Names: []string{`anything but "a"`}}))
+	})
+
+	It("should return the curtailing parsers", func() {
+		p := parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+			return nil, data.NewIntSet(1), nil
+		})
+		_, cp, _ := combinator.Not(p).Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(cp).To(Equal(data.NewIntSet(1)))
+	})
+
+	It("should not consume the input", func() {
+		p := combinator.SeqOf(combinator.Not(terminal.Rune('b')), terminal.Rune('a'))
+		node, _, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(node.ReaderPos()).To(Equal(f.Pos(1)))
+	})
+})
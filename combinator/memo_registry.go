@@ -0,0 +1,133 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// MemoRegistry assigns the parser indices of the memoized parsers of a grammar
+//
+// The indices are assigned in the order of the Memoize calls starting from 1, so building the same grammar always
+// results in the same indices. Every registry has its own indices, so any number of grammars can be built in the
+// same process, but the parsers of different registries (or of the package-level Memoize) must not be used in the
+// same context.
+type MemoRegistry struct {
+	mu      sync.Mutex
+	names   []string
+	indices map[string]int
+	parsers map[interface{}]int
+}
+
+// NewMemoRegistry creates a new memo registry
+func NewMemoRegistry() *MemoRegistry {
+	return &MemoRegistry{
+		indices: map[string]int{},
+		parsers: map[interface{}]int{},
+	}
+}
+
+// Memoize is the same as the package-level Memoize but the parser index is assigned by the registry
+// It panics if the same parser was already memoized in the registry. The pointer parsers (e.g. *Sequence) and the
+// parser.Func values (e.g. Choice or the terminals) are checked, other parser types can't be compared.
+func (r *MemoRegistry) Memoize(p parsley.Parser) parser.Func {
+	parserIndex := r.register("", p)
+	return memoizeWithName(fmt.Sprintf("MEMOIZE#%d", parserIndex), parserIndex, p)
+}
+
+// MemoizeNamed is the same as Memoize but it also registers a name for the parser index
+// The memoized parser is reported to the tracers as MEMOIZE#<name>. It panics if the name is already used.
+func (r *MemoRegistry) MemoizeNamed(name string, p parsley.Parser) parser.Func {
+	if name == "" {
+		panic("MemoizeNamed() should not be called with an empty name")
+	}
+
+	parserIndex := r.register(name, p)
+	return memoizeWithName("MEMOIZE#"+name, parserIndex, p)
+}
+
+// Index returns with the parser index of a named memoized parser
+func (r *MemoRegistry) Index(name string) (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	parserIndex, ok := r.indices[name]
+	return parserIndex, ok
+}
+
+// Name returns with the name of the memoized parser with the given index or an empty string if it has no name
+func (r *MemoRegistry) Name(parserIndex int) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if parserIndex < 1 || parserIndex > len(r.names) {
+		return ""
+	}
+	return r.names[parserIndex-1]
+}
+
+// Len returns with the number of the memoized parsers
+func (r *MemoRegistry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.names)
+}
+
+func (r *MemoRegistry) register(name string, p parsley.Parser) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if name != "" {
+		if parserIndex, ok := r.indices[name]; ok {
+			panic(fmt.Sprintf("the name %q is already used by the memoized parser #%d", name, parserIndex))
+		}
+	}
+
+	key, checked := parserKey(p)
+	if checked {
+		if parserIndex, ok := r.parsers[key]; ok {
+			panic(fmt.Sprintf("the parser is already memoized as #%d", parserIndex))
+		}
+	}
+
+	r.names = append(r.names, name)
+	parserIndex := len(r.names)
+
+	if name != "" {
+		r.indices[name] = parserIndex
+	}
+	if checked {
+		r.parsers[key] = parserIndex
+	}
+
+	return parserIndex
+}
+
+// parserKey returns with a comparable key which identifies the parser
+// Only pointers and parser.Func values are checked, as other values might contain functions which would make the map
+// lookup panic. A parser.Func is identified by its closure, as the code pointer is the same for all the parsers
+// created by the same function.
+func parserKey(p parsley.Parser) (interface{}, bool) {
+	switch pt := p.(type) {
+	case nil:
+		return nil, false
+	case parser.Func:
+		if pt == nil {
+			return nil, false
+		}
+		return *(*unsafe.Pointer)(unsafe.Pointer(&pt)), true
+	default:
+		return p, reflect.TypeOf(p).Kind() == reflect.Ptr
+	}
+}
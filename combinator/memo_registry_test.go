@@ -0,0 +1,134 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+// Every grammar can have its own registry, so the parser indices are the same every time the grammar is built
+func ExampleMemoRegistry() {
+	newParser := func() (parsley.Parser, *combinator.MemoRegistry) {
+		memo := combinator.NewMemoRegistry()
+
+		var sum parser.Func
+		sum = memo.MemoizeNamed("sum", combinator.Any(
+			combinator.SeqOf(&sum, terminal.Rune('+'), terminal.Integer("integer")),
+			terminal.Integer("integer"),
+		))
+		return combinator.Sentence(&sum), memo
+	}
+
+	for i := 0; i < 2; i++ {
+		p, memo := newParser()
+		index, _ := memo.Index("sum")
+
+		f := text.NewFile("example.file", []byte("1+2+3"))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		_, err := parsley.Parse(ctx, p)
+		_, cached := ctx.ResultCache().Get(index, f.Pos(0), data.EmptyIntMap)
+		fmt.Println(index, err, cached)
+	}
+	// Output:
+	// 1 <nil> true
+	// 1 <nil> true
+}
+
+var _ = Describe("MemoRegistry", func() {
+	var (
+		memo *combinator.MemoRegistry
+	)
+
+	BeforeEach(func() {
+		memo = combinator.NewMemoRegistry()
+	})
+
+	It("should assign consecutive parser indices", func() {
+		memo.Memoize(terminal.Rune('a'))
+		memo.MemoizeNamed("b", terminal.Rune('b'))
+		memo.Memoize(terminal.Rune('c'))
+
+		Expect(memo.Len()).To(Equal(3))
+		index, found := memo.Index("b")
+		Expect(found).To(BeTrue())
+		Expect(index).To(Equal(2))
+		Expect(memo.Name(2)).To(Equal("b"))
+		Expect(memo.Name(1)).To(Equal(""))
+		Expect(memo.Name(4)).To(Equal(""))
+	})
+
+	It("should not find an unknown name", func() {
+		_, found := memo.Index("x")
+		Expect(found).To(BeFalse())
+	})
+
+	It("should be independent from other registries", func() {
+		other := combinator.NewMemoRegistry()
+		memo.MemoizeNamed("a", terminal.Rune('a'))
+		other.MemoizeNamed("a", terminal.Rune('a'))
+
+		index, _ := memo.Index("a")
+		otherIndex, _ := other.Index("a")
+		Expect(index).To(Equal(1))
+		Expect(otherIndex).To(Equal(1))
+	})
+
+	It("should cache the result with the assigned parser index", func() {
+		memo.Memoize(terminal.Rune('x'))
+		p := memo.MemoizeNamed("a", terminal.Rune('a'))
+
+		f := text.NewFile("testfile", []byte("a"))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		node, _, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+
+		result, found := ctx.ResultCache().Get(2, f.Pos(0), data.EmptyIntMap)
+		Expect(found).To(BeTrue())
+		Expect(result.Node).To(Equal(node))
+	})
+
+	It("should panic if the same parser is memoized twice", func() {
+		p := combinator.SeqOf(terminal.Rune('a'))
+		memo.Memoize(p)
+		Expect(func() { memo.MemoizeNamed("a", p) }).To(Panic())
+		Expect(memo.Len()).To(Equal(1))
+	})
+
+	It("should panic if the same parser function is memoized twice", func() {
+		p := combinator.Choice(terminal.Rune('a'), terminal.Rune('b'))
+		memo.Memoize(p)
+		Expect(func() { memo.Memoize(p) }).To(Panic())
+		Expect(memo.Len()).To(Equal(1))
+	})
+
+	It("should allow different parser functions created by the same function", func() {
+		memo.Memoize(combinator.Choice(terminal.Rune('a'), terminal.Rune('b')))
+		memo.Memoize(combinator.Choice(terminal.Rune('a'), terminal.Rune('b')))
+		memo.Memoize(terminal.Rune('a'))
+		memo.Memoize(terminal.Rune('a'))
+		Expect(memo.Len()).To(Equal(4))
+	})
+
+	It("should panic if a name is used twice", func() {
+		memo.MemoizeNamed("a", terminal.Rune('a'))
+		Expect(func() { memo.MemoizeNamed("a", terminal.Rune('b')) }).To(Panic())
+	})
+
+	It("should panic if the name is empty", func() {
+		Expect(func() { memo.MemoizeNamed("", terminal.Rune('a')) }).To(Panic())
+	})
+})
@@ -7,6 +7,7 @@
 package combinator
 
 import (
+	"fmt"
 	"sync/atomic"
 
 	"github.com/conflowio/parsley/data"
@@ -17,28 +18,75 @@ import (
 var nextParserIndex int32
 
 // Memoize handles result cache and curtailing left recursion
+// The parser index is taken from a process-wide counter, so it depends on the order of the Memoize calls in the
+// process. Use a MemoRegistry if you build grammars dynamically or you need stable parser indices.
 func Memoize(p parsley.Parser) parser.Func {
 	parserIndex := int(atomic.AddInt32(&nextParserIndex, 1))
+	return memoizeWithName(fmt.Sprintf("MEMOIZE#%d", parserIndex), parserIndex, p)
+}
+
+// memoizeWithName returns with the memoizing parser which is reported to the tracer with the given name
+func memoizeWithName(name string, parserIndex int, p parsley.Parser) parser.Func {
+	traceID := parsley.NewTraceID()
 	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
-		if result, found := ctx.ResultCache().Get(parserIndex, pos, leftRecCtx); found {
-			return result.Node, result.CurtailingParsers, result.Error
+		tracer := ctx.Tracer()
+		if tracer == nil {
+			node, cp, err, _ := memoize(parserIndex, p, ctx, leftRecCtx, pos)
+			return node, cp, err
 		}
 
-		if leftRecCtx.Get(parserIndex) > ctx.Reader().Remaining(pos)+1 {
-			return nil, data.NewIntSet(parserIndex), nil
+		tracer.Enter(name, pos)
+		node, cp, err, cached := memoize(parserIndex, p, ctx, leftRecCtx, pos)
+		tracer.Exit(name, pos, parsley.TraceResult{
+			Node: node, CurtailingParsers: cp, Err: err, ID: traceID, Named: true, Memoized: true, Cached: cached,
+		})
+
+		return node, cp, err
+	})
+}
+
+// memoize returns with the cached result or calls the parser and saves the result
+// The last return value is true if the result was found in the cache.
+func memoize(parserIndex int, p parsley.Parser, ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error, bool) {
+	rt, trackRead := ctx.Reader().(parsley.ReadTracker)
+
+	if result, found := ctx.GetResult(parserIndex, pos, leftRecCtx); found {
+		if trackRead && result.ReadEnd > rt.ReadEnd() {
+			rt.SetReadEnd(result.ReadEnd)
 		}
+		return result.Node, result.CurtailingParsers, result.Error, true
+	}
+
+	// The left recursion is curtailed if the call count is higher than the remaining input length + 1
+	// The remaining length is checked with IsEOF, so a streamed input is only loaded as far as it's necessary.
+	if count := leftRecCtx.Get(parserIndex); count > 1 && ctx.Reader().IsEOF(pos+parsley.Pos(count-2)) {
+		return nil, data.NewIntSet(parserIndex), nil, false
+	}
+
+	var prevReadEnd parsley.Pos
+	if trackRead {
+		prevReadEnd = rt.ReadEnd()
+		rt.SetReadEnd(pos)
+	}
+
+	node, cp, err := p.Parse(ctx, leftRecCtx.Inc(parserIndex), pos)
+	leftRecCtx = leftRecCtx.Filter(cp)
 
-		node, cp, err := p.Parse(ctx, leftRecCtx.Inc(parserIndex), pos)
-		leftRecCtx = leftRecCtx.Filter(cp)
+	res := &parsley.Result{
+		LeftRecCtx:        leftRecCtx,
+		CurtailingParsers: cp,
+		Error:             err,
+		Node:              node,
+	}
 
-		res := &parsley.Result{
-			LeftRecCtx:        leftRecCtx,
-			CurtailingParsers: cp,
-			Error:             err,
-			Node:              node,
+	if trackRead {
+		res.ReadEnd = rt.ReadEnd()
+		if prevReadEnd > res.ReadEnd {
+			rt.SetReadEnd(prevReadEnd)
 		}
-		ctx.ResultCache().Save(parserIndex, pos, res)
+	}
 
-		return node, cp, err
-	})
+	ctx.SaveResult(parserIndex, pos, res)
+
+	return node, cp, err, false
 }
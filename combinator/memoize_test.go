@@ -8,9 +8,14 @@ package combinator_test
 
 import (
 	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
 
 	"github.com/conflowio/parsley/ast"
 	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
 	"github.com/conflowio/parsley/parser"
 	"github.com/conflowio/parsley/parsley"
 	"github.com/conflowio/parsley/text"
@@ -48,6 +53,76 @@ func ExampleMemoize() {
 	// Output: string abbbbbbbb
 }
 
+var _ = Describe("Memoize", func() {
+	It("should cache the result with the parser index", func() {
+		memo := combinator.NewMemoRegistry()
+		f := text.NewFile("testfile", []byte("a"))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+
+		p := memo.MemoizeNamed("A", terminal.Rune('a'))
+		node, _, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+
+		index, _ := memo.Index("A")
+		result, found := ctx.ResultCache().Get(index, f.Pos(0), data.EmptyIntMap)
+		Expect(found).To(BeTrue())
+		Expect(result.Node).To(Equal(node))
+	})
+
+	It("should report the memoized and the cached calls to the tracer", func() {
+		memo := combinator.NewMemoRegistry()
+		f := text.NewFile("testfile", []byte("a"))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		tracer := &memoTracer{}
+		ctx.SetTracer(tracer)
+
+		p := memo.Memoize(terminal.Rune('a'))
+		_, _, _ = p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		_, _, _ = p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+
+		name := "MEMOIZE#1"
+		Expect(tracer.results[name]).To(HaveLen(2))
+		Expect(tracer.results[name][0].Memoized).To(BeTrue())
+		Expect(tracer.results[name][0].Cached).To(BeFalse())
+		Expect(tracer.results[name][1].Memoized).To(BeTrue())
+		Expect(tracer.results[name][1].Cached).To(BeTrue())
+	})
+
+	It("should keep the result cache small when the backtracked results are evicted", func() {
+		input := []byte(strings.Repeat("ab", 5000))
+		f := text.NewFile("testfile", input)
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		ctx.SetCachePolicy(parsley.CachePolicy{EvictBacktracked: true})
+
+		a := combinator.Memoize(terminal.Rune('a'))
+		p := combinator.Sentence(combinator.Many(combinator.Choice(
+			combinator.SeqOf(a, terminal.Rune('c')),
+			combinator.SeqOf(a, terminal.Rune('b')),
+		)))
+		_, err := parsley.Parse(ctx, p)
+		Expect(err).ToNot(HaveOccurred())
+
+		stats := ctx.ResultCacheStats()
+		// every second alternative and the last failed match at the end of the input
+		Expect(stats.Hits).To(Equal(5001))
+		Expect(stats.PeakEntries).To(BeNumerically("<", 1000))
+		Expect(stats.Evictions).To(BeNumerically(">", 4000))
+	})
+})
+
+type memoTracer struct {
+	results map[string][]parsley.TraceResult
+}
+
+func (m *memoTracer) Enter(name string, pos parsley.Pos) {}
+
+func (m *memoTracer) Exit(name string, pos parsley.Pos, result parsley.TraceResult) {
+	if m.results == nil {
+		m.results = map[string][]parsley.TraceResult{}
+	}
+	m.results[name] = append(m.results[name], result)
+}
+
 //
 // func TestRegisterResultShouldSaveResultForPosition(t *testing.T) {
 // 	h := parser.NewHistory()
@@ -14,9 +14,15 @@ import (
 )
 
 // Optional returns the parser's matches and an empty match
+// If the parser fails with a cut error (see Cut) then the error is returned.
 func Optional(p parsley.Parser) parser.Func {
 	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		ctx.EnterBacktrackPoint(pos)
 		res, cp, err := p.Parse(ctx, leftRecCtx, pos)
+		ctx.ExitBacktrackPoint()
+		if res == nil && parsley.IsCutError(err) {
+			return nil, cp, err
+		}
 		return ast.AppendNode(res, ast.EmptyNode(pos)), cp, err
 	})
 }
@@ -0,0 +1,240 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator
+
+import (
+	"fmt"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// TokenPermutation is the default token of the Permutation nodes
+const TokenPermutation = "PERMUTATION"
+
+type permutationMember struct {
+	name     string
+	p        parsley.Parser
+	required bool
+}
+
+// PermutationParser matches its members in any order, every member at most once
+//
+// The result is an ast.NonTerminalNode with one child for every member in the order they were defined. The missing
+// optional members are ast.EmptyNodes at the end of the match. The separators are not part of the result.
+type PermutationParser struct {
+	token       string
+	members     []permutationMember
+	sep         parsley.Parser
+	interpreter parsley.Interpreter
+	customErr   error
+	traceID     int
+	named       bool
+}
+
+// Permutation creates a new permutation parser, the members should be added with Required and Optional
+func Permutation() *PermutationParser {
+	return &PermutationParser{token: TokenPermutation, traceID: parsley.NewTraceID()}
+}
+
+// Required adds a member which has to be matched
+// The name is used in the error messages: "was expecting <name>" or "duplicate <name>".
+func (p *PermutationParser) Required(name string, member parsley.Parser) *PermutationParser {
+	return p.add(name, member, true)
+}
+
+// Optional adds a member which can be omitted
+// The name is used in the error messages: "duplicate <name>".
+func (p *PermutationParser) Optional(name string, member parsley.Parser) *PermutationParser {
+	return p.add(name, member, false)
+}
+
+func (p *PermutationParser) add(name string, member parsley.Parser, required bool) *PermutationParser {
+	if name == "" {
+		panic("Permutation members should not have an empty name")
+	}
+	for _, m := range p.members {
+		if m.name == name {
+			panic(fmt.Sprintf("Permutation member %q is already defined", name))
+		}
+	}
+
+	p.members = append(p.members, permutationMember{name: name, p: member, required: required})
+	return p
+}
+
+// Separator sets the parser which has to match between the members
+func (p *PermutationParser) Separator(sep parsley.Parser) *PermutationParser {
+	p.sep = sep
+	return p
+}
+
+// Name overrides the returned error if its position is the same as the reader's position
+// The error will be: "was expecting <name>"
+func (p *PermutationParser) Name(name string) *PermutationParser {
+	p.customErr = parsley.NotFoundError(name)
+	return p
+}
+
+// Token sets the result token
+func (p *PermutationParser) Token(token string) *PermutationParser {
+	p.token = token
+	p.named = true
+	return p
+}
+
+// Bind binds the given interpreter
+func (p *PermutationParser) Bind(interpreter parsley.Interpreter) *PermutationParser {
+	p.interpreter = interpreter
+	return p
+}
+
+// Parse parses the given input
+func (p *PermutationParser) Parse(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	if len(p.members) == 0 {
+		panic("Permutation should have at least one member")
+	}
+
+	if ctx.Tracer() == nil {
+		return p.parse(ctx, leftRecCtx, pos)
+	}
+	return parsley.Trace(p.traceID, p.token, p.named, parser.Func(p.parse), ctx, leftRecCtx, pos)
+}
+
+func (p *PermutationParser) parse(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	notFoundErrs := ctx.NotFoundErrors()
+	res, cp, err := p.parseMembers(ctx, leftRecCtx, pos)
+	if err != nil && p.customErr != nil && err.Pos() == pos && parsley.IsNotFoundError(err) && !parsley.IsCutError(err) {
+		err = parsley.NewError(pos, p.customErr)
+		ctx.OverrideNotFoundErrors(notFoundErrs, err)
+	}
+
+	return res, cp, err
+}
+
+func (p *PermutationParser) parseMembers(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	cp := data.EmptyIntSet
+	nodes := make([]parsley.Node, len(p.members))
+	var err, cutErr parsley.Error
+
+	setErr := func(err2 parsley.Error) {
+		ctx.RegisterNotFoundError(err2)
+		if err2 != nil && (err == nil || err2.Pos() >= err.Pos()) {
+			err = err2
+		}
+	}
+
+	// parseAt returns with the first match of the member at the given position
+	parseAt := func(parser parsley.Parser, pos2 parsley.Pos) parsley.Node {
+		ctx.RegisterCall()
+		lrc := leftRecCtx
+		if pos2 > pos {
+			lrc = data.EmptyIntMap
+		}
+		node, cp2, err2 := parser.Parse(ctx, lrc, pos2)
+		if pos2 == pos {
+			cp = cp.Union(cp2)
+		}
+		setErr(err2)
+		if node == nil && parsley.IsCutError(err2) {
+			cutErr = err2
+		}
+		return firstNode(node)
+	}
+
+	// parseNext parses the separator and the next unmatched member, it returns nil if no more members can be matched
+	parseNext := func(matched int, readerPos parsley.Pos) (parsley.Node, parsley.Error) {
+		memberPos := readerPos
+		if matched > 0 && p.sep != nil {
+			sepNode := parseAt(p.sep, readerPos)
+			if cutErr != nil {
+				return nil, cutErr
+			}
+			if sepNode == nil {
+				return nil, nil
+			}
+			memberPos = sepNode.ReaderPos()
+		}
+
+		for i, m := range p.members {
+			if nodes[i] != nil {
+				continue
+			}
+			if node := parseAt(m.p, memberPos); node != nil {
+				nodes[i] = node
+				return node, nil
+			}
+			if cutErr != nil {
+				return nil, cutErr
+			}
+		}
+
+		// The matched members are not expected here, so the errors of the duplicate check are dropped
+		notFoundErrs, prevErr := ctx.NotFoundErrors(), err
+		defer func() {
+			ctx.SetNotFoundErrors(notFoundErrs)
+			err = prevErr
+		}()
+
+		for i, m := range p.members {
+			if nodes[i] == nil {
+				continue
+			}
+			if duplicate := parseAt(m.p, memberPos); duplicate != nil {
+				return nil, parsley.NewErrorf(duplicate.Pos(), "duplicate %s", m.name)
+			}
+		}
+
+		return nil, nil
+	}
+
+	readerPos := pos
+	for matched := 0; ; matched++ {
+		// The permutation ends at the current position if no more members can be matched
+		ctx.EnterBacktrackPoint(readerPos)
+		node, memberErr := parseNext(matched, readerPos)
+		ctx.ExitBacktrackPoint()
+		if memberErr != nil {
+			return nil, cp, memberErr
+		}
+		if node == nil {
+			break
+		}
+
+		readerPos = node.ReaderPos()
+	}
+
+	var missing bool
+	for i, m := range p.members {
+		if nodes[i] == nil && m.required {
+			setErr(parsley.NewError(readerPos, parsley.NotFoundError(m.name)))
+			missing = true
+		}
+	}
+	if missing {
+		return nil, cp, err
+	}
+
+	ctx.SetError(err)
+
+	startPos := readerPos
+	for i := range nodes {
+		if nodes[i] == nil {
+			nodes[i] = ast.EmptyNode(readerPos)
+		} else if nodes[i].Pos() < startPos {
+			startPos = nodes[i].Pos()
+		}
+	}
+
+	node := ast.NewNonTerminalNode(p.token, nodes, p.interpreter)
+	node.SetPos(func(parsley.Pos) parsley.Pos { return startPos })
+	node.SetReaderPos(func(parsley.Pos) parsley.Pos { return readerPos })
+
+	return node, cp, nil
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/ast/interpreter"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/parsley/parsleyfakes"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+// Let's define a config block where the attributes can be in any order, but every attribute can be set only once
+func ExamplePermutation() {
+	attr := func(name string) parsley.Parser {
+		return combinator.SeqOf(
+			text.LeftTrim(terminal.Word(nil, name, name), text.WsSpaces),
+			text.LeftTrim(terminal.Rune('='), text.WsSpaces),
+			text.LeftTrim(terminal.Integer("integer"), text.WsSpaces),
+		).Bind(interpreter.Select(2))
+	}
+	values := ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+		var res []interface{}
+		for _, child := range node.Children() {
+			if _, empty := child.(ast.EmptyNode); empty {
+				res = append(res, nil)
+				continue
+			}
+			value, err := parsley.EvaluateNode(userCtx, child)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, value)
+		}
+		return res, nil
+	})
+
+	config := combinator.Permutation().
+		Required("port", attr("port")).
+		Optional("timeout", attr("timeout")).
+		Optional("retries", attr("retries")).
+		Separator(text.LeftTrim(terminal.Rune(','), text.WsSpaces)).
+		Bind(values)
+
+	for _, input := range []string{"retries = 3, port = 80", "timeout = 5", "port = 80, port = 81"} {
+		f := text.NewFile("example.file", []byte(input))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		value, err := parsley.Evaluate(ctx, combinator.Sentence(config))
+		fmt.Println(value, err)
+	}
+	// Output:
+	// [80 <nil> 3] <nil>
+	// <nil> failed to parse the input: was expecting "," or port, found end of input at example.file:1:12
+	// <nil> failed to parse the input: duplicate port at example.file:1:12
+}
+
+var _ = Describe("Permutation", func() {
+
+	var (
+		p          *combinator.PermutationParser
+		a, b, c    *parsleyfakes.FakeParser
+		ctx        *parsley.Context
+		leftRecCtx data.IntMap
+		pos        parsley.Pos
+		res        parsley.Node
+		cp         data.IntSet
+		err        parsley.Error
+	)
+
+	// matchAt sets up a fake parser which matches a single character at the given positions
+	// The curtailing parsers contain the position, so it can be checked which calls were merged.
+	matchAt := func(fp *parsleyfakes.FakeParser, token string, positions ...parsley.Pos) {
+		fp.ParseStub = func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+			for _, matchPos := range positions {
+				if pos == matchPos {
+					return ast.NewTerminalNode(nil, token, token, pos, pos+1), data.NewIntSet(int(pos)), nil
+				}
+			}
+			return nil, data.NewIntSet(int(pos)), parsley.NewError(pos, parsley.NotFoundError(token))
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = parsley.NewContext(parsley.NewFileSet(), &parsleyfakes.FakeReader{})
+		a = &parsleyfakes.FakeParser{}
+		b = &parsleyfakes.FakeParser{}
+		c = &parsleyfakes.FakeParser{}
+		leftRecCtx = data.NewIntMap(map[int]int{1: 2})
+		pos = parsley.Pos(1)
+		matchAt(a, "a")
+		matchAt(b, "b")
+		matchAt(c, "c")
+		p = combinator.Permutation().Required("a", a).Required("b", b).Required("c", c)
+	})
+
+	JustBeforeEach(func() {
+		res, cp, err = p.Parse(ctx, leftRecCtx, pos)
+	})
+
+	Context("when all members match in a different order", func() {
+		BeforeEach(func() {
+			matchAt(a, "a", pos+1)
+			matchAt(b, "b", pos+2)
+			matchAt(c, "c", pos)
+		})
+
+		It("should return the members in the order they were defined", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Token()).To(Equal(combinator.TokenPermutation))
+			Expect(res.Pos()).To(Equal(pos))
+			Expect(res.ReaderPos()).To(Equal(pos + 3))
+
+			children := res.(parsley.NonTerminalNode).Children()
+			Expect(children).To(HaveLen(3))
+			Expect(children[0].Pos()).To(Equal(pos + 1))
+			Expect(children[1].Pos()).To(Equal(pos + 2))
+			Expect(children[2].Pos()).To(Equal(pos))
+		})
+
+		It("should only pass the left recursion context at the start position", func() {
+			passedCtx, passedLeftRecCtx, passedPos := a.ParseArgsForCall(0)
+			Expect(passedCtx).To(BeEquivalentTo(ctx))
+			Expect(passedLeftRecCtx).To(BeEquivalentTo(leftRecCtx))
+			Expect(passedPos).To(Equal(pos))
+
+			_, passedLeftRecCtx, passedPos = a.ParseArgsForCall(1)
+			Expect(passedLeftRecCtx).To(BeEquivalentTo(data.EmptyIntMap))
+			Expect(passedPos).To(Equal(pos + 1))
+		})
+
+		It("should only merge the curtailing parsers at the start position", func() {
+			Expect(cp).To(Equal(data.NewIntSet(int(pos))))
+		})
+
+		It("should only call the matched members again to check for duplicates", func() {
+			Expect(c.ParseCallCount()).To(Equal(2))
+			_, _, passedPos := c.ParseArgsForCall(1)
+			Expect(passedPos).To(Equal(pos + 3))
+		})
+	})
+
+	Context("when an optional member is missing", func() {
+		BeforeEach(func() {
+			matchAt(a, "a", pos)
+			p = combinator.Permutation().Optional("b", b).Required("a", a)
+		})
+
+		It("should return an empty node at the end of the match", func() {
+			Expect(err).ToNot(HaveOccurred())
+			children := res.(parsley.NonTerminalNode).Children()
+			Expect(children[0]).To(Equal(ast.EmptyNode(pos + 1)))
+			Expect(res.ReaderPos()).To(Equal(pos + 1))
+		})
+	})
+
+	Context("when all members are optional and nothing matches", func() {
+		BeforeEach(func() {
+			p = combinator.Permutation().Optional("a", a).Optional("b", b)
+		})
+
+		It("should match the empty input", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Pos()).To(Equal(pos))
+			Expect(res.ReaderPos()).To(Equal(pos))
+		})
+	})
+
+	Context("when required members are missing", func() {
+		BeforeEach(func() {
+			matchAt(b, "b", pos)
+		})
+
+		It("should register the missing members as not found errors", func() {
+			Expect(res).To(BeNil())
+			Expect(err.Pos()).To(Equal(pos + 1))
+			Expect(ctx.NotFoundErrors()).To(Equal(parsley.NotFoundErrors{Pos: pos + 1, Names: []string{"a", "c"}}))
+		})
+	})
+
+	Context("when a member matches again", func() {
+		BeforeEach(func() {
+			matchAt(a, "a", pos, pos+2)
+			matchAt(b, "b", pos+1)
+			p = combinator.Permutation().Required("a", a).Optional("b", b)
+		})
+
+		It("should return a duplicate error", func() {
+			Expect(res).To(BeNil())
+			Expect(err).To(MatchError("duplicate a"))
+			Expect(err.Pos()).To(Equal(pos + 2))
+		})
+	})
+
+	Context("when a separator is set", func() {
+		var sep *parsleyfakes.FakeParser
+
+		BeforeEach(func() {
+			sep = &parsleyfakes.FakeParser{}
+			matchAt(sep, ",", pos+1, pos+3)
+			matchAt(a, "a", pos+2)
+			matchAt(b, "b", pos)
+			p = combinator.Permutation().Required("a", a).Required("b", b).Separator(sep)
+		})
+
+		It("should match the members between the separators", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.ReaderPos()).To(Equal(pos + 3))
+
+			Expect(sep.ParseCallCount()).To(Equal(2))
+			_, _, passedPos := sep.ParseArgsForCall(0)
+			Expect(passedPos).To(Equal(pos + 1))
+		})
+
+		Context("when there is a trailing separator", func() {
+			BeforeEach(func() {
+				p = combinator.Permutation().Required("a", a).Required("b", b).Required("c", c).Separator(sep)
+				matchAt(a, "a", pos)
+				matchAt(b, "b", pos+2)
+			})
+
+			It("should only expect the unmatched members", func() {
+				Expect(res).To(BeNil())
+				Expect(err.Pos()).To(Equal(pos + 4))
+				Expect(ctx.NotFoundErrors()).To(Equal(parsley.NotFoundErrors{Pos: pos + 4, Names: []string{"c"}}))
+			})
+		})
+
+		Context("when the separator is missing", func() {
+			BeforeEach(func() {
+				matchAt(sep, ",")
+				matchAt(a, "a", pos+1)
+			})
+
+			It("should return an error for the missing members", func() {
+				Expect(err).To(MatchError("was expecting a"))
+				Expect(a.ParseCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Context("when nothing matched and the permutation has a name", func() {
+		BeforeEach(func() {
+			p.Name("attributes")
+		})
+
+		It("should use the name in the error", func() {
+			Expect(err).To(MatchError("was expecting attributes"))
+		})
+	})
+
+	Context("when a member returns a cut error", func() {
+		BeforeEach(func() {
+			a.ParseStub = nil
+			a.ParseReturns(nil, data.EmptyIntSet, parsley.NewCutError(parsley.NewErrorf(pos+1, "some error")))
+		})
+
+		It("should return the cut error", func() {
+			Expect(res).To(BeNil())
+			Expect(parsley.IsCutError(err)).To(BeTrue())
+			Expect(b.ParseCallCount()).To(Equal(0))
+		})
+	})
+
+	It("should panic for invalid members", func() {
+		Expect(func() { combinator.Permutation().Required("", a) }).To(Panic())
+		Expect(func() { combinator.Permutation().Required("a", a).Optional("a", b) }).To(Panic())
+		Expect(func() { combinator.Permutation().Parse(ctx, leftRecCtx, pos) }).To(Panic())
+	})
+})
@@ -0,0 +1,60 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator
+
+import (
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// Recover tries to apply the given parser and if it fails it skips the input until the sync parser matches
+// The skipped input will be returned as an ast.ErrorNode containing the original error so the parsing can continue.
+// The input matched by the sync parser is not consumed, so it should be matched by the next parser.
+// If the sync parser doesn't match anywhere then all the remaining input will be skipped.
+// If the context's error limit is reached then the error is returned as a cut error, so the parsing stops.
+func Recover(p parsley.Parser, sync parsley.Parser) parser.Func {
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		ctx.EnterBacktrackPoint(pos)
+		defer ctx.ExitBacktrackPoint()
+
+		res, cp, err := p.Parse(ctx, leftRecCtx, pos)
+		if res != nil || err == nil {
+			return res, cp, err
+		}
+
+		ctx.RegisterNotFoundError(err)
+		err = ctx.ExtendNotFoundError(err)
+
+		if !ctx.RegisterRecoveredError(err) {
+			return nil, cp, parsley.NewCutError(err)
+		}
+
+		readerPos := pos
+		if err.Pos() > readerPos {
+			readerPos = err.Pos()
+		}
+
+		// The errors of the sync parser are irrelevant as the skipped input is reported as a single error
+		notFoundErrs := ctx.NotFoundErrors()
+		parseErr := ctx.Error()
+
+		ctx.RegisterCall()
+		for !ctx.Reader().IsEOF(readerPos) {
+			if syncRes, _, _ := sync.Parse(ctx, data.EmptyIntMap, readerPos); syncRes != nil {
+				break
+			}
+			readerPos++
+		}
+
+		ctx.SetNotFoundErrors(notFoundErrs)
+		ctx.RestoreError(parseErr)
+
+		return ast.NewErrorNode(err, pos, readerPos), cp, nil
+	})
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/ast/interpreter"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+// Let's define a parser which accepts a list of integers and continues parsing after an invalid item
+func ExampleRecover() {
+	p := combinator.SepBy(
+		combinator.Recover(terminal.Integer("integer"), combinator.Choice(terminal.Rune(','), parser.End())),
+		terminal.Rune(','),
+	).Bind(interpreter.Array())
+	f := text.NewFile("example.file", []byte("1,a,3,b"))
+	r := text.NewReader(f)
+	ctx := parsley.NewContext(parsley.NewFileSet(f), r)
+	_, err := parsley.Parse(ctx, combinator.Sentence(p))
+	fmt.Println(err)
+	// Output: failed to parse the input: was expecting integer value, found "a" at example.file:1:3
+	// was expecting integer value, found "b" at example.file:1:7
+}
+
+var _ = Describe("Recover", func() {
+	var (
+		f          *text.File
+		ctx        *parsley.Context
+		p          parsley.Parser
+		errorLimit int
+		res        parsley.Node
+		err        error
+		errs       parsley.ErrorList
+	)
+
+	BeforeEach(func() {
+		errorLimit = parsley.DefaultErrorLimit
+		p = combinator.Sentence(combinator.SeqOf(
+			terminal.Rune('['),
+			combinator.SepBy(
+				combinator.Recover(
+					terminal.Integer("integer"),
+					combinator.Choice(terminal.Rune(','), terminal.Rune(']')),
+				),
+				terminal.Rune(','),
+			).Bind(interpreter.Array()),
+			terminal.Rune(']'),
+		).Bind(interpreter.Select(1)))
+	})
+
+	JustBeforeEach(func() {
+		ctx = parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		ctx.SetErrorLimit(errorLimit)
+		res, err = parsley.Parse(ctx, p)
+		errs = nil
+		errors.As(err, &errs)
+	})
+
+	Context("when there are no errors", func() {
+		BeforeEach(func() {
+			f = text.NewFile("test", []byte("[1,2]"))
+		})
+
+		It("should return the result", func() {
+			Expect(err).ToNot(HaveOccurred())
+			value, evalErr := parsley.EvaluateNode(nil, res)
+			Expect(evalErr).ToNot(HaveOccurred())
+			Expect(value).To(Equal([]interface{}{int64(1), int64(2)}))
+		})
+	})
+
+	Context("when there are multiple errors", func() {
+		BeforeEach(func() {
+			f = text.NewFile("test", []byte("[1,abc,3,,5]"))
+		})
+
+		It("should return the partial tree", func() {
+			Expect(res).ToNot(BeNil())
+			array := res.(parsley.NonTerminalNode).Children()[0].(parsley.NonTerminalNode).Children()[1]
+			children := array.(parsley.NonTerminalNode).Children()
+			Expect(children).To(HaveLen(9))
+
+			Expect(children[2]).To(BeAssignableToTypeOf(&ast.ErrorNode{}))
+			Expect(children[2].Pos()).To(Equal(f.Pos(3)))
+			Expect(children[2].ReaderPos()).To(Equal(f.Pos(6)))
+			Expect(children[2].(*ast.ErrorNode).Error()).To(MatchError(`was expecting integer value, found "abc"`))
+
+			Expect(children[6]).To(BeAssignableToTypeOf(&ast.ErrorNode{}))
+			Expect(children[6].Pos()).To(Equal(f.Pos(9)))
+			Expect(children[6].ReaderPos()).To(Equal(f.Pos(9)))
+			Expect(children[6].(*ast.ErrorNode).Error()).To(MatchError(`was expecting integer value, found ","`))
+		})
+
+		It("should return all the errors", func() {
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0]).To(MatchError(`was expecting integer value, found "abc" at test:1:4`))
+			Expect(errs[1]).To(MatchError(`was expecting integer value, found "," at test:1:10`))
+		})
+
+		Context("when the error limit is reached", func() {
+			BeforeEach(func() {
+				errorLimit = 1
+			})
+
+			It("should stop parsing and return only the first errors", func() {
+				Expect(res).To(BeNil())
+				Expect(errs).To(HaveLen(1))
+				Expect(errs[0]).To(MatchError(`was expecting integer value, found "abc" at test:1:4`))
+			})
+		})
+	})
+
+	Context("when the sync parser fails further in the skipped input", func() {
+		BeforeEach(func() {
+			sync := combinator.Choice(
+				combinator.SeqOf(terminal.Rune(';'), terminal.Rune('x')),
+				terminal.Rune(','),
+				terminal.Rune(']'),
+				terminal.Rune(';'),
+			)
+			p = combinator.Sentence(combinator.SeqOf(
+				terminal.Rune('['),
+				combinator.SepBy(combinator.Recover(terminal.Integer("integer"), sync), terminal.Rune(',')),
+				terminal.Rune(']'),
+			))
+			f = text.NewFile("test", []byte("[1,a;b"))
+		})
+
+		It("should not report the errors of the sync parser", func() {
+			Expect(res).To(BeNil())
+			Expect(err).To(MatchError(`failed to parse the input: was expecting "," or "]", found ";" at test:1:5`))
+		})
+	})
+
+	Context("when the sync parser doesn't match", func() {
+		BeforeEach(func() {
+			f = text.NewFile("test", []byte("[1,abc"))
+		})
+
+		It("should skip the remaining input and fail", func() {
+			Expect(res).To(BeNil())
+			Expect(err).To(MatchError(`failed to parse the input: was expecting "," or "]", found end of input at test:1:7`))
+		})
+	})
+})
@@ -0,0 +1,57 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// Repeat applies the parser at least min and at most max times
+// If max is zero then there is no upper limit. The parser is not tried again after the max-th match.
+// If the parser matches less than min times then a "was expecting at least <min> <name>" error is returned at the
+// position where the next match was expected.
+func Repeat(p parsley.Parser, min int, max int) *Sequence {
+	if min < 0 || max < 0 || max > 0 && max < min {
+		panic(fmt.Sprintf("Repeat() was called with invalid limits: min=%d, max=%d", min, max))
+	}
+
+	required := requiredRepetition(p, min)
+	lookup := func(i int) parsley.Parser {
+		if max > 0 && i >= max {
+			return nil
+		}
+		if i < min {
+			return required
+		}
+		return p
+	}
+	lenCheck := func(len int) bool {
+		return len >= min && (max == 0 || len <= max)
+	}
+	return Seq("REPEAT", lookup, lenCheck)
+}
+
+// requiredRepetition replaces the not found errors of the parser with "was expecting at least <min> <name>"
+func requiredRepetition(p parsley.Parser, min int) parser.Func {
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		notFoundErrs := ctx.NotFoundErrors()
+		res, cp, err := p.Parse(ctx, leftRecCtx, pos)
+
+		var notFoundErr parsley.NotFoundError
+		if res == nil && err != nil && err.Pos() == pos && !parsley.IsCutError(err) && errors.As(err, &notFoundErr) {
+			err = parsley.NewError(pos, parsley.NotFoundError(fmt.Sprintf("at least %d %s", min, string(notFoundErr))))
+			ctx.OverrideNotFoundErrors(notFoundErrs, err)
+		}
+
+		return res, cp, err
+	})
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/parsley/parsleyfakes"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+// Let's define a parser for unicode escape sequences with exactly 4 hex digits
+func ExampleRepeat() {
+	hexDigit := terminal.Regexp(nil, "HEX", "hex digit", "[0-9a-fA-F]", 0)
+	p := combinator.SeqOf(terminal.Op("\\u"), combinator.Repeat(hexDigit, 4, 4))
+
+	for _, input := range []string{`\u00e9`, `\u0e`} {
+		f := text.NewFile("example.file", []byte(input))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		_, err := parsley.Parse(ctx, combinator.Sentence(p))
+		fmt.Println(err)
+	}
+	// Output:
+	// <nil>
+	// failed to parse the input: was expecting at least 4 hex digit, found end of input at example.file:1:5
+}
+
+var _ = Describe("Repeat", func() {
+
+	var (
+		p          *combinator.Sequence
+		q          *parsleyfakes.FakeParser
+		ctx        *parsley.Context
+		leftRecCtx data.IntMap
+		pos        parsley.Pos
+		min, max   int
+		matches    int
+		name       string
+		qErr       parsley.Error
+		res        parsley.Node
+		cp         data.IntSet
+		err        parsley.Error
+	)
+
+	BeforeEach(func() {
+		ctx = parsley.NewContext(parsley.NewFileSet(), &parsleyfakes.FakeReader{})
+		q = &parsleyfakes.FakeParser{}
+		leftRecCtx = data.NewIntMap(map[int]int{1: 2})
+		pos = parsley.Pos(1)
+		min, max = 2, 3
+		matches = 4
+		name = ""
+		qErr = nil
+	})
+
+	JustBeforeEach(func() {
+		// q matches a single character at every call until it reaches the number of matches
+		q.ParseStub = func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+			i := q.ParseCallCount()
+			if i > matches {
+				if qErr != nil {
+					return nil, data.NewIntSet(i), qErr
+				}
+				return nil, data.NewIntSet(i), parsley.NewError(pos, parsley.NotFoundError(`"a"`))
+			}
+			return ast.NewTerminalNode(nil, "CHAR", 'a', pos, pos+1), data.NewIntSet(i), nil
+		}
+
+		p = combinator.Repeat(q, min, max)
+		if name != "" {
+			p.Name(name)
+		}
+		res, cp, err = p.Parse(ctx, leftRecCtx, pos)
+	})
+
+	It("should match at most max times", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.(parsley.NonTerminalNode).Children()).To(HaveLen(3))
+		Expect(res.ReaderPos()).To(Equal(pos + 3))
+	})
+
+	It("should not call the parser again after max matches", func() {
+		Expect(q.ParseCallCount()).To(Equal(3))
+	})
+
+	It("should call the parser at the end of the previous match", func() {
+		passedCtx, passedLeftRecCtx, passedPos := q.ParseArgsForCall(0)
+		Expect(passedCtx).To(BeEquivalentTo(ctx))
+		Expect(passedLeftRecCtx).To(BeEquivalentTo(leftRecCtx))
+		Expect(passedPos).To(Equal(pos))
+
+		for i := 1; i < 3; i++ {
+			_, passedLeftRecCtx, passedPos = q.ParseArgsForCall(i)
+			Expect(passedLeftRecCtx).To(BeEquivalentTo(data.EmptyIntMap))
+			Expect(passedPos).To(Equal(pos + parsley.Pos(i)))
+		}
+	})
+
+	It("should only return the curtailing parsers of the first call", func() {
+		Expect(cp).To(Equal(data.NewIntSet(1)))
+	})
+
+	Context("when max is zero", func() {
+		BeforeEach(func() {
+			max = 0
+		})
+
+		It("should have no upper limit", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.ReaderPos()).To(Equal(pos + 4))
+			Expect(q.ParseCallCount()).To(Equal(5))
+		})
+	})
+
+	Context("when min is zero and the parser doesn't match", func() {
+		BeforeEach(func() {
+			min = 0
+			matches = 0
+		})
+
+		It("should return an empty result", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.(parsley.NonTerminalNode).Children()).To(BeEmpty())
+			Expect(res.ReaderPos()).To(Equal(pos))
+		})
+	})
+
+	Context("when the parser matches less than min times", func() {
+		BeforeEach(func() {
+			matches = 1
+		})
+
+		It("should return an error at the position of the missing match", func() {
+			Expect(res).To(BeNil())
+			Expect(err).To(MatchError(`was expecting at least 2 "a"`))
+			Expect(err.Pos()).To(Equal(pos + 1))
+			Expect(ctx.NotFoundErrors()).To(Equal(parsley.NotFoundErrors{Pos: pos + 1, Names: []string{`at least 2 "a"`}}))
+		})
+
+		Context("when the error is after the start of the parser", func() {
+			BeforeEach(func() {
+				qErr = parsley.NewError(pos+2, parsley.NotFoundError(`"b"`))
+			})
+
+			It("should keep the error", func() {
+				Expect(err).To(MatchError(`was expecting "b"`))
+				Expect(err.Pos()).To(Equal(pos + 2))
+			})
+		})
+	})
+
+	Context("when nothing matched and the sequence has a name", func() {
+		BeforeEach(func() {
+			matches = 0
+			name = "two or three a"
+		})
+
+		It("should use the name in the error", func() {
+			Expect(err).To(MatchError("was expecting two or three a"))
+		})
+	})
+
+	It("should panic if the limits are invalid", func() {
+		Expect(func() { combinator.Repeat(q, -1, 0) }).To(Panic())
+		Expect(func() { combinator.Repeat(q, 0, -1) }).To(Panic())
+		Expect(func() { combinator.Repeat(q, 3, 2) }).To(Panic())
+	})
+})
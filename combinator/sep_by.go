@@ -12,15 +12,51 @@ import (
 
 // SepBy applies the given value parser zero or more times separated by the separator parser
 func SepBy(valueP parsley.Parser, sepP parsley.Parser) *Sequence {
-	return newSepBy(valueP, sepP, true)
+	return newSepBy("SEP_BY", valueP, sepP, func(len int) bool {
+		return len == 0 || len%2 == 1
+	})
 }
 
 // SepBy1 applies the given value parser one or more times separated by the separator parser
 func SepBy1(valueP parsley.Parser, sepP parsley.Parser) *Sequence {
-	return newSepBy(valueP, sepP, false)
+	return newSepBy("SEP_BY", valueP, sepP, func(len int) bool {
+		return len%2 == 1
+	})
 }
 
-func newSepBy(valueP parsley.Parser, sepP parsley.Parser, allowEmpty bool) *Sequence {
+// SepEndBy applies the given value parser zero or more times separated by the separator parser, allowing a
+// trailing separator
+func SepEndBy(valueP parsley.Parser, sepP parsley.Parser) *Sequence {
+	return newSepBy("SEP_END_BY", valueP, sepP, func(len int) bool {
+		return true
+	})
+}
+
+// SepEndBy1 applies the given value parser one or more times separated by the separator parser, allowing a
+// trailing separator
+func SepEndBy1(valueP parsley.Parser, sepP parsley.Parser) *Sequence {
+	return newSepBy("SEP_END_BY", valueP, sepP, func(len int) bool {
+		return len > 0
+	})
+}
+
+// EndBy applies the given value parser zero or more times, every value has to be followed by the separator parser
+func EndBy(valueP parsley.Parser, sepP parsley.Parser) *Sequence {
+	return newSepBy("END_BY", valueP, sepP, func(len int) bool {
+		return len%2 == 0
+	})
+}
+
+// EndBy1 applies the given value parser one or more times, every value has to be followed by the separator parser
+func EndBy1(valueP parsley.Parser, sepP parsley.Parser) *Sequence {
+	return newSepBy("END_BY", valueP, sepP, func(len int) bool {
+		return len > 0 && len%2 == 0
+	})
+}
+
+// newSepBy creates a sequence where the values and the separators follow each other
+// The lenCheck function gets the number of all matched values and separators.
+func newSepBy(token string, valueP parsley.Parser, sepP parsley.Parser, lenCheck func(int) bool) *Sequence {
 	lookup := func(i int) parsley.Parser {
 		if i%2 == 0 {
 			return valueP
@@ -28,8 +64,5 @@ func newSepBy(valueP parsley.Parser, sepP parsley.Parser, allowEmpty bool) *Sequ
 			return sepP
 		}
 	}
-	lenCheck := func(len int) bool {
-		return (len == 0 && allowEmpty) || len%2 == 1
-	}
-	return Seq("SEP_BY", lookup, lenCheck)
+	return Seq(token, lookup, lenCheck)
 }
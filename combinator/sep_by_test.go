@@ -9,9 +9,14 @@ package combinator_test
 import (
 	"fmt"
 
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
 	"github.com/conflowio/parsley/ast"
 	"github.com/conflowio/parsley/ast/interpreter"
 	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
 	"github.com/conflowio/parsley/parsley"
 	"github.com/conflowio/parsley/text"
 	"github.com/conflowio/parsley/text/terminal"
@@ -92,6 +97,86 @@ func ExampleSepBy1() {
 	// int64 6
 }
 
+// Let's define an integer list where a trailing comma is allowed
+func ExampleSepEndBy() {
+	p := combinator.SeqOf(
+		terminal.Rune('['),
+		combinator.SepEndBy(terminal.Integer("integer"), terminal.Rune(',')).Bind(interpreter.Array()),
+		terminal.Rune(']'),
+	).Bind(interpreter.Select(1))
+
+	for _, input := range []string{"[]", "[1,2]", "[1,2,]"} {
+		r := text.NewReader(text.NewFile("example.file", []byte(input)))
+		ctx := parsley.NewContext(parsley.NewFileSet(), r)
+		value, _ := parsley.Evaluate(ctx, combinator.Sentence(p))
+		fmt.Printf("%T %v\n", value, value)
+	}
+	// Output: []interface {} []
+	// []interface {} [1 2]
+	// []interface {} [1 2]
+}
+
+// Let's define a list of statements where every statement has to be terminated by a semicolon
+func ExampleEndBy() {
+	p := combinator.EndBy(terminal.Integer("integer"), terminal.Rune(';')).Bind(interpreter.Array())
+
+	r := text.NewReader(text.NewFile("example.file", []byte("1;2;")))
+	ctx := parsley.NewContext(parsley.NewFileSet(), r)
+	value, _ := parsley.Evaluate(ctx, combinator.Sentence(p))
+	fmt.Printf("%T %v\n", value, value)
+	// Output: []interface {} [1 2]
+}
+
+var _ = DescribeTable("separated lists",
+	func(p *combinator.Sequence, input string, expectedReaderPos int, expectedToken string) {
+		f := text.NewFile("testfile", []byte(input))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		node, _, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		if expectedReaderPos < 0 {
+			Expect(node).To(BeNil())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(node.Token()).To(Equal(expectedToken))
+		Expect(node.ReaderPos()).To(Equal(f.Pos(expectedReaderPos)))
+	},
+	Entry("SepEndBy empty", combinator.SepEndBy(terminal.Rune('a'), terminal.Rune(',')), "", 0, "SEP_END_BY"),
+	Entry("SepEndBy without trailing separator", combinator.SepEndBy(terminal.Rune('a'), terminal.Rune(',')), "a,a", 3, "SEP_END_BY"),
+	Entry("SepEndBy with trailing separator", combinator.SepEndBy(terminal.Rune('a'), terminal.Rune(',')), "a,a,", 4, "SEP_END_BY"),
+	Entry("SepEndBy1 empty", combinator.SepEndBy1(terminal.Rune('a'), terminal.Rune(',')), "", -1, ""),
+	Entry("SepEndBy1 with trailing separator", combinator.SepEndBy1(terminal.Rune('a'), terminal.Rune(',')), "a,", 2, "SEP_END_BY"),
+	Entry("EndBy empty", combinator.EndBy(terminal.Rune('a'), terminal.Rune(';')), "", 0, "END_BY"),
+	Entry("EndBy with terminators", combinator.EndBy(terminal.Rune('a'), terminal.Rune(';')), "a;a;", 4, "END_BY"),
+	Entry("EndBy without last terminator", combinator.EndBy(terminal.Rune('a'), terminal.Rune(';')), "a;a", -1, ""),
+	Entry("EndBy1 empty", combinator.EndBy1(terminal.Rune('a'), terminal.Rune(';')), "", -1, ""),
+	Entry("EndBy1 without terminator", combinator.EndBy1(terminal.Rune('a'), terminal.Rune(';')), "a", -1, ""),
+	Entry("EndBy1 with terminator", combinator.EndBy1(terminal.Rune('a'), terminal.Rune(';')), "a;", 2, "END_BY"),
+)
+
+var _ = DescribeTable("separated lists with interpreter.Array",
+	func(p *combinator.Sequence, input string, expected []interface{}) {
+		f := text.NewFile("testfile", []byte(input))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		value, err := parsley.Evaluate(ctx, combinator.Sentence(p.Bind(interpreter.Array())))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal(expected))
+	},
+	Entry("SepEndBy with trailing separator", combinator.SepEndBy(terminal.Integer("integer"), terminal.Rune(',')), "1,2,", []interface{}{int64(1), int64(2)}),
+	Entry("SepEndBy1 with trailing separator", combinator.SepEndBy1(terminal.Integer("integer"), terminal.Rune(',')), "1,", []interface{}{int64(1)}),
+	Entry("EndBy", combinator.EndBy(terminal.Integer("integer"), terminal.Rune(';')), "1;2;", []interface{}{int64(1), int64(2)}),
+	Entry("EndBy1", combinator.EndBy1(terminal.Integer("integer"), terminal.Rune(';')), "1;", []interface{}{int64(1)}),
+)
+
+var _ = Describe("SepEndBy", func() {
+	It("should support the sequence builder methods", func() {
+		f := text.NewFile("testfile", []byte("x"))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		p := combinator.SepEndBy1(terminal.Rune('a'), terminal.Rune(',')).Token("LIST").Name("list")
+		_, _, err := p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		Expect(err).To(MatchError("was expecting list"))
+	})
+})
+
 //
 // func TestSepByShouldCombineParserResults(t *testing.T) {
 // 	pResults := []parser.ResultSet{
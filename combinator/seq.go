@@ -21,6 +21,8 @@ type Sequence struct {
 	interpreter   parsley.Interpreter
 	customErr     error
 	resultHandler SeqResultHandler
+	traceID       int
+	named         bool
 }
 
 // Seq tries to apply all parsers after each other and returns with all combinations of the results.
@@ -32,6 +34,7 @@ func Seq(token string, parserLookUp func(int) parsley.Parser, lenCheck func(int)
 		token:        token,
 		parserLookUp: parserLookUp,
 		lenCheck:     lenCheck,
+		traceID:      parsley.NewTraceID(),
 	}
 }
 
@@ -45,6 +48,7 @@ func (s *Sequence) Name(name string) *Sequence {
 // Token sets the result token
 func (s *Sequence) Token(token string) *Sequence {
 	s.token = token
+	s.named = true
 	return s
 }
 
@@ -63,6 +67,13 @@ func (s *Sequence) Bind(interpreter parsley.Interpreter) *Sequence {
 
 // Parse parses the given input
 func (s *Sequence) Parse(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	if ctx.Tracer() == nil {
+		return s.parse(ctx, leftRecCtx, pos)
+	}
+	return parsley.Trace(s.traceID, s.token, s.named, parser.Func(s.parse), ctx, leftRecCtx, pos)
+}
+
+func (s *Sequence) parse(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
 	p := &sequence{
 		token:             s.token,
 		parserLookUp:      s.parserLookUp,
@@ -70,6 +81,7 @@ func (s *Sequence) Parse(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsl
 		interpreter:       s.interpreter,
 		curtailingParsers: data.EmptyIntSet,
 		nodes:             nil,
+		cutDepth:          -1,
 	}
 
 	if s.resultHandler != nil {
@@ -78,9 +90,11 @@ func (s *Sequence) Parse(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsl
 		p.resultHandler = seqDefaultResultHandler(false)
 	}
 
+	notFoundErrs := ctx.NotFoundErrors()
 	res, cp, err := p.Parse(ctx, leftRecCtx, pos)
-	if err != nil && s.customErr != nil && err.Pos() == pos && parsley.IsNotFoundError(err) {
+	if err != nil && s.customErr != nil && err.Pos() == pos && parsley.IsNotFoundError(err) && !parsley.IsCutError(err) {
 		err = parsley.NewError(pos, s.customErr)
+		ctx.OverrideNotFoundErrors(notFoundErrs, err)
 	}
 
 	return res, cp, err
@@ -96,11 +110,17 @@ type sequence struct {
 	err               parsley.Error
 	nodes             []parsley.Node
 	resultHandler     SeqResultHandler
+	cutDepth          int
+	cutErr            parsley.Error
 }
 
 // Parse runs the recursive parser
 func (s *sequence) Parse(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
 	s.parse(0, ctx, leftRecCtx, pos, true)
+	if s.cutErr != nil {
+		return nil, s.curtailingParsers, s.cutErr
+	}
+
 	if s.result == nil {
 		return nil, s.curtailingParsers, s.err
 	}
@@ -118,8 +138,21 @@ func (s *sequence) parse(depth int, ctx *parsley.Context, leftRecCtx data.IntMap
 	var err parsley.Error
 	nextParser := s.parserLookUp(depth)
 	if nextParser != nil {
+		if _, isCut := nextParser.(cut); isCut && s.cutDepth < 0 {
+			s.cutDepth = depth
+		}
+
+		// If the sequence can end here then the nodes parsed so far are returned if the next parser fails
+		canEnd := s.lenCheck(depth)
+		if canEnd {
+			ctx.EnterBacktrackPoint(pos)
+		}
 		ctx.RegisterCall()
 		res, cp, err = nextParser.Parse(ctx, leftRecCtx, pos)
+		if canEnd {
+			ctx.ExitBacktrackPoint()
+		}
+		ctx.RegisterNotFoundError(err)
 		if err != nil && (s.err == nil || err.Pos() >= s.err.Pos()) {
 			s.err = err
 		}
@@ -129,14 +162,23 @@ func (s *sequence) parse(depth int, ctx *parsley.Context, leftRecCtx data.IntMap
 		s.curtailingParsers = s.curtailingParsers.Union(cp)
 	}
 
+	// A failure after a cut is a hard error, the other combinations are not tried
+	if res == nil && err != nil && (parsley.IsCutError(err) || s.cutDepth >= 0 && depth > s.cutDepth && !s.lenCheck(depth)) {
+		s.cutErr = parsley.NewCutError(err)
+		return true
+	}
+
 	if res != nil {
 		switch rest := res.(type) {
 		case ast.NodeList:
+			ctx.EnterBacktrackPoint(lowestReaderPos(rest))
 			for i, node := range rest {
 				if s.parseNext(i, node, depth, ctx, leftRecCtx, pos, mergeCurtailingParsers) {
+					ctx.ExitBacktrackPoint()
 					return true
 				}
 			}
+			ctx.ExitBacktrackPoint()
 		default:
 			if s.parseNext(0, rest, depth, ctx, leftRecCtx, pos, mergeCurtailingParsers) {
 				return true
@@ -176,6 +218,18 @@ func (s *sequence) parseNext(i int, node parsley.Node, depth int, ctx *parsley.C
 	return false
 }
 
+// lowestReaderPos returns with the lowest reader position of the nodes
+// The parsing continues after every node, so the input from this position is needed until all nodes are processed.
+func lowestReaderPos(nodes ast.NodeList) parsley.Pos {
+	pos := nodes[0].ReaderPos()
+	for _, node := range nodes[1:] {
+		if node.ReaderPos() < pos {
+			pos = node.ReaderPos()
+		}
+	}
+	return pos
+}
+
 // SeqOf tries to apply all parsers after each other and returns with all combinations of the results.
 // Only matches are returned where all parsers were applied successfully.
 func SeqOf(parsers ...parsley.Parser) *Sequence {
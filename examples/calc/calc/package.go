@@ -0,0 +1,10 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package calc contains a calculator parser generated from the calc.grammar file
+package calc
+
+//go:generate go run github.com/conflowio/parsley/cmd/parsley-gen -grammar calc.grammar -start sum -output parser.go
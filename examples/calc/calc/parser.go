@@ -0,0 +1,131 @@
+// Code generated by parsley-gen from calc.grammar. DO NOT EDIT.
+
+package calc
+
+import (
+	"strconv"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+// NewParser returns with a parser for the "sum" rule
+// The interpreters are bound to the rules by name, the rules without an interpreter are evaluated as a list.
+func NewParser(interpreters map[string]parsley.Interpreter, terminals map[string]parsley.Parser) parsley.Parser {
+	list := ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+		children := node.Children()
+		res := make([]interface{}, 0, len(children))
+		for _, child := range children {
+			if _, empty := child.(ast.EmptyNode); empty {
+				res = append(res, nil)
+				continue
+			}
+			value, err := parsley.EvaluateNode(userCtx, child)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, value)
+		}
+		return res, nil
+	})
+
+	bind := func(s *combinator.Sequence, rule string) *combinator.Sequence {
+		if interpreter, ok := interpreters[rule]; ok {
+			return s.Bind(interpreter)
+		}
+		return s.HandleResult(combinator.ReturnSingle()).Bind(list)
+	}
+
+	term1 := text.LeftTrim(parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		if readerPos, found := ctx.Reader().(*text.Reader).MatchString(pos, "+"); found {
+			return terminal.NewOpNode("+", pos, readerPos), data.EmptyIntSet, nil
+		}
+		return nil, data.EmptyIntSet, parsley.NewError(pos, parsley.NotFoundError(`"+"`))
+	}).Trace("+"), text.WsSpacesNl)
+	term2 := text.LeftTrim(parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		if readerPos, found := ctx.Reader().(*text.Reader).MatchString(pos, "-"); found {
+			return terminal.NewOpNode("-", pos, readerPos), data.EmptyIntSet, nil
+		}
+		return nil, data.EmptyIntSet, parsley.NewError(pos, parsley.NotFoundError(`"-"`))
+	}).Trace("-"), text.WsSpacesNl)
+	term3 := text.LeftTrim(parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		if readerPos, found := ctx.Reader().(*text.Reader).MatchString(pos, "*"); found {
+			return terminal.NewOpNode("*", pos, readerPos), data.EmptyIntSet, nil
+		}
+		return nil, data.EmptyIntSet, parsley.NewError(pos, parsley.NotFoundError(`"*"`))
+	}).Trace("*"), text.WsSpacesNl)
+	term4 := text.LeftTrim(parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		if readerPos, found := ctx.Reader().(*text.Reader).MatchString(pos, "/"); found {
+			return terminal.NewOpNode("/", pos, readerPos), data.EmptyIntSet, nil
+		}
+		return nil, data.EmptyIntSet, parsley.NewError(pos, parsley.NotFoundError(`"/"`))
+	}).Trace("/"), text.WsSpacesNl)
+	term5 := text.LeftTrim(parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		tr := ctx.Reader().(*text.Reader)
+		if readerPos, result := tr.ReadRegexp(pos, "[-+]?[0-9]*\\.[0-9]+(?:[eE][-+]?[0-9]+)?"); result != nil {
+			value, err := strconv.ParseFloat(string(result), 64)
+			if err != nil {
+				return nil, data.EmptyIntSet, parsley.NewErrorf(pos, "invalid float value")
+			}
+			return terminal.NewFloatNode("float", value, pos, readerPos), data.EmptyIntSet, nil
+		}
+		return nil, data.EmptyIntSet, parsley.NewError(pos, parsley.NotFoundError("float value"))
+	}).Trace("FLOAT"), text.WsSpacesNl)
+	term6 := text.LeftTrim(parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		tr := ctx.Reader().(*text.Reader)
+		if readerPos, result := tr.ReadRegexp(pos, "[-+]?(?:[1-9][0-9]*|0[xX][0-9a-fA-F]+|0[0-7]*)"); result != nil {
+			if _, isFloat := tr.ReadRune(readerPos, '.'); !isFloat {
+				value, err := strconv.ParseInt(string(result), 0, 64)
+				if err != nil {
+					panic("Could not convert " + string(result) + " to integer")
+				}
+				return terminal.NewIntegerNode("integer", value, pos, readerPos), data.EmptyIntSet, nil
+			}
+		}
+		return nil, data.EmptyIntSet, parsley.NewError(pos, parsley.NotFoundError("integer value"))
+	}).Trace("INTEGER"), text.WsSpacesNl)
+	term7 := text.LeftTrim(parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		if readerPos, found := ctx.Reader().(*text.Reader).MatchString(pos, "("); found {
+			return terminal.NewOpNode("(", pos, readerPos), data.EmptyIntSet, nil
+		}
+		return nil, data.EmptyIntSet, parsley.NewError(pos, parsley.NotFoundError(`"("`))
+	}).Trace("("), text.WsSpacesNl)
+	term8 := text.LeftTrim(parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		if readerPos, found := ctx.Reader().(*text.Reader).MatchString(pos, ")"); found {
+			return terminal.NewOpNode(")", pos, readerPos), data.EmptyIntSet, nil
+		}
+		return nil, data.EmptyIntSet, parsley.NewError(pos, parsley.NotFoundError(`")"`))
+	}).Trace(")"), text.WsSpacesNl)
+
+	memo := combinator.NewMemoRegistry()
+
+	var (
+		rule_sum     parser.Func
+		rule_product parser.Func
+		rule_value   parser.Func
+	)
+
+	rule_sum = memo.MemoizeNamed("sum", combinator.Any(
+		bind(combinator.SeqOf(&rule_sum, combinator.Choice(term1, term2), &rule_product).Token("sum"), "sum"),
+		bind(combinator.SeqOf(&rule_product).Token("sum"), "sum"),
+	))
+
+	rule_product = memo.MemoizeNamed("product", combinator.Any(
+		bind(combinator.SeqOf(&rule_product, combinator.Choice(term3, term4), &rule_value).Token("product"), "product"),
+		bind(combinator.SeqOf(&rule_value).Token("product"), "product"),
+	))
+
+	rule_value = combinator.Choice(
+		bind(combinator.SeqOf(term5).Token("value"), "value"),
+		bind(combinator.SeqOf(term6).Token("value"), "value"),
+		bind(combinator.SeqOf(term7, &rule_sum, term8).Token("value"), "value"),
+		bind(combinator.SeqOf(term2, &rule_value).Token("value"), "value"),
+	)
+
+	return rule_sum
+}
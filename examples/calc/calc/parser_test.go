@@ -0,0 +1,105 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package calc_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/examples/calc/calc"
+	"github.com/conflowio/parsley/grammar"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+)
+
+func toFloat(v interface{}) float64 {
+	if i, ok := v.(int64); ok {
+		return float64(i)
+	}
+	return v.(float64)
+}
+
+var binary = ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+	children := node.Children()
+	a, err := parsley.EvaluateNode(userCtx, children[0])
+	if err != nil || len(children) == 1 {
+		return a, err
+	}
+	op, _ := parsley.EvaluateNode(userCtx, children[1])
+	b, err := parsley.EvaluateNode(userCtx, children[2])
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case "+":
+		return toFloat(a) + toFloat(b), nil
+	case "-":
+		return toFloat(a) - toFloat(b), nil
+	case "*":
+		return toFloat(a) * toFloat(b), nil
+	default:
+		return toFloat(a) / toFloat(b), nil
+	}
+})
+
+var value = ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+	children := node.Children()
+	switch len(children) {
+	case 1:
+		return parsley.EvaluateNode(userCtx, children[0])
+	case 2:
+		v, err := parsley.EvaluateNode(userCtx, children[1])
+		if err != nil {
+			return nil, err
+		}
+		return -toFloat(v), nil
+	default:
+		return parsley.EvaluateNode(userCtx, children[1])
+	}
+})
+
+func ExampleNewParser() {
+	p := calc.NewParser(map[string]parsley.Interpreter{"sum": binary, "product": binary, "value": value}, nil)
+
+	f := text.NewFile("example.file", []byte("-2 * (3 + 4.5) / 5 - 1"))
+	ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+	result, err := parsley.Evaluate(ctx, combinator.Sentence(text.RightTrim(p, text.WsSpacesNl)))
+	fmt.Println(result, err)
+	// Output: -4 <nil>
+}
+
+func TestNewParserShouldMatchTheGrammar(t *testing.T) {
+	definition, err := os.ReadFile("calc.grammar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := grammar.Parse("calc.grammar", definition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := g.Parser("sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parse := func(p parsley.Parser, input string) (parsley.Node, error) {
+		f := text.NewFile("testfile", []byte(input))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		return parsley.Parse(ctx, combinator.Sentence(text.RightTrim(p, text.WsSpacesNl)))
+	}
+
+	for _, input := range []string{"1", "1 + 2 * 3", "(1 - 2) / -3.5", "1 +", "1 2", ")"} {
+		expectedNode, expectedErr := parse(expected, input)
+		node, err := parse(calc.NewParser(nil, nil), input)
+		if fmt.Sprint(node) != fmt.Sprint(expectedNode) || fmt.Sprint(err) != fmt.Sprint(expectedErr) {
+			t.Errorf("%q: expected %v %v, got %v %v", input, expectedNode, expectedErr, node, err)
+		}
+	}
+}
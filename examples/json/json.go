@@ -7,26 +7,32 @@
 // This is a JSON parser example. It's not a strict implementation as it was written only for demonstration. For most JSON strings it should still be able to parse the input.
 //
 // You can run this file to see the parser in action:
-//  go run json.go
+//  go run json.go [-profile] [file]
 // By default the included example.json file will be used and the output will be:
-//  Parser calls: 240
+//  Parser calls: 118
 //  map[title:Person type:object properties:map[firstName:map[type:string] lastName:map[type:string] age:map[description:Age in years type:integer minimum:0]] required:[firstName lastName]]
+// With -profile the call counts and timings per parser are also printed.
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
 	"github.com/conflowio/parsley/combinator"
 	"github.com/conflowio/parsley/examples/json/json"
 	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/profile"
 	"github.com/conflowio/parsley/text"
 )
 
 func main() {
+	profileEnabled := flag.Bool("profile", false, "print the parser statistics")
+	flag.Parse()
+
 	jsonFilePath := "example.json"
-	if len(os.Args) > 1 {
-		jsonFilePath = os.Args[1]
+	if flag.NArg() > 0 {
+		jsonFilePath = flag.Arg(0)
 	}
 	file, err := text.ReadFile(jsonFilePath)
 	if err != nil {
@@ -36,6 +42,10 @@ func main() {
 
 	reader := text.NewReader(file)
 	ctx := parsley.NewContext(fs, reader)
+	profiler := profile.New()
+	if *profileEnabled {
+		ctx.SetTracer(profiler)
+	}
 	s := combinator.Sentence(text.Trim(json.NewParser()))
 
 	res, evalErr := parsley.Evaluate(ctx, s)
@@ -43,5 +53,10 @@ func main() {
 		panic(evalErr)
 	}
 	fmt.Printf("Parser calls: %d\n", ctx.CallCount())
+	if *profileEnabled {
+		if err := profiler.WriteReport(os.Stdout); err != nil {
+			panic(err)
+		}
+	}
 	fmt.Printf("%v\n", res)
 }
@@ -17,16 +17,29 @@ import (
 
 // NewParser returns with a new JSON parser
 func NewParser() parsley.Parser {
+	return newParser(false)
+}
+
+// NewMemoizedParser returns with a new JSON parser where the values are memoized
+// JSON doesn't need memoization, it's only used for benchmarking the result caches.
+func NewMemoizedParser() parsley.Parser {
+	return newParser(true)
+}
+
+func newParser(memoize bool) parsley.Parser {
 	var value parser.Func
 
-	array := combinator.SeqOf(
+	closeArray := text.LeftTrim(terminal.Rune(']'), text.WsSpacesNl)
+	closeObject := text.LeftTrim(terminal.Rune('}'), text.WsSpacesNl)
+
+	array := combinator.Between(
 		terminal.Rune('['),
 		combinator.SepBy(
 			text.LeftTrim(&value, text.WsSpacesNl),
 			text.LeftTrim(terminal.Rune(','), text.WsSpaces),
 		).Bind(interpreter.Array()),
-		text.LeftTrim(terminal.Rune(']'), text.WsSpacesNl),
-	).Bind(interpreter.Select(1))
+		closeArray,
+	).Closers(closeObject)
 
 	keyValue := combinator.SeqOf(
 		terminal.String("string", false),
@@ -34,14 +47,14 @@ func NewParser() parsley.Parser {
 		text.LeftTrim(&value, text.WsSpacesNl),
 	)
 
-	object := combinator.SeqOf(
+	object := combinator.Between(
 		terminal.Rune('{'),
 		combinator.SepBy(
 			text.LeftTrim(keyValue, text.WsSpacesNl),
 			text.LeftTrim(terminal.Rune(','), text.WsSpaces),
 		).Bind(interpreter.Object()),
-		text.LeftTrim(terminal.Rune('}'), text.WsSpacesNl),
-	).Bind(interpreter.Select(1))
+		closeObject,
+	).Closers(closeArray)
 
 	value = combinator.Choice(
 		terminal.String("string", false),
@@ -53,5 +66,9 @@ func NewParser() parsley.Parser {
 		terminal.Nil("null", "null"),
 	).Name("value")
 
+	if memoize {
+		value = combinator.Memoize(value)
+	}
+
 	return value
 }
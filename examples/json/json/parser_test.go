@@ -7,6 +7,7 @@
 package json_test
 
 import (
+	"fmt"
 	"io/ioutil"
 	"testing"
 
@@ -18,6 +19,21 @@ import (
 	"github.com/conflowio/parsley/text"
 )
 
+// The error message will contain all the expected inputs, or the opening bracket if a closing bracket is missing
+func ExampleNewParser() {
+	for _, input := range []string{`{"a": [1 2]}`, `{"a": [1, 2 }`, `[1, 2 }`, "{\n  \"a\": [1, 2\n"} {
+		f := text.NewFile("example.json", []byte(input))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		_, err := parsley.Evaluate(ctx, combinator.Sentence(text.Trim(json.NewParser())))
+		fmt.Println(err)
+	}
+	// Output:
+	// failed to parse the input: was expecting "," or "]", found "2" at example.json:1:10
+	// failed to parse the input: unclosed '[' opened at example.json:1:7, found '}' at example.json:1:13
+	// failed to parse the input: unclosed '[' opened at example.json:1:1, found '}' at example.json:1:7
+	// failed to parse the input: unclosed '[' opened at example.json:2:8 at example.json:3:1
+}
+
 func benchmarkParsleyJSON(b *testing.B, jsonFilePath string) {
 	f, err := text.ReadFile(jsonFilePath)
 	if err != nil {
@@ -42,6 +58,44 @@ func BenchmarkParsleyJSON1k(b *testing.B)   { benchmarkParsleyJSON(b, "../exampl
 func BenchmarkParsleyJSON10k(b *testing.B)  { benchmarkParsleyJSON(b, "../example_10k.json") }
 func BenchmarkParsleyJSON100k(b *testing.B) { benchmarkParsleyJSON(b, "../example_100k.json") }
 
+func benchmarkParsleyJSONResultCache(b *testing.B, jsonFilePath string, newResultCache func() parsley.ResultCache) {
+	f, err := text.ReadFile(jsonFilePath)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	s := combinator.Sentence(text.Trim(json.NewMemoizedParser()))
+	r := text.NewReader(f)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ctx := parsley.NewContext(parsley.NewFileSet(f), r)
+		ctx.SetResultCache(newResultCache())
+		if _, err := parsley.Evaluate(ctx, s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParsleyJSONMapResultCache1k(b *testing.B) {
+	benchmarkParsleyJSONResultCache(b, "../example_1k.json", parsley.NewResultCache)
+}
+func BenchmarkParsleyJSONMapResultCache10k(b *testing.B) {
+	benchmarkParsleyJSONResultCache(b, "../example_10k.json", parsley.NewResultCache)
+}
+func BenchmarkParsleyJSONMapResultCache100k(b *testing.B) {
+	benchmarkParsleyJSONResultCache(b, "../example_100k.json", parsley.NewResultCache)
+}
+func BenchmarkParsleyJSONDenseResultCache1k(b *testing.B) {
+	benchmarkParsleyJSONResultCache(b, "../example_1k.json", parsley.NewDenseResultCache)
+}
+func BenchmarkParsleyJSONDenseResultCache10k(b *testing.B) {
+	benchmarkParsleyJSONResultCache(b, "../example_10k.json", parsley.NewDenseResultCache)
+}
+func BenchmarkParsleyJSONDenseResultCache100k(b *testing.B) {
+	benchmarkParsleyJSONResultCache(b, "../example_100k.json", parsley.NewDenseResultCache)
+}
+
 func benchmarkEncodingJSON(b *testing.B, jsonFilePath string) {
 	input, err := ioutil.ReadFile(jsonFilePath)
 	if err != nil {
@@ -0,0 +1,261 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package grammar
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/conflowio/parsley/text"
+)
+
+// BuilderAnnotation is the comment which marks a grammar builder in a Go file, see ReadBuilder
+const BuilderAnnotation = "//parsley:grammar"
+
+const (
+	grammarImportPath = "github.com/conflowio/parsley/grammar"
+	textImportPath    = "github.com/conflowio/parsley/text"
+)
+
+var builderWsModes = map[string]text.WsMode{
+	"WsNone":          text.WsNone,
+	"WsSpaces":        text.WsSpaces,
+	"WsSpacesNl":      text.WsSpacesNl,
+	"WsSpacesForceNl": text.WsSpacesForceNl,
+}
+
+// ReadBuilder reads a grammar defined by an annotated Go builder
+//
+// The builder is a function returning with the grammar or a variable, marked with the BuilderAnnotation comment:
+//
+//	//parsley:grammar
+//	func calcGrammar() *grammar.Grammar {
+//		return grammar.New("calc").
+//			Rule("sum", `sum ("+" | "-") product | product`).
+//			Rule("product", `product ("*" | "/") value | value`).
+//			Rule("value", `FLOAT | INTEGER | "(" sum ")" | "-" value`)
+//	}
+//
+// The Go code is not executed, the New, Rule, Terminal, WsMode and Bind calls are read from the syntax tree, so the
+// names and the rule definitions have to be string literals. The custom terminals are only registered by name and the
+// interpreters are ignored, as the generated constructor gets them by name. The returned grammar can be used for
+// generating code, but its Parser method returns an error if a custom terminal is referenced.
+//
+// If the file contains multiple builders then the name of the function or the variable has to be given.
+func ReadBuilder(filename string, name string) (*Grammar, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &builderReader{
+		fset:       fset,
+		grammarPkg: importName(f, grammarImportPath, "grammar"),
+		textPkg:    importName(f, textImportPath, "text"),
+	}
+
+	var builders []ast.Expr
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !isAnnotated(d.Doc) || name != "" && d.Name.Name != name {
+				continue
+			}
+			expr, err := r.returnedExpr(d)
+			if err != nil {
+				return nil, err
+			}
+			builders = append(builders, expr)
+		case *ast.GenDecl:
+			if d.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range d.Specs {
+				vs := spec.(*ast.ValueSpec)
+				if !isAnnotated(d.Doc) && !isAnnotated(vs.Doc) {
+					continue
+				}
+				for i, ident := range vs.Names {
+					if (name == "" || ident.Name == name) && i < len(vs.Values) {
+						builders = append(builders, vs.Values[i])
+					}
+				}
+			}
+		}
+	}
+
+	switch {
+	case len(builders) == 0 && name != "":
+		return nil, fmt.Errorf("%s: the grammar builder %q was not found", filename, name)
+	case len(builders) == 0:
+		return nil, fmt.Errorf("%s: no grammar builder was found, it should be annotated with %s", filename, BuilderAnnotation)
+	case len(builders) > 1:
+		return nil, fmt.Errorf("%s: there are multiple grammar builders, the name of the builder is required", filename)
+	}
+
+	g, err := r.build(builders[0])
+	if err != nil {
+		return nil, err
+	}
+
+	// The generated code refers to the Go file
+	g.filename = filename
+
+	return g, nil
+}
+
+type builderReader struct {
+	fset       *token.FileSet
+	grammarPkg string
+	textPkg    string
+}
+
+func (r *builderReader) returnedExpr(d *ast.FuncDecl) (ast.Expr, error) {
+	if d.Body != nil && len(d.Body.List) > 0 {
+		if ret, ok := d.Body.List[len(d.Body.List)-1].(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+			return ret.Results[0], nil
+		}
+	}
+	return nil, r.errorf(d, "the grammar builder %s should return with the grammar", d.Name.Name)
+}
+
+// build creates the grammar by replaying the method calls of the builder chain
+func (r *builderReader) build(expr ast.Expr) (*Grammar, error) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil, r.errorf(expr, "the grammar builder should be a method chain starting with %s.New", r.grammarPkg)
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, r.errorf(call, "the grammar builder should be a method chain starting with %s.New", r.grammarPkg)
+	}
+
+	if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == r.grammarPkg && sel.Sel.Name == "New" {
+		if len(call.Args) != 1 {
+			return nil, r.errorf(call, "%s.New should have one argument", r.grammarPkg)
+		}
+		name, err := r.stringValue(call.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		return New(name), nil
+	}
+
+	g, err := r.build(sel.X)
+	if err != nil {
+		return nil, err
+	}
+
+	switch sel.Sel.Name {
+	case "Rule":
+		if len(call.Args) != 2 {
+			return nil, r.errorf(call, "Rule should have two arguments")
+		}
+		name, err := r.stringValue(call.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		definition, err := r.stringValue(call.Args[1])
+		if err != nil {
+			return nil, err
+		}
+		if err := g.addRule(name, definition); err != nil {
+			return nil, r.errorf(call, "%s", err)
+		}
+	case "Terminal":
+		if len(call.Args) != 2 {
+			return nil, r.errorf(call, "Terminal should have two arguments")
+		}
+		name, err := r.stringValue(call.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		g.terminals[name] = nil
+	case "WsMode":
+		if len(call.Args) != 1 {
+			return nil, r.errorf(call, "WsMode should have one argument")
+		}
+		wsMode, err := r.wsMode(call.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		g.WsMode(wsMode)
+	case "Bind":
+	default:
+		return nil, r.errorf(call, "unsupported grammar builder method: %s", sel.Sel.Name)
+	}
+
+	return g, nil
+}
+
+// stringValue returns with the value of a string literal or a concatenation of string literals
+func (r *builderReader) stringValue(expr ast.Expr) (string, error) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.STRING {
+			return strconv.Unquote(e.Value)
+		}
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			x, err := r.stringValue(e.X)
+			if err != nil {
+				return "", err
+			}
+			y, err := r.stringValue(e.Y)
+			if err != nil {
+				return "", err
+			}
+			return x + y, nil
+		}
+	case *ast.ParenExpr:
+		return r.stringValue(e.X)
+	}
+	return "", r.errorf(expr, "a string literal was expected")
+}
+
+func (r *builderReader) wsMode(expr ast.Expr) (text.WsMode, error) {
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == r.textPkg {
+			if wsMode, ok := builderWsModes[sel.Sel.Name]; ok {
+				return wsMode, nil
+			}
+		}
+	}
+	return 0, r.errorf(expr, "one of the %s.Ws* constants was expected", r.textPkg)
+}
+
+func (r *builderReader) errorf(node ast.Node, format string, values ...interface{}) error {
+	return fmt.Errorf("%s: %s", r.fset.Position(node.Pos()), fmt.Sprintf(format, values...))
+}
+
+func isAnnotated(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == BuilderAnnotation {
+			return true
+		}
+	}
+	return false
+}
+
+// importName returns with the name of an imported package in the file
+func importName(f *ast.File, path string, defaultName string) string {
+	for _, spec := range f.Imports {
+		if spec.Path.Value == strconv.Quote(path) && spec.Name != nil {
+			return spec.Name.Name
+		}
+	}
+	return defaultName
+}
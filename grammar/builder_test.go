@@ -0,0 +1,227 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package grammar_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/grammar"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+)
+
+var _ = Describe("Rule", func() {
+	It("should add the rules to the grammar", func() {
+		g := grammar.New("test").
+			Rule("sum", `sum "+" value | value`).
+			Rule("value", `INTEGER | "(" sum ")"`)
+		Expect(g.RuleNames()).To(Equal([]string{"sum", "value"}))
+
+		p, err := g.Parser("sum")
+		Expect(err).ToNot(HaveOccurred())
+
+		f := text.NewFile("input", []byte("1 + (2 + 3)"))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		_, err = parsley.Parse(ctx, p)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should panic if the definition is invalid", func() {
+		Expect(func() { grammar.New("test").Rule("sum", `sum "+`) }).To(Panic())
+	})
+
+	It("should panic if the definition contains multiple rules", func() {
+		Expect(func() { grammar.New("test").Rule("a", `"x" ; b = "y"`) }).To(Panic())
+	})
+
+	It("should panic if the rule is already defined", func() {
+		Expect(func() { grammar.New("test").Rule("a", `"x"`).Rule("a", `"y"`) }).To(Panic())
+	})
+})
+
+var _ = Describe("ReadBuilder", func() {
+
+	var (
+		dir      string
+		filename string
+		source   string
+		name     string
+		g        *grammar.Grammar
+		err      error
+	)
+
+	BeforeEach(func() {
+		var tmpErr error
+		dir, tmpErr = ioutil.TempDir("", "parsley-builder")
+		Expect(tmpErr).ToNot(HaveOccurred())
+		filename = filepath.Join(dir, "grammar.go")
+		name = ""
+		source = `package calc
+
+import (
+	"github.com/conflowio/parsley/grammar"
+	"github.com/conflowio/parsley/text"
+)
+
+//parsley:grammar
+func calcGrammar() *grammar.Grammar {
+	return grammar.New("calc").
+		Rule("sum", ` + "`" + `sum ("+" | "-") product | product` + "`" + `).
+		Rule("product", "product (\"*\" | \"/\") value | " + "value").
+		Rule("value", ` + "`" + `FLOAT | INTEGER | ID | "(" sum ")" | "-" value` + "`" + `).
+		Terminal("ID", nil).
+		Bind("sum", nil).
+		WsMode(text.WsSpaces)
+}
+`
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	JustBeforeEach(func() {
+		Expect(ioutil.WriteFile(filename, []byte(source), 0644)).To(Succeed())
+		g, err = grammar.ReadBuilder(filename, name)
+	})
+
+	It("should read the rules", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(g.RuleNames()).To(Equal([]string{"sum", "product", "value"}))
+		Expect(g.LeftRecursiveRules()).To(Equal([]string{"product", "sum"}))
+	})
+
+	It("should generate the parser", func() {
+		code, err := g.Generate(grammar.GenerateOptions{Package: "calc", Start: "sum"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(code)).To(HavePrefix("// Code generated by parsley-gen from grammar.go. DO NOT EDIT.\n"))
+		Expect(string(code)).To(ContainSubstring(`customTerminal("ID")`))
+		Expect(string(code)).To(ContainSubstring("text.WsSpaces)"))
+	})
+
+	It("should not create a parser with the custom terminals", func() {
+		_, err := g.Parser("sum")
+		Expect(err).To(MatchError(ContainSubstring(`the terminal "ID" has no parser`)))
+	})
+
+	Context("when the builder is a variable", func() {
+		BeforeEach(func() {
+			source = `package calc
+
+import g "github.com/conflowio/parsley/grammar"
+
+//parsley:grammar
+var calcGrammar = g.New("calc").Rule("value", "INTEGER")
+`
+		})
+
+		It("should read the rules", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(g.RuleNames()).To(Equal([]string{"value"}))
+		})
+	})
+
+	Context("when there are multiple builders", func() {
+		BeforeEach(func() {
+			source = `package calc
+
+import "github.com/conflowio/parsley/grammar"
+
+//parsley:grammar
+var a = grammar.New("a").Rule("a", "INTEGER")
+
+//parsley:grammar
+var b = grammar.New("b").Rule("b", "FLOAT")
+`
+		})
+
+		It("should return an error", func() {
+			Expect(err).To(MatchError(ContainSubstring("there are multiple grammar builders")))
+		})
+
+		Context("when the name is given", func() {
+			BeforeEach(func() {
+				name = "b"
+			})
+
+			It("should read the selected builder", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(g.RuleNames()).To(Equal([]string{"b"}))
+			})
+		})
+	})
+
+	Context("when there is no annotated builder", func() {
+		BeforeEach(func() {
+			source = `package calc
+
+import "github.com/conflowio/parsley/grammar"
+
+var a = grammar.New("a").Rule("a", "INTEGER")
+`
+		})
+
+		It("should return an error", func() {
+			Expect(err).To(MatchError(ContainSubstring("no grammar builder was found")))
+		})
+	})
+
+	Context("when a rule definition is not a string literal", func() {
+		BeforeEach(func() {
+			source = `package calc
+
+import "github.com/conflowio/parsley/grammar"
+
+var def = "INTEGER"
+
+//parsley:grammar
+var a = grammar.New("a").Rule("a", def)
+`
+		})
+
+		It("should return an error with the position", func() {
+			Expect(err).To(MatchError(filename + ":8:36: a string literal was expected"))
+		})
+	})
+
+	Context("when an unsupported method is called", func() {
+		BeforeEach(func() {
+			source = `package calc
+
+import "github.com/conflowio/parsley/grammar"
+
+//parsley:grammar
+var a = grammar.New("a").Rule("a", "INTEGER").Other()
+`
+		})
+
+		It("should return an error with the position", func() {
+			Expect(err).To(MatchError(filename + ":6:9: unsupported grammar builder method: Other"))
+		})
+	})
+
+	Context("when a rule definition is invalid", func() {
+		BeforeEach(func() {
+			source = `package calc
+
+import "github.com/conflowio/parsley/grammar"
+
+//parsley:grammar
+var a = grammar.New("a").Rule("a", "INTEGER |")
+`
+		})
+
+		It("should return an error with the position", func() {
+			Expect(err).To(MatchError(HavePrefix(filename + ":6:9: ")))
+		})
+	})
+})
@@ -0,0 +1,348 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package grammar
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+var identifierPattern = regexp.MustCompile("^" + identifierRegexp + "$")
+
+// builtinTerminals contains the terminals which can be referenced in any grammar
+var builtinTerminals = map[string]func() parsley.Parser{
+	"STRING":        func() parsley.Parser { return terminal.String("string", false) },
+	"INTEGER":       func() parsley.Parser { return terminal.Integer("integer") },
+	"FLOAT":         func() parsley.Parser { return terminal.Float("float") },
+	"BOOL":          func() parsley.Parser { return terminal.Bool("bool", "true", "false") },
+	"NIL":           func() parsley.Parser { return terminal.Nil("nil", "nil") },
+	"CHAR":          func() parsley.Parser { return terminal.Char("char") },
+	"TIME_DURATION": func() parsley.Parser { return terminal.TimeDuration("time_duration") },
+	"EOF":           func() parsley.Parser { return parser.End() },
+}
+
+type compiler struct {
+	grammar  *Grammar
+	rules    map[string]*rule
+	parsers  map[string]parsley.Parser
+	nullable map[string]bool
+	memo     *combinator.MemoRegistry
+}
+
+func newCompiler(g *Grammar) *compiler {
+	rules := make(map[string]*rule, len(g.rules))
+	for _, r := range g.rules {
+		rules[r.name] = r
+	}
+	c := &compiler{
+		grammar: g,
+		rules:   rules,
+		parsers: make(map[string]parsley.Parser, len(g.rules)),
+		memo:    combinator.NewMemoRegistry(),
+	}
+	c.nullable = c.nullableRules()
+	return c
+}
+
+// compile creates the parsers for all rules
+func (c *compiler) compile() error {
+	leftRecursive := c.leftRecursiveRules()
+
+	for _, r := range c.grammar.rules {
+		p, err := c.compileRule(r, leftRecursive[r.name])
+		if err != nil {
+			return err
+		}
+		c.parsers[r.name] = p
+	}
+
+	return nil
+}
+
+func (c *compiler) compileRule(r *rule, leftRecursive bool) (parsley.Parser, error) {
+	alternatives, ok := r.expr.(choice)
+	if !ok {
+		alternatives = choice{r.expr}
+	}
+
+	parsers := make([]parsley.Parser, len(alternatives))
+	for i, alternative := range alternatives {
+		items, ok := alternative.(sequence)
+		if !ok {
+			items = sequence{alternative}
+		}
+
+		itemParsers, err := c.compileExpressions(items)
+		if err != nil {
+			return nil, err
+		}
+
+		p := combinator.SeqOf(itemParsers...).Token(r.name)
+		if interpreter, ok := c.grammar.interpreters[r.name]; ok {
+			p.Bind(interpreter)
+		} else {
+			p.HandleResult(combinator.ReturnSingle()).Bind(listInterpreter())
+		}
+		parsers[i] = p
+	}
+
+	var p parsley.Parser
+	switch {
+	case len(parsers) == 1:
+		p = parsers[0]
+	case leftRecursive:
+		// Choice would stop at the first match which might have been curtailed because of the left recursion
+		p = combinator.Any(parsers...)
+	default:
+		p = combinator.Choice(parsers...)
+	}
+
+	if leftRecursive {
+		return c.memo.MemoizeNamed(r.name, p), nil
+	}
+
+	return p, nil
+}
+
+func (c *compiler) compileExpressions(exprs []expression) ([]parsley.Parser, error) {
+	parsers := make([]parsley.Parser, len(exprs))
+	for i, expr := range exprs {
+		p, err := c.compileExpression(expr)
+		if err != nil {
+			return nil, err
+		}
+		parsers[i] = p
+	}
+	return parsers, nil
+}
+
+func (c *compiler) compileExpression(expr expression) (parsley.Parser, error) {
+	switch e := expr.(type) {
+	case choice:
+		parsers, err := c.compileExpressions(e)
+		if err != nil {
+			return nil, err
+		}
+		return combinator.Choice(parsers...), nil
+	case sequence:
+		parsers, err := c.compileExpressions(e)
+		if err != nil {
+			return nil, err
+		}
+		return combinator.SeqOf(parsers...).Bind(listInterpreter()), nil
+	case repetition:
+		p, err := c.compileExpression(e.expr)
+		if err != nil {
+			return nil, err
+		}
+		if c.isNullable(e.expr, c.nullable) {
+			if e.op == "?" {
+				return p, nil
+			}
+			return nil, c.errorf(e.pos, "the repeated expression should not match an empty input")
+		}
+		switch e.op {
+		case "*":
+			return combinator.Many(p).Bind(listInterpreter()), nil
+		case "+":
+			return combinator.Many1(p).Bind(listInterpreter()), nil
+		default:
+			return combinator.Optional(p), nil
+		}
+	case reference:
+		return c.compileReference(e)
+	case literal:
+		if err := c.checkLiteral(e); err != nil {
+			return nil, err
+		}
+		if identifierPattern.MatchString(e.value) {
+			return text.LeftTrim(terminal.Word(nil, e.value, e.value), c.grammar.wsMode), nil
+		}
+		return text.LeftTrim(terminal.Op(e.value), c.grammar.wsMode), nil
+	case pattern:
+		if err := c.checkPattern(e); err != nil {
+			return nil, err
+		}
+		return text.LeftTrim(terminal.Regexp(nil, "REGEXP", e.name(), e.regexp, 0), c.grammar.wsMode), nil
+	default:
+		panic(fmt.Sprintf("unexpected expression type: %T", expr))
+	}
+}
+
+func (c *compiler) compileReference(ref reference) (parsley.Parser, error) {
+	if p, ok := c.grammar.terminals[ref.name]; ok {
+		if p == nil {
+			return nil, c.errorf(ref.pos, "the terminal %q has no parser", ref.name)
+		}
+		return text.LeftTrim(p, c.grammar.wsMode), nil
+	}
+
+	if _, ok := c.rules[ref.name]; ok {
+		// The rule might not have been compiled yet, so we need to look it up lazily
+		return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+			return c.parsers[ref.name].Parse(ctx, leftRecCtx, pos)
+		}), nil
+	}
+
+	if f, ok := builtinTerminals[ref.name]; ok {
+		return text.LeftTrim(f(), c.grammar.wsMode), nil
+	}
+
+	return nil, c.errorf(ref.pos, "undefined rule or terminal %q", ref.name)
+}
+
+func (c *compiler) checkLiteral(l literal) error {
+	if l.value == "" {
+		return c.errorf(l.pos, "empty literals are not allowed")
+	}
+	return nil
+}
+
+func (c *compiler) checkPattern(p pattern) error {
+	rc, err := regexp.Compile("^(?:" + p.regexp + ")")
+	if err != nil {
+		return c.errorf(p.pos, "invalid regular expression: %s", err)
+	}
+	if rc.MatchString("") {
+		return c.errorf(p.pos, "regular expression %s should not match an empty input", p.name())
+	}
+	return nil
+}
+
+func (c *compiler) errorf(pos parsley.Pos, format string, values ...interface{}) error {
+	return c.grammar.fileSet.ErrorWithPosition(parsley.NewErrorf(pos, format, values...))
+}
+
+// leftRecursiveRules returns with the rules which can call themselves without consuming any input
+func (c *compiler) leftRecursiveRules() map[string]bool {
+	leftRefs := make(map[string]map[string]bool, len(c.rules))
+	for name, r := range c.rules {
+		leftRefs[name] = map[string]bool{}
+		c.collectLeftRefs(r.expr, c.nullable, leftRefs[name])
+	}
+
+	res := map[string]bool{}
+	for name := range c.rules {
+		visited := map[string]bool{}
+		queue := []string{name}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for ref := range leftRefs[current] {
+				if ref == name {
+					res[name] = true
+				}
+				if !visited[ref] {
+					visited[ref] = true
+					queue = append(queue, ref)
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// nullableRules returns with the rules which can match an empty input
+func (c *compiler) nullableRules() map[string]bool {
+	nullable := map[string]bool{}
+	for changed := true; changed; {
+		changed = false
+		for name, r := range c.rules {
+			if !nullable[name] && c.isNullable(r.expr, nullable) {
+				nullable[name] = true
+				changed = true
+			}
+		}
+	}
+	return nullable
+}
+
+func (c *compiler) isNullable(expr expression, nullable map[string]bool) bool {
+	switch e := expr.(type) {
+	case choice:
+		for _, alternative := range e {
+			if c.isNullable(alternative, nullable) {
+				return true
+			}
+		}
+		return false
+	case sequence:
+		for _, item := range e {
+			if !c.isNullable(item, nullable) {
+				return false
+			}
+		}
+		return true
+	case repetition:
+		return e.op != "+" || c.isNullable(e.expr, nullable)
+	case reference:
+		if _, ok := c.grammar.terminals[e.name]; ok {
+			return false
+		}
+		if _, ok := c.rules[e.name]; ok {
+			return nullable[e.name]
+		}
+		return e.name == "EOF"
+	default:
+		return false
+	}
+}
+
+// collectLeftRefs collects the rules which might be called at the starting position of the expression
+func (c *compiler) collectLeftRefs(expr expression, nullable map[string]bool, refs map[string]bool) {
+	switch e := expr.(type) {
+	case choice:
+		for _, alternative := range e {
+			c.collectLeftRefs(alternative, nullable, refs)
+		}
+	case sequence:
+		for _, item := range e {
+			c.collectLeftRefs(item, nullable, refs)
+			if !c.isNullable(item, nullable) {
+				return
+			}
+		}
+	case repetition:
+		c.collectLeftRefs(e.expr, nullable, refs)
+	case reference:
+		if _, ok := c.grammar.terminals[e.name]; ok {
+			return
+		}
+		if _, ok := c.rules[e.name]; ok {
+			refs[e.name] = true
+		}
+	}
+}
+
+// listInterpreter returns with an interpreter which evaluates the children to a list
+func listInterpreter() ast.InterpreterFunc {
+	return func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+		children := node.Children()
+		res := make([]interface{}, 0, len(children))
+		for _, child := range children {
+			if _, empty := child.(ast.EmptyNode); empty {
+				res = append(res, nil)
+				continue
+			}
+			value, err := parsley.EvaluateNode(userCtx, child)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, value)
+		}
+		return res, nil
+	}
+}
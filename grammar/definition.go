@@ -0,0 +1,238 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package grammar
+
+import (
+	"strings"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/ast/interpreter"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+const identifierRegexp = "[a-zA-Z_][a-zA-Z0-9_]*"
+
+// rule is a named grammar rule
+type rule struct {
+	name string
+	pos  parsley.Pos
+	expr expression
+}
+
+// expression is one of: choice, sequence, repetition, reference, literal, pattern
+type expression interface{}
+
+type choice []expression
+
+type sequence []expression
+
+type repetition struct {
+	expr expression
+	op   string
+	pos  parsley.Pos
+}
+
+type reference struct {
+	name string
+	pos  parsley.Pos
+}
+
+type literal struct {
+	value string
+	pos   parsley.Pos
+}
+
+type pattern struct {
+	regexp string
+	pos    parsley.Pos
+}
+
+// name returns with the name of the pattern used in the error messages
+func (p pattern) name() string {
+	return "/" + p.regexp + "/"
+}
+
+// newDefinitionParser returns with a parser for grammar definitions
+func newDefinitionParser() parsley.Parser {
+	var alts parser.Func
+
+	identifier := terminal.Regexp(nil, "IDENTIFIER", "identifier", identifierRegexp, 0)
+	assignment := combinator.Choice(terminal.Op("="), terminal.Op("<-"), terminal.Op("::=")).Name("'='")
+
+	primary := combinator.Choice(
+		referenceParser(identifier, tok(assignment)),
+		terminal.String(nil, true),
+		terminal.Regexp(nil, "PATTERN", "regular expression", `/((?:[^/\\\n]|\\.)+)/`, 1),
+		combinator.SeqOf(terminal.Rune('('), &alts, tok(terminal.Rune(')'))).Bind(interpreter.Select(1)),
+	)
+
+	item := combinator.SeqFirstOrAll(
+		tok(primary),
+		combinator.Choice(terminal.Rune('*'), terminal.Rune('+'), terminal.Rune('?')),
+	).HandleResult(combinator.ReturnSingle()).Bind(ast.InterpreterFunc(evalRepetition))
+
+	alts = parser.Func(combinator.SepBy1(
+		combinator.Many1(item).Bind(ast.InterpreterFunc(evalSequence)),
+		tok(terminal.Rune('|')),
+	).Bind(ast.InterpreterFunc(evalChoice)).Parse)
+
+	rule := combinator.SeqOf(tok(identifier), tok(assignment), alts).Bind(ast.InterpreterFunc(evalRule))
+
+	return combinator.SeqOf(
+		combinator.Many(
+			combinator.SeqFirstOrAll(rule, tok(terminal.Rune(';'))).
+				HandleResult(combinator.ReturnSingle()).
+				Bind(interpreter.Select(0)),
+		).Bind(ast.InterpreterFunc(evalRules)),
+		tok(end()),
+	).Bind(interpreter.Select(0))
+}
+
+// tok skips the whitespaces and comments before it tries to match the given parser
+func tok(p parsley.Parser) parser.Func {
+	return func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		tr := ctx.Reader().(*text.Reader)
+		for {
+			pos, _ = tr.SkipWhitespaces(pos, text.WsSpacesNl)
+			next, comment := tr.ReadRegexp(pos, `(#|//)[^\n]*`)
+			if comment == nil {
+				break
+			}
+			pos = next
+		}
+		return p.Parse(ctx, leftRecCtx, pos)
+	}
+}
+
+// end matches the end of the definition
+// If it fails then it returns with the inputs expected at the furthest position, as an invalid rule won't cause
+// an error in itself, the rule list would simply stop before it.
+func end() parser.Func {
+	notFoundErr := parsley.NotFoundError("end of input")
+
+	return func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		res, cp, err := parser.End().Parse(ctx, leftRecCtx, pos)
+		if err != nil {
+			err = parsley.NewError(pos, notFoundErr)
+			ctx.RegisterNotFoundError(err)
+			if notFoundErrs := ctx.NotFoundErrors(); notFoundErrs.Pos > pos {
+				err = parsley.NewError(notFoundErrs.Pos, parsley.NotFoundError(notFoundErrs.Names[0]))
+			}
+		}
+		return res, cp, err
+	}
+}
+
+// referenceParser matches a rule or terminal reference, but only if it's not the beginning of a new rule
+func referenceParser(identifier parsley.Parser, assignment parsley.Parser) parser.Func {
+	notFoundErr := parsley.NotFoundError("rule reference")
+
+	return func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		res, cp, err := identifier.Parse(ctx, leftRecCtx, pos)
+		if err != nil {
+			return nil, cp, parsley.NewError(pos, notFoundErr)
+		}
+
+		notFoundErrs := ctx.NotFoundErrors()
+		next, _, _ := assignment.Parse(ctx, leftRecCtx, res.ReaderPos())
+		ctx.SetNotFoundErrors(notFoundErrs)
+		if next != nil {
+			return nil, cp, parsley.NewError(pos, notFoundErr)
+		}
+
+		return res, cp, nil
+	}
+}
+
+func evalRepetition(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+	children := node.Children()
+	expr, err := evalExpression(userCtx, children[0])
+	if err != nil {
+		return nil, err
+	}
+	op, _ := parsley.EvaluateNode(userCtx, children[1])
+	return repetition{expr: expr, op: string(op.(rune)), pos: children[1].Pos()}, nil
+}
+
+func evalSequence(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+	children := node.Children()
+	if len(children) == 1 {
+		return evalExpression(userCtx, children[0])
+	}
+
+	res := make(sequence, len(children))
+	for i, child := range children {
+		expr, err := evalExpression(userCtx, child)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = expr
+	}
+	return res, nil
+}
+
+func evalChoice(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+	children := node.Children()
+	if len(children) == 1 {
+		return parsley.EvaluateNode(userCtx, children[0])
+	}
+
+	res := make(choice, 0, (len(children)+1)/2)
+	for i := 0; i < len(children); i += 2 {
+		expr, err := parsley.EvaluateNode(userCtx, children[i])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, expr)
+	}
+	return res, nil
+}
+
+func evalRule(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+	children := node.Children()
+	name, _ := parsley.EvaluateNode(userCtx, children[0])
+	expr, err := parsley.EvaluateNode(userCtx, children[2])
+	if err != nil {
+		return nil, err
+	}
+	return &rule{name: name.(string), pos: children[0].Pos(), expr: expr}, nil
+}
+
+func evalRules(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+	children := node.Children()
+	res := make([]*rule, len(children))
+	for i, child := range children {
+		r, err := parsley.EvaluateNode(userCtx, child)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = r.(*rule)
+	}
+	return res, nil
+}
+
+// evalExpression converts the terminal nodes to expressions and evaluates the non-terminal ones
+func evalExpression(userCtx interface{}, node parsley.Node) (expression, parsley.Error) {
+	switch node.Token() {
+	case "IDENTIFIER":
+		name, _ := parsley.EvaluateNode(userCtx, node)
+		return reference{name: name.(string), pos: node.Pos()}, nil
+	case "STRING":
+		value, _ := parsley.EvaluateNode(userCtx, node)
+		return literal{value: value.(string), pos: node.Pos()}, nil
+	case "PATTERN":
+		value, _ := parsley.EvaluateNode(userCtx, node)
+		return pattern{regexp: strings.Replace(value.(string), `\/`, "/", -1), pos: node.Pos()}, nil
+	default:
+		return parsley.EvaluateNode(userCtx, node)
+	}
+}
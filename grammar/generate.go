@@ -0,0 +1,526 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package grammar
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/conflowio/parsley/text"
+)
+
+// GenerateOptions contains the options for the Go code generation
+type GenerateOptions struct {
+	// Package is the name of the package of the generated file
+	Package string
+	// FuncName is the name of the generated constructor function, the default is NewParser
+	FuncName string
+	// Start is the name of the start rule
+	Start string
+	// Terminals contains the terminal names which will be passed to the generated constructor
+	// The terminals registered with Terminal are added automatically.
+	Terminals []string
+}
+
+// Generate generates Go source code for a parser of the given start rule
+//
+// The generated constructor has the following signature:
+//
+//	func NewParser(interpreters map[string]parsley.Interpreter, terminals map[string]parsley.Parser) parsley.Parser
+//
+// The interpreters and the custom terminals are looked up by name. The interpreters bound with Bind are ignored,
+// but the generated parser creates the same nodes as the parser returned by Parser. The literals, the regular
+// expressions and the STRING, INTEGER and FLOAT terminals are matched by inlined parser functions, the other built-in
+// terminals use the terminal package. The memoized rules are registered by name in a
+// combinator.MemoRegistry, so every call creates a parser with the same parser indices.
+func (g *Grammar) Generate(opts GenerateOptions) ([]byte, error) {
+	if opts.Package == "" {
+		return nil, fmt.Errorf("the package name is required")
+	}
+
+	if opts.FuncName == "" {
+		opts.FuncName = "NewParser"
+	}
+
+	gen := &generator{
+		compiler:  newCompiler(g),
+		opts:      opts,
+		terminals: map[string]bool{},
+		imports:   map[string]bool{},
+	}
+	for name := range g.terminals {
+		gen.terminals[name] = true
+	}
+	for _, name := range opts.Terminals {
+		gen.terminals[name] = true
+	}
+
+	if gen.rules[opts.Start] == nil {
+		return nil, fmt.Errorf("the start rule %q is not defined", opts.Start)
+	}
+
+	return gen.generate()
+}
+
+type generator struct {
+	*compiler
+	opts          GenerateOptions
+	terminals     map[string]bool
+	imports       map[string]bool
+	leftRecursive map[string]bool
+	terminalDecls []string
+	terminalVars  map[string]string
+	usesTerminals bool
+	usesUnquote   bool
+}
+
+func (g *generator) generate() ([]byte, error) {
+	g.terminalVars = map[string]string{}
+	g.leftRecursive = g.leftRecursiveRules()
+	g.use("ast", "combinator", "parser", "parsley", "text")
+
+	memoized := 0
+
+	// Only the rules reachable from the start rule are generated, as unused variables wouldn't compile
+	reachable := g.reachableRules()
+
+	var rules []string
+	for _, r := range g.grammar.rules {
+		if !reachable[r.name] {
+			continue
+		}
+		code, err := g.rule(r)
+		if err != nil {
+			return nil, err
+		}
+		if g.leftRecursive[r.name] {
+			code = fmt.Sprintf("memo.MemoizeNamed(%q, %s)", r.name, code)
+			memoized++
+		}
+		rules = append(rules, fmt.Sprintf("%s = %s", ruleVar(r.name), code))
+	}
+
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "// Code generated by parsley-gen from %s. DO NOT EDIT.\n\n", filepath.Base(g.grammar.filename))
+	fmt.Fprintf(b, "package %s\n\n", g.opts.Package)
+
+	b.WriteString("import (\n")
+	if g.imports["strconv"] {
+		b.WriteString("\"strconv\"\n")
+		if g.imports["unicode/utf8"] {
+			b.WriteString("\"unicode/utf8\"\n")
+		}
+		b.WriteString("\n")
+	}
+	for _, pkg := range []string{"ast", "combinator", "data", "parser", "parsley", "text", "text/terminal"} {
+		if g.imports[pkg] {
+			fmt.Fprintf(b, "%q\n", "github.com/conflowio/parsley/"+pkg)
+		}
+	}
+	b.WriteString(")\n\n")
+
+	fmt.Fprintf(b, "// %s returns with a parser for the %q rule\n", g.opts.FuncName, g.opts.Start)
+	b.WriteString("// The interpreters are bound to the rules by name, the rules without an interpreter are evaluated as a list.\n")
+	fmt.Fprintf(b, "func %s(interpreters map[string]parsley.Interpreter, terminals map[string]parsley.Parser) parsley.Parser {\n", g.opts.FuncName)
+	b.WriteString(listInterpreterCode)
+	b.WriteString(bindCode)
+	if g.usesTerminals {
+		b.WriteString(terminalCode)
+	}
+	if g.usesUnquote {
+		b.WriteString(unquoteStringCode)
+	}
+
+	for _, decl := range g.terminalDecls {
+		b.WriteString(decl)
+		b.WriteString("\n")
+	}
+
+	if memoized > 0 {
+		b.WriteString("\nmemo := combinator.NewMemoRegistry()\n")
+	}
+
+	b.WriteString("\nvar (\n")
+	for _, r := range g.grammar.rules {
+		if reachable[r.name] {
+			fmt.Fprintf(b, "%s parser.Func\n", ruleVar(r.name))
+		}
+	}
+	b.WriteString(")\n\n")
+
+	for _, rule := range rules {
+		b.WriteString(rule)
+		b.WriteString("\n\n")
+	}
+
+	fmt.Fprintf(b, "return %s\n}\n", ruleVar(g.opts.Start))
+
+	return format.Source(b.Bytes())
+}
+
+func (g *generator) rule(r *rule) (string, error) {
+	alternatives, ok := r.expr.(choice)
+	if !ok {
+		alternatives = choice{r.expr}
+	}
+
+	parsers := make([]string, len(alternatives))
+	for i, alternative := range alternatives {
+		items, ok := alternative.(sequence)
+		if !ok {
+			items = sequence{alternative}
+		}
+
+		itemParsers, err := g.expressions(items)
+		if err != nil {
+			return "", err
+		}
+
+		parsers[i] = fmt.Sprintf("bind(combinator.SeqOf(%s).Token(%q), %q)", itemParsers, r.name, r.name)
+	}
+
+	switch {
+	case len(parsers) == 1:
+		// The rule variables are parser.Func values, so the sequence is wrapped
+		g.use("parser")
+		return fmt.Sprintf("parser.Func(%s.Parse)", parsers[0]), nil
+	case g.leftRecursive[r.name]:
+		return fmt.Sprintf("combinator.Any(\n%s,\n)", strings.Join(parsers, ",\n")), nil
+	default:
+		return fmt.Sprintf("combinator.Choice(\n%s,\n)", strings.Join(parsers, ",\n")), nil
+	}
+}
+
+func (g *generator) expressions(exprs []expression) (string, error) {
+	parsers := make([]string, len(exprs))
+	for i, expr := range exprs {
+		p, err := g.expression(expr)
+		if err != nil {
+			return "", err
+		}
+		parsers[i] = p
+	}
+	return strings.Join(parsers, ", "), nil
+}
+
+func (g *generator) expression(expr expression) (string, error) {
+	switch e := expr.(type) {
+	case choice:
+		parsers, err := g.expressions(e)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("combinator.Choice(%s)", parsers), nil
+	case sequence:
+		parsers, err := g.expressions(e)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("combinator.SeqOf(%s).Bind(list)", parsers), nil
+	case repetition:
+		p, err := g.expression(e.expr)
+		if err != nil {
+			return "", err
+		}
+		if g.isNullable(e.expr, g.nullable) {
+			if e.op == "?" {
+				return p, nil
+			}
+			return "", g.errorf(e.pos, "the repeated expression should not match an empty input")
+		}
+		switch e.op {
+		case "*":
+			return fmt.Sprintf("combinator.Many(%s).Bind(list)", p), nil
+		case "+":
+			return fmt.Sprintf("combinator.Many1(%s).Bind(list)", p), nil
+		default:
+			return fmt.Sprintf("combinator.Optional(%s)", p), nil
+		}
+	case reference:
+		return g.reference(e)
+	case literal:
+		if err := g.checkLiteral(e); err != nil {
+			return "", err
+		}
+		g.use("data", "parser")
+		if identifierPattern.MatchString(e.value) {
+			return g.terminal("word:"+e.value, fmt.Sprintf(wordCode,
+				strconv.Quote(e.value), strconv.Quote(strings.ToUpper(e.value)), strconv.Quote(e.value),
+				goString(strconv.Quote(e.value)),
+			)), nil
+		}
+		g.use("text/terminal")
+		return g.terminal("op:"+e.value, fmt.Sprintf(opCode,
+			strconv.Quote(e.value), strconv.Quote(e.value), goString(strconv.Quote(e.value)),
+		)), nil
+	case pattern:
+		if err := g.checkPattern(e); err != nil {
+			return "", err
+		}
+		g.use("data", "parser")
+		return g.terminal("regexp:"+e.regexp, fmt.Sprintf(regexpCode,
+			goString(e.regexp), goString(e.name()),
+		)), nil
+	default:
+		panic(fmt.Sprintf("unexpected expression type: %T", expr))
+	}
+}
+
+func (g *generator) reference(ref reference) (string, error) {
+	if g.terminals[ref.name] {
+		g.usesTerminals = true
+		return g.terminal("terminal:"+ref.name, fmt.Sprintf("customTerminal(%q)", ref.name)), nil
+	}
+
+	if _, ok := g.rules[ref.name]; ok {
+		return "&" + ruleVar(ref.name), nil
+	}
+
+	if code, ok := builtinTerminalsCode[ref.name]; ok {
+		switch ref.name {
+		case "EOF":
+			g.use("parser")
+		case "STRING":
+			g.use("data", "parser", "strconv", "text/terminal", "unicode/utf8")
+			g.usesUnquote = true
+		case "INTEGER", "FLOAT":
+			g.use("data", "parser", "strconv", "text/terminal")
+		default:
+			g.use("text/terminal")
+		}
+		return g.terminal("builtin:"+ref.name, code), nil
+	}
+
+	return "", g.errorf(ref.pos, "undefined rule or terminal %q", ref.name)
+}
+
+// reachableRules returns with the rules which can be reached from the start rule
+func (g *generator) reachableRules() map[string]bool {
+	reachable := map[string]bool{g.opts.Start: true}
+	queue := []string{g.opts.Start}
+	for len(queue) > 0 {
+		r := g.rules[queue[0]]
+		queue = queue[1:]
+		g.walkReferences(r.expr, func(name string) {
+			if _, ok := g.rules[name]; ok && !g.terminals[name] && !reachable[name] {
+				reachable[name] = true
+				queue = append(queue, name)
+			}
+		})
+	}
+	return reachable
+}
+
+func (g *generator) walkReferences(expr expression, f func(name string)) {
+	switch e := expr.(type) {
+	case choice:
+		for _, item := range e {
+			g.walkReferences(item, f)
+		}
+	case sequence:
+		for _, item := range e {
+			g.walkReferences(item, f)
+		}
+	case repetition:
+		g.walkReferences(e.expr, f)
+	case reference:
+		f(e.name)
+	}
+}
+
+// terminal declares a whitespace-trimmed terminal parser variable once and returns with its name
+func (g *generator) terminal(key string, code string) string {
+	if name, ok := g.terminalVars[key]; ok {
+		return name
+	}
+
+	name := fmt.Sprintf("term%d", len(g.terminalDecls)+1)
+	g.terminalVars[key] = name
+	g.terminalDecls = append(g.terminalDecls, fmt.Sprintf("%s := text.LeftTrim(%s, %s)", name, code, wsModeCode(g.grammar.wsMode)))
+	return name
+}
+
+func (g *generator) use(packages ...string) {
+	for _, pkg := range packages {
+		g.imports[pkg] = true
+	}
+}
+
+func ruleVar(name string) string {
+	return "rule_" + name
+}
+
+// goString returns with a Go string literal, a raw string literal is used if it's more readable
+func goString(s string) string {
+	if strings.ContainsAny(s, "\"\\") && !strings.ContainsAny(s, "`\r\n") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+func wsModeCode(wsMode text.WsMode) string {
+	switch wsMode {
+	case text.WsNone:
+		return "text.WsNone"
+	case text.WsSpaces:
+		return "text.WsSpaces"
+	case text.WsSpacesForceNl:
+		return "text.WsSpacesForceNl"
+	default:
+		return "text.WsSpacesNl"
+	}
+}
+
+// builtinTerminalsCode contains the code of the built-in terminals, the STRING, INTEGER and FLOAT scanners are inlined
+var builtinTerminalsCode = map[string]string{
+	"STRING":        stringCode,
+	"INTEGER":       integerCode,
+	"FLOAT":         floatCode,
+	"BOOL":          `terminal.Bool("bool", "true", "false")`,
+	"NIL":           `terminal.Nil("nil", "nil")`,
+	"CHAR":          `terminal.Char("char")`,
+	"TIME_DURATION": `terminal.TimeDuration("time_duration")`,
+	"EOF":           `parser.End()`,
+}
+
+const listInterpreterCode = `list := ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+	children := node.Children()
+	res := make([]interface{}, 0, len(children))
+	for _, child := range children {
+		if _, empty := child.(ast.EmptyNode); empty {
+			res = append(res, nil)
+			continue
+		}
+		value, err := parsley.EvaluateNode(userCtx, child)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, value)
+	}
+	return res, nil
+})
+
+`
+
+const bindCode = `bind := func(s *combinator.Sequence, rule string) *combinator.Sequence {
+	if interpreter, ok := interpreters[rule]; ok {
+		return s.Bind(interpreter)
+	}
+	return s.HandleResult(combinator.ReturnSingle()).Bind(list)
+}
+
+`
+
+const terminalCode = `customTerminal := func(name string) parsley.Parser {
+	p, ok := terminals[name]
+	if !ok {
+		panic("terminal " + name + " is not defined")
+	}
+	return p
+}
+
+`
+
+const wordCode = `parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	if readerPos, found := ctx.Reader().(*text.Reader).MatchWord(pos, %s); found {
+		return ast.NewTerminalNode(nil, %[2]s, %[3]s, pos, readerPos), data.EmptyIntSet, nil
+	}
+	return nil, data.EmptyIntSet, parsley.NewError(pos, parsley.NotFoundError(%[4]s))
+}).Trace(%[2]s)`
+
+const opCode = `parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	if readerPos, found := ctx.Reader().(*text.Reader).MatchString(pos, %s); found {
+		return terminal.NewOpNode(%[2]s, pos, readerPos), data.EmptyIntSet, nil
+	}
+	return nil, data.EmptyIntSet, parsley.NewError(pos, parsley.NotFoundError(%[3]s))
+}).Trace(%[2]s)`
+
+const regexpCode = `parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	if readerPos, match := ctx.Reader().(*text.Reader).ReadRegexp(pos, %s); match != nil {
+		return ast.NewTerminalNode(nil, "REGEXP", string(match), pos, readerPos), data.EmptyIntSet, nil
+	}
+	return nil, data.EmptyIntSet, parsley.NewError(pos, parsley.NotFoundError(%s))
+}).Trace("REGEXP")`
+
+const stringCode = `parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	tr := ctx.Reader().(*text.Reader)
+	readerPos, found := tr.ReadRune(pos, '"')
+	if !found {
+		return nil, data.EmptyIntSet, parsley.NewError(pos, parsley.NotFoundError("string literal"))
+	}
+
+	if readerPos, found = tr.ReadRune(readerPos, '"'); found {
+		return terminal.NewStringNode("string", "", pos, readerPos), data.EmptyIntSet, nil
+	}
+
+	readerPos, value := tr.Readf(readerPos, unquoteString)
+	if readerPos, found = tr.ReadRune(readerPos, '"'); !found {
+		return nil, data.EmptyIntSet, parsley.NewErrorf(readerPos, "was expecting '\"'")
+	}
+	return terminal.NewStringNode("string", string(value), pos, readerPos), data.EmptyIntSet, nil
+}).Trace("STRING")`
+
+const integerCode = `parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	tr := ctx.Reader().(*text.Reader)
+	if readerPos, result := tr.ReadRegexp(pos, "[-+]?(?:[1-9][0-9]*|0[xX][0-9a-fA-F]+|0[0-7]*)"); result != nil {
+		if _, isFloat := tr.ReadRune(readerPos, '.'); !isFloat {
+			value, err := strconv.ParseInt(string(result), 0, 64)
+			if err != nil {
+				panic("Could not convert " + string(result) + " to integer")
+			}
+			return terminal.NewIntegerNode("integer", value, pos, readerPos), data.EmptyIntSet, nil
+		}
+	}
+	return nil, data.EmptyIntSet, parsley.NewError(pos, parsley.NotFoundError("integer value"))
+}).Trace("INTEGER")`
+
+const floatCode = `parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	tr := ctx.Reader().(*text.Reader)
+	if readerPos, result := tr.ReadRegexp(pos, "[-+]?[0-9]*\\.[0-9]+(?:[eE][-+]?[0-9]+)?"); result != nil {
+		value, err := strconv.ParseFloat(string(result), 64)
+		if err != nil {
+			return nil, data.EmptyIntSet, parsley.NewErrorf(pos, "invalid float value")
+		}
+		return terminal.NewFloatNode("float", value, pos, readerPos), data.EmptyIntSet, nil
+	}
+	return nil, data.EmptyIntSet, parsley.NewError(pos, parsley.NotFoundError("float value"))
+}).Trace("FLOAT")`
+
+const unquoteStringCode = `unquoteString := func(b []byte) ([]byte, int) {
+	i := 0
+	for {
+		if i >= len(b) {
+			return b, len(b)
+		}
+		if b[i] == '\r' || b[i] == '\n' || b[i] == '"' {
+			return b[0:i], i
+		}
+		if b[i] == '\\' || b[i] >= utf8.RuneSelf {
+			break
+		}
+		i++
+	}
+
+	str := string(b[i:])
+	res := make([]byte, 0, len(b))
+	res = append(res, b[0:i]...)
+	for str != "" {
+		ch, _, tail, err := strconv.UnquoteChar(str, '"')
+		if err != nil {
+			break
+		}
+		res = append(res, string(ch)...)
+		str = tail
+	}
+	return res, len(b) - len(str)
+}
+
+`
@@ -0,0 +1,149 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package grammar_test
+
+import (
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/grammar"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+var _ = Describe("Generate", func() {
+
+	var (
+		g    *grammar.Grammar
+		opts grammar.GenerateOptions
+		code []byte
+		err  error
+	)
+
+	BeforeEach(func() {
+		var parseErr error
+		g, parseErr = grammar.Parse("test.grammar", []byte(`
+			start  = "if" ID "then" value | value
+			value  = /[0-9]+/ | INTEGER
+			unused = "x"
+		`))
+		Expect(parseErr).ToNot(HaveOccurred())
+		opts = grammar.GenerateOptions{Package: "test", Start: "start", Terminals: []string{"ID"}}
+	})
+
+	JustBeforeEach(func() {
+		code, err = g.Generate(opts)
+	})
+
+	It("should generate the parser", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(code)).To(HavePrefix("// Code generated by parsley-gen from test.grammar. DO NOT EDIT.\n\npackage test\n"))
+		Expect(string(code)).To(ContainSubstring("func NewParser(interpreters map[string]parsley.Interpreter, terminals map[string]parsley.Parser) parsley.Parser {"))
+		Expect(string(code)).To(ContainSubstring(`MatchWord(pos, "if")`))
+		Expect(string(code)).To(ContainSubstring(`ReadRegexp(pos, "[0-9]+")`))
+		Expect(string(code)).To(ContainSubstring(`customTerminal("ID")`))
+		Expect(string(code)).To(ContainSubstring(`terminal.NewIntegerNode("integer", value, pos, readerPos)`))
+	})
+
+	It("should not call the built-in terminal constructors", func() {
+		Expect(string(code)).ToNot(ContainSubstring("terminal.Integer("))
+	})
+
+	It("should trace the inlined terminals", func() {
+		Expect(string(code)).To(ContainSubstring(`}).Trace("IF")`))
+		Expect(string(code)).To(ContainSubstring(`}).Trace("REGEXP")`))
+		Expect(string(code)).To(ContainSubstring(`}).Trace("INTEGER")`))
+	})
+
+	It("should only generate the rules reachable from the start rule", func() {
+		Expect(string(code)).ToNot(ContainSubstring("rule_unused"))
+	})
+
+	It("should not memoize rules which are not left-recursive", func() {
+		Expect(string(code)).ToNot(ContainSubstring("Memoize"))
+	})
+
+	Context("when a rule is left-recursive", func() {
+		BeforeEach(func() {
+			var parseErr error
+			g, parseErr = grammar.Parse("test.grammar", []byte(`
+				start = start "-" INTEGER | INTEGER
+			`))
+			Expect(parseErr).ToNot(HaveOccurred())
+		})
+
+		It("should memoize the rule by name", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(code)).To(ContainSubstring("memo := combinator.NewMemoRegistry()"))
+			Expect(string(code)).To(ContainSubstring(`rule_start = memo.MemoizeNamed("start", combinator.Any(`))
+		})
+	})
+
+	Context("when a custom function name is set", func() {
+		BeforeEach(func() {
+			opts.FuncName = "NewTestParser"
+		})
+
+		It("should use the function name", func() {
+			Expect(string(code)).To(ContainSubstring("func NewTestParser("))
+		})
+	})
+
+	Context("when a terminal is registered", func() {
+		BeforeEach(func() {
+			opts.Terminals = nil
+			g.Terminal("ID", terminal.Regexp(nil, "ID", "identifier", "[a-z]+", 0))
+		})
+
+		It("should be passed to the generated constructor", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(code)).To(ContainSubstring(`customTerminal("ID")`))
+		})
+	})
+
+	Context("when a terminal is not defined", func() {
+		BeforeEach(func() {
+			opts.Terminals = nil
+		})
+
+		It("should return an error", func() {
+			Expect(err).To(MatchError(`undefined rule or terminal "ID" at test.grammar:2:18`))
+		})
+	})
+
+	Context("when the package name is missing", func() {
+		BeforeEach(func() {
+			opts.Package = ""
+		})
+
+		It("should return an error", func() {
+			Expect(err).To(MatchError("the package name is required"))
+		})
+	})
+
+	Context("when the start rule is not defined", func() {
+		BeforeEach(func() {
+			opts.Start = "other"
+		})
+
+		It("should return an error", func() {
+			Expect(err).To(MatchError(`the start rule "other" is not defined`))
+		})
+	})
+
+	It("should generate the same code as the calculator example", func() {
+		g, err := grammar.ReadFile("../examples/calc/calc/calc.grammar")
+		Expect(err).ToNot(HaveOccurred())
+		code, err := g.Generate(grammar.GenerateOptions{Package: "calc", Start: "sum"})
+		Expect(err).ToNot(HaveOccurred())
+
+		expected, err := ioutil.ReadFile("../examples/calc/calc/parser.go")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(code)).To(Equal(string(expected)))
+	})
+})
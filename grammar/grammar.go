@@ -0,0 +1,199 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package grammar creates parsers from a textual EBNF/PEG-style grammar definition
+//
+// A grammar consists of rules, where every rule has a name and a definition:
+//
+//	# comments start with # or //
+//	sum    = sum "+" value | value ;
+//	value  = INTEGER | "(" sum ")" ;
+//
+// The rules can be terminated with a semicolon, but it's optional. Instead of "=" you can also use "<-" or "::=".
+//
+// The following expressions are supported:
+//   - "text" or `text`: a literal (matched as a word if it's an identifier, otherwise as an operator)
+//   - /regexp/: a regular expression
+//   - name: a reference to a rule or to a terminal
+//   - a b: a sequence
+//   - a | b: a choice
+//   - a*, a+, a?: zero or more, one or more, optional
+//   - ( ... ): grouping
+//
+// The built-in terminals are: STRING, INTEGER, FLOAT, BOOL, NIL, CHAR, TIME_DURATION and EOF.
+package grammar
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+)
+
+// Grammar is a parsed grammar definition which can be turned into a parser
+type Grammar struct {
+	filename     string
+	fileSet      *parsley.FileSet
+	rules        []*rule
+	interpreters map[string]parsley.Interpreter
+	terminals    map[string]parsley.Parser
+	wsMode       text.WsMode
+}
+
+// New creates an empty grammar, the rules can be added with Rule
+// The name is used in the error messages and in the header of the generated code.
+func New(name string) *Grammar {
+	return &Grammar{
+		filename:     name,
+		fileSet:      parsley.NewFileSet(),
+		interpreters: map[string]parsley.Interpreter{},
+		terminals:    map[string]parsley.Parser{},
+		wsMode:       text.WsSpacesNl,
+	}
+}
+
+// Parse parses the given grammar definition
+func Parse(filename string, definition []byte) (*Grammar, error) {
+	g := New(filename)
+
+	rules, err := g.parseDefinition(filename, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.addRules(rules); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// Rule adds a new rule to the grammar, the definition has the same syntax as the right side of a rule in a grammar file
+// It panics if the definition is invalid or if the rule is already defined.
+func (g *Grammar) Rule(name string, definition string) *Grammar {
+	if err := g.addRule(name, definition); err != nil {
+		panic(err.Error())
+	}
+	return g
+}
+
+func (g *Grammar) addRule(name string, definition string) error {
+	rules, err := g.parseDefinition(g.filename+":"+name, []byte(name+" = "+definition))
+	if err != nil {
+		return err
+	}
+
+	if len(rules) != 1 || rules[0].name != name {
+		return fmt.Errorf("the definition of rule %q should contain a single expression", name)
+	}
+
+	return g.addRules(rules)
+}
+
+// parseDefinition parses the rules of a definition which is added as a new file to the grammar's file set
+func (g *Grammar) parseDefinition(filename string, definition []byte) ([]*rule, error) {
+	f := text.NewFile(filename, definition)
+	g.fileSet.AddFile(f)
+	ctx := parsley.NewContext(g.fileSet, text.NewReader(f))
+
+	value, err := parsley.Evaluate(ctx, newDefinitionParser())
+	if err != nil {
+		return nil, err
+	}
+
+	return value.([]*rule), nil
+}
+
+func (g *Grammar) addRules(rules []*rule) error {
+	names := map[string]bool{}
+	for _, r := range g.rules {
+		names[r.name] = true
+	}
+
+	for _, r := range rules {
+		if names[r.name] {
+			return g.fileSet.ErrorWithPosition(parsley.NewErrorf(r.pos, "rule %q is already defined", r.name))
+		}
+		names[r.name] = true
+	}
+
+	g.rules = append(g.rules, rules...)
+	return nil
+}
+
+// ReadFile reads and parses the given grammar file
+func ReadFile(filename string) (*Grammar, error) {
+	definition, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("can not read %s", filename)
+	}
+	return Parse(filename, definition)
+}
+
+// Bind binds the given interpreter to a rule
+// The interpreter will get a non-terminal node where the children are the results of the rule's items.
+// If no interpreter is bound then a rule with a single item returns the item's node, otherwise the node will be
+// evaluated as a list of the item values.
+func (g *Grammar) Bind(ruleName string, interpreter parsley.Interpreter) *Grammar {
+	g.interpreters[ruleName] = interpreter
+	return g
+}
+
+// Terminal registers a custom terminal parser which can be referenced by name
+// The whitespaces will be skipped before the terminal based on the whitespace mode.
+func (g *Grammar) Terminal(name string, p parsley.Parser) *Grammar {
+	g.terminals[name] = p
+	return g
+}
+
+// WsMode sets which whitespaces are skipped before the terminals, the default is text.WsSpacesNl
+func (g *Grammar) WsMode(wsMode text.WsMode) *Grammar {
+	g.wsMode = wsMode
+	return g
+}
+
+// RuleNames returns with the names of all rules in the order they were defined
+func (g *Grammar) RuleNames() []string {
+	res := make([]string, len(g.rules))
+	for i, r := range g.rules {
+		res[i] = r.name
+	}
+	return res
+}
+
+// Parser returns with a new parser for the given start rule
+// Any whitespaces after the last token are not consumed, so you might want to use text.Trim or text.RightTrim.
+func (g *Grammar) Parser(start string) (parsley.Parser, error) {
+	c := newCompiler(g)
+
+	for name := range g.interpreters {
+		if c.rules[name] == nil {
+			return nil, fmt.Errorf("an interpreter was bound to an undefined rule %q", name)
+		}
+	}
+
+	if c.rules[start] == nil {
+		return nil, fmt.Errorf("the start rule %q is not defined", start)
+	}
+
+	if err := c.compile(); err != nil {
+		return nil, err
+	}
+
+	return c.parsers[start], nil
+}
+
+// LeftRecursiveRules returns with the names of the rules which are directly or indirectly left-recursive
+func (g *Grammar) LeftRecursiveRules() []string {
+	var res []string
+	for name := range newCompiler(g).leftRecursiveRules() {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
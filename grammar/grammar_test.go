@@ -0,0 +1,335 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package grammar_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/grammar"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+	"github.com/conflowio/parsley/trace"
+)
+
+// Let's define a simple calculator with a left-recursive grammar
+func Example() {
+	g, err := grammar.Parse("calc.grammar", []byte(`
+		sum   = sum "+" value | value ;
+		value = INTEGER | "(" sum ")" ;
+	`))
+	if err != nil {
+		panic(err)
+	}
+
+	g.Bind("sum", ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+		children := node.Children()
+		a, err := parsley.EvaluateNode(userCtx, children[0])
+		if err != nil || len(children) == 1 {
+			return a, err
+		}
+		b, err := parsley.EvaluateNode(userCtx, children[2])
+		if err != nil {
+			return nil, err
+		}
+		return a.(int64) + b.(int64), nil
+	}))
+
+	g.Bind("value", ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+		children := node.Children()
+		if len(children) == 1 {
+			return parsley.EvaluateNode(userCtx, children[0])
+		}
+		return parsley.EvaluateNode(userCtx, children[1])
+	}))
+
+	p, err := g.Parser("sum")
+	if err != nil {
+		panic(err)
+	}
+
+	f := text.NewFile("example.file", []byte("1 + (2 + 3) + 4"))
+	ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+	result, err := parsley.Evaluate(ctx, combinator.Sentence(p))
+	fmt.Println(result, err)
+	// Output: 10 <nil>
+}
+
+var _ = Describe("Grammar", func() {
+
+	var (
+		g      *grammar.Grammar
+		err    error
+		def    string
+		start  string
+		result interface{}
+	)
+
+	BeforeEach(func() {
+		start = "start"
+	})
+
+	JustBeforeEach(func() {
+		g, err = grammar.Parse("testgrammar", []byte(def))
+	})
+
+	evaluate := func(input string) (interface{}, error) {
+		p, err := g.Parser(start)
+		if err != nil {
+			return nil, err
+		}
+		f := text.NewFile("testfile", []byte(input))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		return parsley.Evaluate(ctx, combinator.Sentence(text.RightTrim(p, text.WsSpacesNl)))
+	}
+
+	Context("when the definition is valid", func() {
+		BeforeEach(func() {
+			def = `
+				# a list of items
+				start   <- "[" (item ("," item)*)? "]"
+				item    ::= keyword | BOOL | NIL | /[a-z]+/ | INTEGER | FLOAT | STRING // a comment
+				keyword = "if" | "else"
+			`
+		})
+
+		It("should not return an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should return the rule names", func() {
+			Expect(g.RuleNames()).To(Equal([]string{"start", "item", "keyword"}))
+		})
+
+		DescribeTable("should parse the input",
+			func(input string, expected interface{}) {
+				result, err = evaluate(input)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(expected))
+			},
+			Entry("empty list", "[]", []interface{}{"[", nil, "]"}),
+			Entry("single item", "[ 1 ]", []interface{}{"[", []interface{}{int64(1), []interface{}{}}, "]"}),
+			Entry("multiple items", "[a, 1.5, \"x\",\n true, nil]", []interface{}{
+				"[",
+				[]interface{}{"a", []interface{}{
+					[]interface{}{",", 1.5},
+					[]interface{}{",", "x"},
+					[]interface{}{",", true},
+					[]interface{}{",", nil},
+				}},
+				"]",
+			}),
+			Entry("keyword", "[if]", []interface{}{"[", []interface{}{"if", []interface{}{}}, "]"}),
+		)
+
+		It("should return a parse error for an invalid input", func() {
+			_, err = evaluate("[a b]")
+			Expect(err).To(MatchError(`failed to parse the input: was expecting "," or "]", found "b" at testfile:1:4`))
+		})
+
+		It("should match literals which look like identifiers as words", func() {
+			_, err = evaluate("[ifx]")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should not return left-recursive rules", func() {
+			Expect(g.LeftRecursiveRules()).To(BeEmpty())
+		})
+
+		Context("when an interpreter is bound to a rule", func() {
+			JustBeforeEach(func() {
+				g.Bind("keyword", ast.InterpreterFunc(func(userCtx interface{}, node parsley.NonTerminalNode) (interface{}, parsley.Error) {
+					Expect(node.Token()).To(Equal("keyword"))
+					value, _ := parsley.EvaluateNode(userCtx, node.Children()[0])
+					return "keyword:" + value.(string), nil
+				}))
+			})
+
+			It("should use the interpreter", func() {
+				result, err = evaluate("[else]")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal([]interface{}{"[", []interface{}{"keyword:else", []interface{}{}}, "]"}))
+			})
+		})
+
+		Context("when an interpreter is bound to an undefined rule", func() {
+			JustBeforeEach(func() {
+				g.Bind("other", ast.InterpreterFunc(nil))
+			})
+
+			It("should return an error", func() {
+				_, err = g.Parser(start)
+				Expect(err).To(MatchError(`an interpreter was bound to an undefined rule "other"`))
+			})
+		})
+
+		Context("when the whitespace mode is changed", func() {
+			JustBeforeEach(func() {
+				g.WsMode(text.WsSpaces)
+			})
+
+			It("should not allow new lines", func() {
+				_, err = evaluate("[a,\nb]")
+				Expect(err).To(HaveOccurred())
+
+				_, err = evaluate("[a, b]")
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		It("should return an error if the start rule is not defined", func() {
+			start = "other"
+			_, err = g.Parser(start)
+			Expect(err).To(MatchError(`the start rule "other" is not defined`))
+		})
+	})
+
+	Context("when a custom terminal is registered", func() {
+		BeforeEach(func() {
+			def = `start = INTEGER | STRING`
+		})
+
+		JustBeforeEach(func() {
+			g.Terminal("STRING", terminal.Regexp(nil, "WORD", "word", "[a-z]+", 0))
+		})
+
+		It("should take priority over the built-in terminals", func() {
+			result, err = evaluate("abc")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal("abc"))
+		})
+	})
+
+	Context("when a rule is left-recursive", func() {
+		BeforeEach(func() {
+			def = `
+				start  = start "-" value | value
+				value  = other | INTEGER
+				other  = "(" start ")"
+			`
+		})
+
+		It("should parse the input with left associativity", func() {
+			result, err = evaluate("1 - (2 - 3) - 4")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal([]interface{}{
+				[]interface{}{int64(1), "-", []interface{}{"(", []interface{}{int64(2), "-", int64(3)}, ")"}},
+				"-",
+				int64(4),
+			}))
+		})
+
+		It("should return the left-recursive rules", func() {
+			Expect(g.LeftRecursiveRules()).To(Equal([]string{"start"}))
+		})
+
+		It("should memoize the left-recursive rules by name", func() {
+			p, err := g.Parser(start)
+			Expect(err).ToNot(HaveOccurred())
+
+			b := &bytes.Buffer{}
+			f := text.NewFile("testfile", []byte("1 - 2"))
+			ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+			ctx.SetTracer(trace.NewTextTracer(b, nil))
+			_, err = parsley.Evaluate(ctx, combinator.Sentence(p))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b.String()).To(ContainSubstring("> MEMOIZE#start "))
+		})
+
+		It("should create independent parsers", func() {
+			for i := 0; i < 3; i++ {
+				result, err = evaluate("1 - 2")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal([]interface{}{int64(1), "-", int64(2)}))
+			}
+		})
+	})
+
+	Context("when rules are indirectly left-recursive", func() {
+		BeforeEach(func() {
+			def = `
+				start  = prefix? list "!" | INTEGER ;
+				list   = start ;
+				prefix = "#"* ;
+			`
+		})
+
+		It("should return all left-recursive rules", func() {
+			Expect(g.LeftRecursiveRules()).To(Equal([]string{"list", "start"}))
+		})
+
+		It("should parse the input", func() {
+			result, err = evaluate("#1!!")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal([]interface{}{[]interface{}{"#"}, []interface{}{[]interface{}{}, int64(1), "!"}, "!"}))
+		})
+	})
+
+	DescribeTable("should return an error for an invalid definition",
+		func(definition string, expected string) {
+			_, err := grammar.Parse("testgrammar", []byte(definition))
+			Expect(err).To(MatchError(expected))
+		},
+		Entry("missing definition", "a = = b", `failed to parse the input: was expecting rule reference, string literal, regular expression or "(", found "=" at testgrammar:1:5`),
+		Entry("unclosed group", "a = b ( c", `failed to parse the input: was expecting "*", "+", "?", rule reference, string literal, regular expression, "(", "|" or ")", found end of input at testgrammar:1:10`),
+		Entry("duplicated rule", "a = b\nb = \"x\"\na = b", `rule "a" is already defined at testgrammar:3:1`),
+	)
+
+	DescribeTable("should return an error for an invalid rule",
+		func(definition string, expected string) {
+			g, err := grammar.Parse("testgrammar", []byte(definition))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = g.Parser("a")
+			Expect(err).To(MatchError(expected))
+		},
+		Entry("undefined reference", "a = b\nb = \"x\" c", `undefined rule or terminal "c" at testgrammar:2:9`),
+		Entry("empty literal", `a = ""`, `empty literals are not allowed at testgrammar:1:5`),
+		Entry("invalid regexp", `a = /[a/`, "invalid regular expression: error parsing regexp: missing closing ]: `[a)` at testgrammar:1:5"),
+		Entry("repeating an empty match", `a = (b?)*`+"\nb = \"x\"", `the repeated expression should not match an empty input at testgrammar:1:9`),
+		Entry("regexp matching empty input", `a = /a*/`, `regular expression /a*/ should not match an empty input at testgrammar:1:5`),
+	)
+
+	Describe("ReadFile", func() {
+		var tmpDir string
+
+		BeforeEach(func() {
+			tmpDir, err = ioutil.TempDir("", "parsley-test-")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			if tmpDir != "" {
+				os.RemoveAll(tmpDir)
+			}
+		})
+
+		It("should read the grammar file", func() {
+			filename := filepath.Join(tmpDir, "test.grammar")
+			Expect(ioutil.WriteFile(filename, []byte(`start = "a" | "b"`), 0600)).To(Succeed())
+
+			g, err := grammar.ReadFile(filename)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(g.RuleNames()).To(Equal([]string{"start"}))
+		})
+
+		It("should return an error if the file does not exist", func() {
+			filename := filepath.Join(tmpDir, "nonexisting")
+			_, err := grammar.ReadFile(filename)
+			Expect(err).To(MatchError("can not read " + filename))
+		})
+	})
+})
@@ -0,0 +1,170 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// notificationBufferSize is the number of notifications the client can hold before the server is blocked
+const notificationBufferSize = 1024
+
+// Notification is a notification sent by the server
+type Notification struct {
+	Method string
+	Params json.RawMessage
+}
+
+// Client is a Language Server Protocol client which can be used to test a server in-process
+type Client struct {
+	w             io.WriteCloser
+	writeMu       sync.Mutex
+	mu            sync.Mutex
+	nextID        int
+	pending       map[string]chan *message
+	notifications chan Notification
+	done          chan struct{}
+	serveErr      chan error
+	readErr       error
+	closeOnce     sync.Once
+	closeErr      error
+}
+
+// Connect starts the server in a new goroutine and returns with a client connected to it
+// The server is stopped when the client is closed.
+func Connect(s *Server) *Client {
+	serverR, clientW := io.Pipe()
+	clientR, serverW := io.Pipe()
+
+	c := NewClient(clientR, clientW)
+	c.serveErr = make(chan error, 1)
+	go func() {
+		err := s.Serve(serverR, serverW)
+		serverW.Close()
+		serverR.Close()
+		c.serveErr <- err
+	}()
+
+	return c
+}
+
+// NewClient creates a new client which reads the server messages from r and writes the client messages to w
+func NewClient(r io.Reader, w io.WriteCloser) *Client {
+	c := &Client{
+		w:             w,
+		pending:       map[string]chan *message{},
+		notifications: make(chan Notification, notificationBufferSize),
+		done:          make(chan struct{}),
+	}
+	go c.read(bufio.NewReader(r))
+	return c
+}
+
+// Call sends a request and waits for the response
+// If the result is not nil then the response result will be unmarshalled into it.
+func (c *Client) Call(method string, params interface{}, result interface{}) error {
+	c.mu.Lock()
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+	ch := make(chan *message, 1)
+	c.pending[string(id)] = ch
+	c.mu.Unlock()
+
+	if err := c.send(&message{ID: &id, Method: method}, params); err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, result)
+	case <-c.done:
+		return c.closedErr()
+	}
+}
+
+// Notify sends a notification
+func (c *Client) Notify(method string, params interface{}) error {
+	return c.send(&message{Method: method}, params)
+}
+
+// Notifications returns with the channel of the notifications sent by the server
+func (c *Client) Notifications() <-chan Notification {
+	return c.notifications
+}
+
+// Close closes the connection and returns with the server error if the server was started by Connect
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.w.Close()
+		if c.serveErr != nil {
+			if serveErr := <-c.serveErr; serveErr != nil {
+				c.closeErr = serveErr
+			}
+		}
+		<-c.done
+	})
+	return c.closeErr
+}
+
+func (c *Client) send(msg *message, params interface{}) error {
+	if params != nil {
+		var err error
+		if msg.Params, err = json.Marshal(params); err != nil {
+			return err
+		}
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return writeMessage(c.w, msg)
+}
+
+func (c *Client) read(r *bufio.Reader) {
+	defer close(c.done)
+	for {
+		msg, err := readMessage(r)
+		if err != nil {
+			c.mu.Lock()
+			c.readErr = err
+			c.mu.Unlock()
+			return
+		}
+
+		if msg.ID == nil {
+			c.notifications <- Notification{Method: msg.Method, Params: msg.Params}
+			continue
+		}
+
+		c.mu.Lock()
+		ch, ok := c.pending[string(*msg.ID)]
+		delete(c.pending, string(*msg.ID))
+		c.mu.Unlock()
+		if ok {
+			ch <- msg
+		}
+	}
+}
+
+func (c *Client) closedErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.readErr != nil && c.readErr != io.EOF {
+		return c.readErr
+	}
+	return errors.New("the connection was closed")
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lsp
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+)
+
+// document is an open text document
+//
+// The positions in the parse tree and in the errors are relative to the base position at the time of parsing, as the
+// file can be moved in the file set if a previous document changes.
+type document struct {
+	uri     string
+	version int
+	file    *text.File
+	base    parsley.Pos
+	root    parsley.Node
+	errs    []parsley.Error
+}
+
+// parse parses the document and publishes the diagnostics
+func (s *Server) parse(d *document) {
+	ctx := parsley.NewContext(s.fileSet, text.NewReader(d.file))
+	if s.configure != nil {
+		s.configure(ctx)
+	}
+
+	d.base = d.file.Pos(0)
+	root, err := parsley.Parse(ctx, s.parser)
+	d.root = root
+	d.errs = nil
+
+	diagnostics := []Diagnostic{}
+	if root != nil {
+		// The recovered errors are kept in the tree as error nodes
+		parsley.Walk(root, func(n parsley.Node) bool {
+			if errorNode, ok := n.(parsley.ErrorNode); ok {
+				d.errs = append(d.errs, errorNode.Error())
+				r, _ := s.fileSet.NodeRange(n)
+				diagnostics = append(diagnostics, s.diagnostic(errorNode.Error(), d.rangeOfLocations(r)))
+			}
+			return false
+		})
+	} else if err != nil {
+		for _, parseErr := range parseErrors(err, d.base) {
+			d.errs = append(d.errs, parseErr)
+			diagnostics = append(diagnostics, s.diagnostic(parseErr, d.rangeOf(parseErr.Pos(), parseErr.Pos())))
+		}
+	}
+
+	s.publishDiagnostics(d.uri, d.version, diagnostics)
+}
+
+func (s *Server) diagnostic(err parsley.Error, r Range) Diagnostic {
+	return Diagnostic{
+		Range:    r,
+		Severity: SeverityError,
+		Source:   s.name,
+		Message:  err.Error(),
+	}
+}
+
+// parseErrors returns with the errors wrapped in the error returned by parsley.Parse
+// If an error has no position (e.g. a custom error formatter doesn't wrap it) then the start of the document is used.
+func parseErrors(err error, base parsley.Pos) []parsley.Error {
+	errs := []error{err}
+	var list parsley.ErrorList
+	if errors.As(err, &list) {
+		errs = list
+	}
+
+	res := make([]parsley.Error, 0, len(errs))
+	for _, e := range errs {
+		var parseErr parsley.Error
+		if !errors.As(e, &parseErr) {
+			parseErr = parsley.NewError(base, e)
+		}
+		res = append(res, parseErr)
+	}
+	return res
+}
+
+// position converts a parse tree position to an LSP position
+func (d *document) position(pos parsley.Pos) Position {
+	return d.offsetPosition(int(pos - d.base))
+}
+
+// offsetPosition converts a byte offset in the document to an LSP position
+func (d *document) offsetPosition(offset int) Position {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > d.file.Len() {
+		offset = d.file.Len()
+	}
+
+	line, column, ok := d.file.LineColumn(offset)
+	if !ok {
+		return Position{}
+	}
+	content, _ := d.file.Line(line)
+	if column-1 < len(content) {
+		content = content[:column-1]
+	}
+	return Position{Line: line - 1, Character: utf16Len(content)}
+}
+
+// rangeOfLocations converts a range returned by the file set to an LSP range
+func (d *document) rangeOfLocations(r parsley.Range) Range {
+	return Range{Start: d.offsetPosition(r.Start.Offset), End: d.offsetPosition(r.End.Offset)}
+}
+
+// rangeOf converts the parse tree positions to an LSP range
+func (d *document) rangeOf(start, end parsley.Pos) Range {
+	return Range{Start: d.position(start), End: d.position(end)}
+}
+
+// pos converts an LSP position to a parse tree position
+func (d *document) pos(p Position) (parsley.Pos, bool) {
+	offset, ok := d.offset(p)
+	if !ok {
+		return parsley.NilPos, false
+	}
+	return d.base + parsley.Pos(offset), true
+}
+
+// currentPos converts an LSP position to the current position in the file set
+func (d *document) currentPos(p Position) (parsley.Pos, bool) {
+	offset, ok := d.offset(p)
+	if !ok {
+		return parsley.NilPos, false
+	}
+	return d.file.Pos(offset), true
+}
+
+func (d *document) offset(p Position) (int, bool) {
+	content, ok := d.file.Line(p.Line + 1)
+	if !ok {
+		return 0, false
+	}
+
+	column, units := 0, 0
+	for column < len(content) && units < p.Character {
+		r, size := utf8.DecodeRune(content[column:])
+		column += size
+		units += utf16RuneLen(r)
+	}
+
+	return d.file.Offset(p.Line+1, column+1)
+}
+
+// utf16Len returns with the number of UTF-16 code units in the given UTF-8 text
+func utf16Len(b []byte) int {
+	n := 0
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		n += utf16RuneLen(r)
+		b = b[size:]
+	}
+	return n
+}
+
+func utf16RuneLen(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
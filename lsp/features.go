@@ -0,0 +1,232 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lsp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+// defaultSemanticTokenType highlights the built-in terminals
+func defaultSemanticTokenType(node parsley.Node) (string, bool) {
+	if _, ok := node.(*terminal.OpNode); ok {
+		return "operator", true
+	}
+
+	switch node.Token() {
+	case "STRING", "CHAR":
+		return "string", true
+	case "INTEGER", "FLOAT", "TIME_DURATION":
+		return "number", true
+	case "BOOL", "NIL":
+		return "keyword", true
+	default:
+		return "", false
+	}
+}
+
+// nodes returns with all the nodes in the tree
+func nodes(root parsley.Node) []parsley.Node {
+	var res []parsley.Node
+	if root == nil {
+		return res
+	}
+	parsley.Walk(root, func(n parsley.Node) bool {
+		res = append(res, n)
+		return false
+	})
+	return res
+}
+
+func (s *Server) documentSymbols(d *document) []DocumentSymbol {
+	res := []DocumentSymbol{}
+	if s.symbol == nil {
+		return res
+	}
+
+	type symbol struct {
+		node     parsley.Node
+		symbol   DocumentSymbol
+		children []*symbol
+	}
+
+	var symbols []*symbol
+	for _, n := range nodes(d.root) {
+		name, kind, ok := s.symbol(n)
+		if !ok {
+			continue
+		}
+		r := d.rangeOf(n.Pos(), n.ReaderPos())
+		symbols = append(symbols, &symbol{
+			node:   n,
+			symbol: DocumentSymbol{Name: name, Kind: kind, Range: r, SelectionRange: r},
+		})
+	}
+
+	sort.SliceStable(symbols, func(i, j int) bool {
+		if symbols[i].node.Pos() != symbols[j].node.Pos() {
+			return symbols[i].node.Pos() < symbols[j].node.Pos()
+		}
+		return symbols[i].node.ReaderPos() > symbols[j].node.ReaderPos()
+	})
+
+	var roots, stack []*symbol
+	for _, sym := range symbols {
+		for len(stack) > 0 && sym.node.Pos() >= stack[len(stack)-1].node.ReaderPos() {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) > 0 {
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, sym)
+		} else {
+			roots = append(roots, sym)
+		}
+		stack = append(stack, sym)
+	}
+
+	var build func(symbols []*symbol) []DocumentSymbol
+	build = func(symbols []*symbol) []DocumentSymbol {
+		res := make([]DocumentSymbol, len(symbols))
+		for i, sym := range symbols {
+			res[i] = sym.symbol
+			if len(sym.children) > 0 {
+				res[i].Children = build(sym.children)
+			}
+		}
+		return res
+	}
+
+	return append(res, build(roots)...)
+}
+
+func (s *Server) foldingRanges(d *document) []FoldingRange {
+	endLines := map[int]int{}
+	for _, n := range nodes(d.root) {
+		if _, ok := n.(parsley.NonTerminalNode); !ok || n.ReaderPos() <= n.Pos() {
+			continue
+		}
+		if s.folding != nil && !s.folding(n) {
+			continue
+		}
+		start := d.position(n.Pos()).Line
+		end := d.position(n.ReaderPos() - 1).Line
+		if end > start && end > endLines[start] {
+			endLines[start] = end
+		}
+	}
+
+	res := make([]FoldingRange, 0, len(endLines))
+	for start, end := range endLines {
+		res = append(res, FoldingRange{StartLine: start, EndLine: end})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].StartLine < res[j].StartLine })
+	return res
+}
+
+func (s *Server) hoverAt(d *document, p Position) *Hover {
+	pos, ok := d.pos(p)
+	if !ok {
+		return nil
+	}
+
+	node := parsley.NodeAt(d.root, pos)
+	if node == nil {
+		return nil
+	}
+
+	contents, ok := "", false
+	if s.hover != nil {
+		contents, ok = s.hover(node)
+	}
+	if !ok {
+		contents = defaultHover(node)
+	}
+
+	r := d.rangeOf(node.Pos(), node.ReaderPos())
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: contents},
+		Range:    &r,
+	}
+}
+
+func defaultHover(node parsley.Node) string {
+	var sb strings.Builder
+	sb.WriteString("`" + node.Token() + "`")
+	if schema := node.Schema(); schema != nil {
+		fmt.Fprintf(&sb, ": %v", schema)
+	}
+	if n, ok := node.(parsley.LiteralNode); ok {
+		fmt.Fprintf(&sb, "\n\n```\n%#v\n```", n.Value())
+	}
+	return sb.String()
+}
+
+func (s *Server) semanticTokens(d *document) *SemanticTokens {
+	typeIndex := make(map[string]int, len(SemanticTokenTypes))
+	for i, t := range SemanticTokenTypes {
+		typeIndex[t] = i
+	}
+
+	type token struct {
+		line, char, length, typ int
+	}
+
+	var tokens []token
+	for _, n := range nodes(d.root) {
+		if _, ok := n.(parsley.LiteralNode); !ok || n.ReaderPos() <= n.Pos() {
+			continue
+		}
+		t, ok := s.semanticTokenType(n)
+		if !ok {
+			continue
+		}
+		typ, ok := typeIndex[t]
+		if !ok {
+			continue
+		}
+
+		// Tokens can not span multiple lines, so we split them
+		start, end := d.position(n.Pos()), d.position(n.ReaderPos())
+		for line := start.Line; line <= end.Line; line++ {
+			char, endChar := 0, end.Character
+			if line == start.Line {
+				char = start.Character
+			}
+			if line < end.Line {
+				content, _ := d.file.Line(line + 1)
+				endChar = utf16Len(content)
+			}
+			if endChar > char {
+				tokens = append(tokens, token{line: line, char: char, length: endChar - char, typ: typ})
+			}
+		}
+	}
+
+	sort.SliceStable(tokens, func(i, j int) bool {
+		if tokens[i].line != tokens[j].line {
+			return tokens[i].line < tokens[j].line
+		}
+		return tokens[i].char < tokens[j].char
+	})
+
+	data := make([]int, 0, len(tokens)*5)
+	prevLine, prevChar := 0, 0
+	for _, t := range tokens {
+		deltaChar := t.char
+		if t.line == prevLine {
+			deltaChar -= prevChar
+		}
+		data = append(data, t.line-prevLine, deltaChar, t.length, t.typ, 0)
+		prevLine, prevChar = t.line, t.char
+	}
+
+	return &SemanticTokens{Data: data}
+}
@@ -0,0 +1,300 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package lsp implements a Language Server Protocol server for languages defined with parsley parsers
+//
+// The server supports the following features:
+//   - diagnostics from the parse, transformation and static check errors
+//   - document symbols (see Server.Symbol)
+//   - folding ranges for the multi-line non-terminal nodes
+//   - hover text with the token, schema and value of the node under the cursor
+//   - semantic tokens from the terminal token names
+//
+// The documents are synchronized incrementally and all open documents are stored in a single file set.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+)
+
+var nullID = json.RawMessage("null")
+
+// Server is a language server which parses the documents with the given parser
+type Server struct {
+	name              string
+	parser            parsley.Parser
+	configure         func(ctx *parsley.Context)
+	symbol            func(node parsley.Node) (string, SymbolKind, bool)
+	hover             func(node parsley.Node) (string, bool)
+	folding           func(node parsley.Node) bool
+	semanticTokenType func(node parsley.Node) (string, bool)
+	fileSet           *parsley.FileSet
+	documents         map[string]*document
+	files             map[string]*text.File
+	w                 io.Writer
+	initialized       bool
+	shutdown          bool
+}
+
+// NewServer creates a new language server
+// The parser should match the whole document, e.g. it should be wrapped in combinator.Sentence.
+func NewServer(name string, p parsley.Parser) *Server {
+	return &Server{
+		name:              name,
+		parser:            p,
+		semanticTokenType: defaultSemanticTokenType,
+		fileSet:           parsley.NewFileSet(),
+		documents:         map[string]*document{},
+		files:             map[string]*text.File{},
+	}
+}
+
+// Configure sets a function which is called on every new parsing context
+// You can use it to set the user context, register keywords or to enable the transformation and static checking.
+// The error formatter on the context will be overridden.
+func (s *Server) Configure(f func(ctx *parsley.Context)) *Server {
+	s.configure = f
+	return s
+}
+
+// Symbol sets a function which decides whether a node is a document symbol, by default there are no symbols
+// The symbols are nested based on their ranges.
+func (s *Server) Symbol(f func(node parsley.Node) (name string, kind SymbolKind, ok bool)) *Server {
+	s.symbol = f
+	return s
+}
+
+// Hover sets a function which returns with the hover text (in markdown) for the node under the cursor
+// If it returns false then the default text is displayed with the token, schema and value of the node.
+func (s *Server) Hover(f func(node parsley.Node) (string, bool)) *Server {
+	s.hover = f
+	return s
+}
+
+// Folding sets a function which decides whether a multi-line non-terminal node can be folded, by default all can
+func (s *Server) Folding(f func(node parsley.Node) bool) *Server {
+	s.folding = f
+	return s
+}
+
+// SemanticTokenType sets a function which returns with the semantic token type for a terminal node
+// The type must be one of SemanticTokenTypes. By default the string, number, bool, nil and operator terminals
+// are highlighted.
+func (s *Server) SemanticTokenType(f func(node parsley.Node) (string, bool)) *Server {
+	s.semanticTokenType = f
+	return s
+}
+
+// FileSet returns with the file set containing the open documents
+func (s *Server) FileSet() *parsley.FileSet {
+	return s.fileSet
+}
+
+// ServeStdio serves the Language Server Protocol on the standard input and output
+func (s *Server) ServeStdio() error {
+	return s.Serve(os.Stdin, os.Stdout)
+}
+
+// Serve reads the requests from r and writes the responses and notifications to w
+// It returns when the exit notification is received or r is closed.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.w = w
+	br := bufio.NewReader(r)
+	for {
+		msg, err := readMessage(br)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			var respErr *ResponseError
+			if errors.As(err, &respErr) {
+				id := nullID
+				if err := writeMessage(w, &message{ID: &id, Error: respErr}); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+
+		if msg.Method == "exit" {
+			return nil
+		}
+
+		if msg.ID == nil {
+			s.handleNotification(msg.Method, msg.Params)
+			continue
+		}
+
+		result, err := s.handleRequest(msg.Method, msg.Params)
+		response := &message{ID: msg.ID}
+		if err != nil {
+			if !errors.As(err, &response.Error) {
+				response.Error = &ResponseError{Code: CodeInternalError, Message: err.Error()}
+			}
+		} else if response.Result, err = json.Marshal(result); err != nil {
+			return err
+		}
+		if err := writeMessage(w, response); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handleRequest(method string, params json.RawMessage) (interface{}, error) {
+	if s.shutdown {
+		return nil, &ResponseError{Code: CodeInvalidRequest, Message: "the server is shutting down"}
+	}
+
+	if method == "initialize" {
+		s.initialized = true
+		return s.initialize(), nil
+	}
+
+	if !s.initialized {
+		return nil, &ResponseError{Code: CodeServerNotInitialized, Message: "the server is not initialized"}
+	}
+
+	switch method {
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/documentSymbol", "textDocument/foldingRange", "textDocument/semanticTokens/full",
+		"textDocument/hover":
+	default:
+		return nil, &ResponseError{Code: CodeMethodNotFound, Message: "method not found: " + method}
+	}
+
+	var p TextDocumentPositionParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &ResponseError{Code: CodeInvalidParams, Message: err.Error()}
+	}
+
+	d, ok := s.documents[p.TextDocument.URI]
+	if !ok {
+		return nil, &ResponseError{Code: CodeInvalidParams, Message: "unknown document: " + p.TextDocument.URI}
+	}
+
+	switch method {
+	case "textDocument/documentSymbol":
+		return s.documentSymbols(d), nil
+	case "textDocument/foldingRange":
+		return s.foldingRanges(d), nil
+	case "textDocument/semanticTokens/full":
+		return s.semanticTokens(d), nil
+	default:
+		return s.hoverAt(d, p.Position), nil
+	}
+}
+
+func (s *Server) initialize() interface{} {
+	return map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"textDocumentSync": map[string]interface{}{
+				"openClose": true,
+				"change":    2,
+			},
+			"documentSymbolProvider": true,
+			"foldingRangeProvider":   true,
+			"hoverProvider":          true,
+			"semanticTokensProvider": map[string]interface{}{
+				"legend": map[string]interface{}{
+					"tokenTypes":     SemanticTokenTypes,
+					"tokenModifiers": []string{},
+				},
+				"full": true,
+			},
+		},
+		"serverInfo": map[string]interface{}{
+			"name": s.name,
+		},
+	}
+}
+
+func (s *Server) handleNotification(method string, params json.RawMessage) {
+	if !s.initialized || s.shutdown {
+		return
+	}
+
+	switch method {
+	case "textDocument/didOpen":
+		var p DidOpenTextDocumentParams
+		if json.Unmarshal(params, &p) != nil {
+			return
+		}
+		file, ok := s.files[p.TextDocument.URI]
+		if ok {
+			// A reopened document reuses its file, so there is only one file with the same name in the file set
+			if _, err := s.fileSet.Edit(file.Pos(0), file.Pos(file.Len()), []byte(p.TextDocument.Text)); err != nil {
+				return
+			}
+		} else {
+			file = text.NewEditableFile(p.TextDocument.URI, []byte(p.TextDocument.Text))
+			s.fileSet.AddFile(file)
+			s.files[p.TextDocument.URI] = file
+		}
+		d := &document{uri: p.TextDocument.URI, version: p.TextDocument.Version, file: file}
+		s.documents[d.uri] = d
+		s.parse(d)
+	case "textDocument/didChange":
+		var p DidChangeTextDocumentParams
+		if json.Unmarshal(params, &p) != nil {
+			return
+		}
+		d, ok := s.documents[p.TextDocument.URI]
+		if !ok {
+			return
+		}
+		for _, change := range p.ContentChanges {
+			if err := s.applyChange(d, change); err != nil {
+				break
+			}
+		}
+		d.version = p.TextDocument.Version
+		s.parse(d)
+	case "textDocument/didClose":
+		var p DidCloseTextDocumentParams
+		if json.Unmarshal(params, &p) != nil {
+			return
+		}
+		d, ok := s.documents[p.TextDocument.URI]
+		if !ok {
+			return
+		}
+		delete(s.documents, d.uri)
+		// The file can't be removed from the file set, so it's emptied (which releases its buffer) and it's reused if
+		// the document is opened again
+		_, _ = s.fileSet.Edit(d.file.Pos(0), d.file.Pos(d.file.Len()), nil)
+		s.publishDiagnostics(d.uri, d.version, []Diagnostic{})
+	}
+}
+
+func (s *Server) applyChange(d *document, change TextDocumentContentChangeEvent) error {
+	start, end := d.file.Pos(0), d.file.Pos(d.file.Len())
+	if change.Range != nil {
+		var ok bool
+		if start, ok = d.currentPos(change.Range.Start); !ok {
+			return errors.New("invalid change range")
+		}
+		if end, ok = d.currentPos(change.Range.End); !ok {
+			return errors.New("invalid change range")
+		}
+	}
+	_, err := s.fileSet.Edit(start, end, []byte(change.Text))
+	return err
+}
+
+func (s *Server) publishDiagnostics(uri string, version int, diagnostics []Diagnostic) {
+	params, _ := json.Marshal(PublishDiagnosticsParams{URI: uri, Version: version, Diagnostics: diagnostics})
+	_ = writeMessage(s.w, &message{Method: "textDocument/publishDiagnostics", Params: params})
+}
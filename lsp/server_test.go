@@ -0,0 +1,362 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lsp_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/grammar"
+	"github.com/conflowio/parsley/lsp"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+func newParser() parsley.Parser {
+	g, err := grammar.Parse("test.grammar", []byte(`
+		file       = statement* ;
+		statement  = block | assignment ;
+		block      = /[a-z]+/ "{" statement* "}" ;
+		assignment = /[a-z]+/ "=" value ;
+		value      = INTEGER | STRING ;
+	`))
+	if err != nil {
+		panic(err)
+	}
+	p, err := g.Parser("file")
+	if err != nil {
+		panic(err)
+	}
+	return combinator.Sentence(text.RightTrim(p, text.WsSpacesNl))
+}
+
+// Let's create a language server which highlights the built-in terminals and reports the syntax errors
+func ExampleServer() {
+	server := lsp.NewServer("mylang", newParser()).
+		Configure(func(ctx *parsley.Context) {
+			ctx.EnableStaticCheck()
+		})
+
+	if err := server.ServeStdio(); err != nil {
+		panic(err)
+	}
+}
+
+var _ = Describe("Server", func() {
+
+	var (
+		server *lsp.Server
+		client *lsp.Client
+	)
+
+	BeforeEach(func() {
+		server = lsp.NewServer("test", newParser())
+	})
+
+	JustBeforeEach(func() {
+		client = lsp.Connect(server)
+	})
+
+	AfterEach(func() {
+		Expect(client.Close()).To(Succeed())
+	})
+
+	initialize := func() {
+		var res map[string]interface{}
+		Expect(client.Call("initialize", map[string]interface{}{}, &res)).To(Succeed())
+		Expect(client.Notify("initialized", map[string]interface{}{})).To(Succeed())
+	}
+
+	diagnostics := func() lsp.PublishDiagnosticsParams {
+		var n lsp.Notification
+		Eventually(client.Notifications()).Should(Receive(&n))
+		Expect(n.Method).To(Equal("textDocument/publishDiagnostics"))
+		var params lsp.PublishDiagnosticsParams
+		Expect(json.Unmarshal(n.Params, &params)).To(Succeed())
+		return params
+	}
+
+	open := func(uri string, content string) lsp.PublishDiagnosticsParams {
+		Expect(client.Notify("textDocument/didOpen", lsp.DidOpenTextDocumentParams{
+			TextDocument: lsp.TextDocumentItem{URI: uri, LanguageID: "test", Version: 1, Text: content},
+		})).To(Succeed())
+		return diagnostics()
+	}
+
+	change := func(uri string, version int, changes ...lsp.TextDocumentContentChangeEvent) lsp.PublishDiagnosticsParams {
+		Expect(client.Notify("textDocument/didChange", lsp.DidChangeTextDocumentParams{
+			TextDocument:   lsp.VersionedTextDocumentIdentifier{URI: uri, Version: version},
+			ContentChanges: changes,
+		})).To(Succeed())
+		return diagnostics()
+	}
+
+	call := func(method string, uri string, pos *lsp.Position, result interface{}) error {
+		params := lsp.TextDocumentPositionParams{TextDocument: lsp.TextDocumentIdentifier{URI: uri}}
+		if pos != nil {
+			params.Position = *pos
+		}
+		return client.Call(method, params, result)
+	}
+
+	It("should return the capabilities", func() {
+		var res map[string]interface{}
+		Expect(client.Call("initialize", map[string]interface{}{}, &res)).To(Succeed())
+		Expect(res["serverInfo"]).To(Equal(map[string]interface{}{"name": "test"}))
+		capabilities := res["capabilities"].(map[string]interface{})
+		Expect(capabilities["hoverProvider"]).To(BeTrue())
+		Expect(capabilities["documentSymbolProvider"]).To(BeTrue())
+		Expect(capabilities["foldingRangeProvider"]).To(BeTrue())
+		Expect(capabilities["textDocumentSync"]).To(Equal(map[string]interface{}{"openClose": true, "change": float64(2)}))
+	})
+
+	It("should return an error if the server is not initialized", func() {
+		err := call("textDocument/hover", "file:///a", nil, nil)
+		Expect(err).To(MatchError(&lsp.ResponseError{Code: lsp.CodeServerNotInitialized, Message: "the server is not initialized"}))
+	})
+
+	Context("when the server is initialized", func() {
+		JustBeforeEach(func() {
+			initialize()
+		})
+
+		It("should publish no diagnostics for a valid document", func() {
+			res := open("file:///a", "a = 1\n")
+			Expect(res).To(Equal(lsp.PublishDiagnosticsParams{URI: "file:///a", Version: 1, Diagnostics: []lsp.Diagnostic{}}))
+		})
+
+		It("should publish the parse errors", func() {
+			res := open("file:///a", "a = 1\nb = ")
+			Expect(res.Diagnostics).To(Equal([]lsp.Diagnostic{
+				{
+					Range:    lsp.Range{Start: lsp.Position{Line: 1, Character: 4}, End: lsp.Position{Line: 1, Character: 4}},
+					Severity: lsp.SeverityError,
+					Source:   "test",
+					Message:  `was expecting integer value or string literal, found end of input`,
+				},
+			}))
+		})
+
+		Context("when the parser recovers from the errors", func() {
+			BeforeEach(func() {
+				p := combinator.SepBy(
+					combinator.Recover(terminal.Integer("integer"), combinator.Choice(terminal.Rune(','), parser.End())),
+					terminal.Rune(','),
+				)
+				server = lsp.NewServer("test", combinator.Sentence(p))
+			})
+
+			It("should publish the recovered errors with the range of the skipped input", func() {
+				res := open("file:///a", "1,ab,3")
+				Expect(res.Diagnostics).To(Equal([]lsp.Diagnostic{
+					{
+						Range:    lsp.Range{Start: lsp.Position{Line: 0, Character: 2}, End: lsp.Position{Line: 0, Character: 4}},
+						Severity: lsp.SeverityError,
+						Source:   "test",
+						Message:  `was expecting integer value, found "ab"`,
+					},
+				}))
+			})
+		})
+
+		It("should apply the incremental changes", func() {
+			open("file:///a", "a = 1\nb = ")
+			res := change("file:///a", 2, lsp.TextDocumentContentChangeEvent{
+				Range: &lsp.Range{Start: lsp.Position{Line: 1, Character: 4}, End: lsp.Position{Line: 1, Character: 4}},
+				Text:  `"é"`,
+			})
+			Expect(res.Version).To(Equal(2))
+			Expect(res.Diagnostics).To(BeEmpty())
+
+			res = change("file:///a", 3, lsp.TextDocumentContentChangeEvent{
+				Range: &lsp.Range{Start: lsp.Position{Line: 1, Character: 7}, End: lsp.Position{Line: 1, Character: 7}},
+				Text:  " x",
+			})
+			Expect(res.Diagnostics).To(HaveLen(1))
+			Expect(res.Diagnostics[0].Range.Start).To(Equal(lsp.Position{Line: 1, Character: 9}))
+			Expect(res.Diagnostics[0].Message).To(Equal(`was expecting "{" or "=", found end of input`))
+		})
+
+		It("should apply the full changes", func() {
+			open("file:///a", "a = ")
+			res := change("file:///a", 2, lsp.TextDocumentContentChangeEvent{Text: "b = 2"})
+			Expect(res.Diagnostics).To(BeEmpty())
+		})
+
+		It("should clear the diagnostics when a document is closed", func() {
+			open("file:///a", "a = ")
+			Expect(client.Notify("textDocument/didClose", lsp.DidCloseTextDocumentParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: "file:///a"},
+			})).To(Succeed())
+			Expect(diagnostics().Diagnostics).To(BeEmpty())
+
+			err := call("textDocument/hover", "file:///a", &lsp.Position{}, nil)
+			Expect(err).To(MatchError(&lsp.ResponseError{Code: lsp.CodeInvalidParams, Message: "unknown document: file:///a"}))
+		})
+
+		It("should reuse the file when a closed document is reopened", func() {
+			open("file:///a", "a = 1")
+			open("file:///b", "b = 2")
+			Expect(client.Notify("textDocument/didClose", lsp.DidCloseTextDocumentParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: "file:///a"},
+			})).To(Succeed())
+			diagnostics()
+			open("file:///a", "c = 345")
+
+			pos, ok := server.FileSet().LookupPos("file:///a", 1, 5)
+			Expect(ok).To(BeTrue())
+			Expect(server.FileSet().Position(pos).String()).To(Equal("file:///a:1:5"))
+
+			var res lsp.Hover
+			Expect(call("textDocument/hover", "file:///a", &lsp.Position{Line: 0, Character: 4}, &res)).To(Succeed())
+			Expect(res.Contents.Value).To(ContainSubstring("345"))
+			Expect(call("textDocument/hover", "file:///b", &lsp.Position{Line: 0, Character: 4}, &res)).To(Succeed())
+			Expect(res.Contents.Value).To(ContainSubstring("2"))
+			Expect(res.Range).To(Equal(&lsp.Range{Start: lsp.Position{Line: 0, Character: 4}, End: lsp.Position{Line: 0, Character: 5}}))
+		})
+
+		It("should return the hover text for the innermost node", func() {
+			open("file:///a", "a = 1\nb = 23")
+			var res lsp.Hover
+			Expect(call("textDocument/hover", "file:///a", &lsp.Position{Line: 1, Character: 5}, &res)).To(Succeed())
+			Expect(res).To(Equal(lsp.Hover{
+				Contents: lsp.MarkupContent{Kind: "markdown", Value: "`INTEGER`: integer\n\n```\n23\n```"},
+				Range:    &lsp.Range{Start: lsp.Position{Line: 1, Character: 4}, End: lsp.Position{Line: 1, Character: 6}},
+			}))
+		})
+
+		It("should keep the positions when a previous document is changed", func() {
+			open("file:///a", "a = 1")
+			open("file:///b", "b = 23")
+			change("file:///a", 2, lsp.TextDocumentContentChangeEvent{Text: "a = 1\nc = 2\n"})
+
+			var res lsp.Hover
+			Expect(call("textDocument/hover", "file:///b", &lsp.Position{Line: 0, Character: 4}, &res)).To(Succeed())
+			Expect(res.Contents.Value).To(ContainSubstring("23"))
+			Expect(res.Range).To(Equal(&lsp.Range{Start: lsp.Position{Line: 0, Character: 4}, End: lsp.Position{Line: 0, Character: 6}}))
+		})
+
+		It("should return null if there is no node under the cursor", func() {
+			open("file:///a", "a = 1")
+			var res *lsp.Hover
+			Expect(call("textDocument/hover", "file:///a", &lsp.Position{Line: 0, Character: 5}, &res)).To(Succeed())
+			Expect(res).To(BeNil())
+		})
+
+		It("should return the folding ranges", func() {
+			open("file:///a", "x {\n  a = 1\n  y {\n    b = 2\n  }\n}\nc = 3")
+			var res []lsp.FoldingRange
+			Expect(call("textDocument/foldingRange", "file:///a", nil, &res)).To(Succeed())
+			Expect(res).To(Equal([]lsp.FoldingRange{
+				{StartLine: 0, EndLine: 6},
+				{StartLine: 1, EndLine: 4},
+				{StartLine: 2, EndLine: 4},
+			}))
+		})
+
+		It("should return the semantic tokens", func() {
+			open("file:///a", "a = 1\nb = \"x\"")
+			var res lsp.SemanticTokens
+			Expect(call("textDocument/semanticTokens/full", "file:///a", nil, &res)).To(Succeed())
+			Expect(res.Data).To(Equal([]int{
+				0, 2, 1, 21, 0,
+				0, 2, 1, 19, 0,
+				1, 2, 1, 21, 0,
+				0, 2, 3, 18, 0,
+			}))
+		})
+
+		It("should return no document symbols by default", func() {
+			open("file:///a", "a = 1")
+			var res []lsp.DocumentSymbol
+			Expect(call("textDocument/documentSymbol", "file:///a", nil, &res)).To(Succeed())
+			Expect(res).To(BeEmpty())
+		})
+
+		Context("when a symbol function is set", func() {
+			BeforeEach(func() {
+				server.Symbol(func(node parsley.Node) (string, lsp.SymbolKind, bool) {
+					switch node.Token() {
+					case "block":
+						name, _ := parsley.EvaluateNode(nil, node.(parsley.NonTerminalNode).Children()[0])
+						return name.(string), lsp.SymbolKindNamespace, true
+					case "assignment":
+						name, _ := parsley.EvaluateNode(nil, node.(parsley.NonTerminalNode).Children()[0])
+						return name.(string), lsp.SymbolKindVariable, true
+					default:
+						return "", 0, false
+					}
+				})
+			})
+
+			It("should return the nested symbols", func() {
+				open("file:///a", "x {\n  a = 1\n}\nc = 3")
+				var res []lsp.DocumentSymbol
+				Expect(call("textDocument/documentSymbol", "file:///a", nil, &res)).To(Succeed())
+
+				xRange := lsp.Range{Start: lsp.Position{Line: 0, Character: 0}, End: lsp.Position{Line: 2, Character: 1}}
+				aRange := lsp.Range{Start: lsp.Position{Line: 1, Character: 2}, End: lsp.Position{Line: 1, Character: 7}}
+				cRange := lsp.Range{Start: lsp.Position{Line: 3, Character: 0}, End: lsp.Position{Line: 3, Character: 5}}
+				Expect(res).To(Equal([]lsp.DocumentSymbol{
+					{Name: "x", Kind: lsp.SymbolKindNamespace, Range: xRange, SelectionRange: xRange, Children: []lsp.DocumentSymbol{
+						{Name: "a", Kind: lsp.SymbolKindVariable, Range: aRange, SelectionRange: aRange},
+					}},
+					{Name: "c", Kind: lsp.SymbolKindVariable, Range: cRange, SelectionRange: cRange},
+				}))
+			})
+		})
+
+		Context("when a hover function is set", func() {
+			BeforeEach(func() {
+				server.Hover(func(node parsley.Node) (string, bool) {
+					return "custom " + node.Token(), node.Token() == "INTEGER"
+				})
+			})
+
+			It("should use the hover function", func() {
+				open("file:///a", "a = 1")
+				var res lsp.Hover
+				Expect(call("textDocument/hover", "file:///a", &lsp.Position{Line: 0, Character: 4}, &res)).To(Succeed())
+				Expect(res.Contents.Value).To(Equal("custom INTEGER"))
+
+				Expect(call("textDocument/hover", "file:///a", &lsp.Position{Line: 0, Character: 0}, &res)).To(Succeed())
+				Expect(res.Contents.Value).To(Equal("`REGEXP`\n\n```\n\"a\"\n```"))
+			})
+		})
+
+		Context("when a configure function is set", func() {
+			BeforeEach(func() {
+				server.Configure(func(ctx *parsley.Context) {
+					ctx.EnableStaticCheck()
+					ctx.SetUserContext("user context")
+				})
+			})
+
+			It("should call it for every parse", func() {
+				res := open("file:///a", "a = 1")
+				Expect(res.Diagnostics).To(BeEmpty())
+			})
+		})
+
+		It("should return an error for an unknown method", func() {
+			err := client.Call("unknown", nil, nil)
+			Expect(err).To(MatchError(&lsp.ResponseError{Code: lsp.CodeMethodNotFound, Message: "method not found: unknown"}))
+		})
+
+		It("should stop after the exit notification", func() {
+			Expect(client.Call("shutdown", nil, nil)).To(Succeed())
+			err := call("textDocument/hover", "file:///a", &lsp.Position{}, nil)
+			Expect(err).To(MatchError(&lsp.ResponseError{Code: lsp.CodeInvalidRequest, Message: "the server is shutting down"}))
+			Expect(client.Notify("exit", nil)).To(Succeed())
+		})
+	})
+})
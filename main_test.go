@@ -7,7 +7,9 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/ast/interpreter"
 	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
 	"github.com/conflowio/parsley/parser"
 	"github.com/conflowio/parsley/parsley"
 	"github.com/conflowio/parsley/text"
@@ -141,4 +143,36 @@ var _ = Describe("Parsley", func() {
 		Expect(ctx.CallCount()).To(Equal(237769))
 
 	})
+
+	It("should reuse the cached results after an edit", func() {
+		callCount := 0
+		integer := parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+			callCount++
+			return terminal.Integer("integer").Parse(ctx, leftRecCtx, pos)
+		})
+
+		p := combinator.Sentence(
+			combinator.SepBy(combinator.Memoize(integer), terminal.Rune(',')).Bind(interpreter.Array()),
+		)
+
+		f := text.NewEditableFile("testfile", []byte("1,2,3,4,5"))
+		fs := parsley.NewFileSet(f)
+		ctx := parsley.NewContext(fs, text.NewReader(f))
+		result, err := parsley.Evaluate(ctx, p)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal([]interface{}{int64(1), int64(2), int64(3), int64(4), int64(5)}))
+		Expect(callCount).To(Equal(5))
+
+		e, editErr := fs.Edit(f.Pos(4), f.Pos(5), []byte("33"))
+		Expect(editErr).ToNot(HaveOccurred())
+
+		callCount = 0
+		ctx2 := parsley.NewContext(fs, text.NewReader(f))
+		ctx2.SetResultCache(ctx.ResultCache().ApplyEdit(e))
+		result, err = parsley.Evaluate(ctx2, p)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal([]interface{}{int64(1), int64(2), int64(33), int64(4), int64(5)}))
+		// the regexp engine reads a few characters ahead, so the integer right before the edit is parsed again
+		Expect(callCount).To(Equal(2))
+	})
 })
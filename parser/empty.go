@@ -16,5 +16,5 @@ import (
 func Empty() Func {
 	return Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
 		return ast.EmptyNode(pos), data.EmptyIntSet, nil
-	})
+	}).Trace("EMPTY")
 }
@@ -49,6 +49,11 @@ func (e EndNode) ReaderPos() parsley.Pos {
 func (e EndNode) SetReaderPos(func(parsley.Pos) parsley.Pos) {
 }
 
+// ShiftPos returns with a new node moved by the shifter's delta
+func (e EndNode) ShiftPos(s *parsley.Shifter) parsley.Node {
+	return EndNode(s.Pos(parsley.Pos(e)))
+}
+
 // String returns with a string representation of the node
 func (e EndNode) String() string {
 	return fmt.Sprintf("%s{%d}", e.Token(), e.Pos())
@@ -63,5 +68,5 @@ func End() Func {
 			return EndNode(pos), data.EmptyIntSet, nil
 		}
 		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
-	})
+	}).Trace("EOF")
 }
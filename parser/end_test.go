@@ -53,4 +53,11 @@ var _ = Describe("End", func() {
 		})
 	})
 
+	Describe("EndNode", func() {
+		It("ShiftPos() should return with a moved node", func() {
+			res := parser.EndNode(parsley.Pos(2)).ShiftPos(parsley.NewShifter(3))
+			Expect(res).To(Equal(parser.EndNode(parsley.Pos(5))))
+		})
+	})
+
 })
@@ -23,8 +23,22 @@ func (f Func) Parse(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Po
 // Name returns with a new parser function which overrides the returned error
 // if its position is the same as the reader's position
 // The error will be: "was expecting <name>"
+// The calls are also reported to the context's tracer with the given name.
 func (f Func) Name(name string) Func {
-	return ReturnError(Func(f), parsley.NotFoundError(name))
+	return ReturnError(Func(f), parsley.NotFoundError(name)).trace(name, true)
+}
+
+// Trace returns with a new parser function which reports its calls to the context's tracer with the given name
+// If there is no tracer set on the context then the only overhead is an extra function call (see BenchmarkFuncTrace).
+func (f Func) Trace(name string) Func {
+	return f.trace(name, false)
+}
+
+func (f Func) trace(name string, named bool) Func {
+	id := parsley.NewTraceID()
+	return Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		return parsley.Trace(id, name, named, f, ctx, leftRecCtx, pos)
+	})
 }
 
 // FuncWrapper is a parser which wraps a parser function as a struct
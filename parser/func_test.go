@@ -7,6 +7,8 @@
 package parser_test
 
 import (
+	"testing"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -53,3 +55,54 @@ var _ = Describe("Func", func() {
 		Expect(actualErr).To(BeEquivalentTo(expectedErr))
 	})
 })
+
+var _ = Describe("Func.Trace", func() {
+
+	It("should report the calls to the tracer with the given name", func() {
+		ctx := parsley.NewContext(parsley.NewFileSet(), &parsleyfakes.FakeReader{})
+		tracer := &recordingTracer{}
+		ctx.SetTracer(tracer)
+
+		expectedNode := ast.NewTerminalNode(nil, "x", nil, parsley.Pos(1), parsley.Pos(2))
+		p := parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+			return expectedNode, data.EmptyIntSet, nil
+		}).Trace("X")
+
+		node, _, err := p.Parse(ctx, data.EmptyIntMap, parsley.Pos(1))
+		Expect(node).To(Equal(expectedNode))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tracer.names).To(Equal([]string{"X", "X"}))
+		Expect(tracer.nodes).To(Equal([]parsley.Node{expectedNode}))
+	})
+})
+
+type recordingTracer struct {
+	names []string
+	nodes []parsley.Node
+}
+
+func (r *recordingTracer) Enter(name string, pos parsley.Pos) {
+	r.names = append(r.names, name)
+}
+
+func (r *recordingTracer) Exit(name string, pos parsley.Pos, result parsley.TraceResult) {
+	r.names = append(r.names, name)
+	r.nodes = append(r.nodes, result.Node)
+}
+
+// The tracing wrapper should have a negligible overhead if there is no tracer set on the context
+func benchmarkFunc(b *testing.B, p parsley.Parser) {
+	ctx := parsley.NewContext(parsley.NewFileSet(), &parsleyfakes.FakeReader{})
+	for n := 0; n < b.N; n++ {
+		_, _, _ = p.Parse(ctx, data.EmptyIntMap, parsley.Pos(1))
+	}
+}
+
+var benchmarkNode = ast.NewTerminalNode(nil, "x", nil, parsley.Pos(1), parsley.Pos(2))
+
+func benchmarkParser(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	return benchmarkNode, data.EmptyIntSet, nil
+}
+
+func BenchmarkFunc(b *testing.B)      { benchmarkFunc(b, parser.Func(benchmarkParser)) }
+func BenchmarkFuncTrace(b *testing.B) { benchmarkFunc(b, parser.Func(benchmarkParser).Trace("X")) }
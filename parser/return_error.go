@@ -14,11 +14,13 @@ import (
 // ReturnError will override the returned error by the parser if its position is the same as the reader's position
 func ReturnError(p parsley.Parser, customErr error) Func {
 	return Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+		notFoundErrs := ctx.NotFoundErrors()
 		res, cp, err := p.Parse(ctx, leftRecCtx, pos)
 
 		if err != nil {
-			if err.Pos() == pos && parsley.IsNotFoundError(err) {
+			if err.Pos() == pos && parsley.IsNotFoundError(err) && !parsley.IsCutError(err) {
 				err = parsley.NewError(pos, customErr)
+				ctx.OverrideNotFoundErrors(notFoundErrs, err)
 			}
 
 			return nil, cp, err
@@ -26,6 +28,7 @@ func ReturnError(p parsley.Parser, customErr error) Func {
 
 		if res == nil {
 			err = parsley.NewError(pos, customErr)
+			ctx.OverrideNotFoundErrors(notFoundErrs, err)
 		}
 
 		return res, cp, err
@@ -0,0 +1,270 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parsley
+
+import (
+	"container/list"
+	"unsafe"
+
+	"github.com/conflowio/parsley/data"
+)
+
+// minBacktrackEvictionInterval is the minimum number of saved results between two backtrack evictions
+const minBacktrackEvictionInterval = 256
+
+// resultCacheEntrySize is the estimated memory used by a cached result, excluding the node and the error
+const resultCacheEntrySize = int64(unsafe.Sizeof(Result{})) + int64(unsafe.Sizeof(Pos(0))) + 40
+
+// CachePolicy defines which results are kept in the result cache, see Context.SetCachePolicy
+//
+// The zero value keeps all results until they are discarded by Context.Commit (the cut points).
+type CachePolicy struct {
+	// MaxEntries is the maximum number of results kept in the cache, the least recently used results are evicted first
+	// Zero means no limit.
+	MaxEntries int
+	// EvictBacktracked enables to drop the results before the lowest position the parsing can still backtrack to
+	// The backtrack points are registered by the combinators which might continue at an earlier position than the
+	// input read by the called parsers (e.g. Choice, Any and Optional, see Context.EnterBacktrackPoint).
+	// The results are also evicted this way if the reader discards the input automatically (see AutoDiscarder).
+	EvictBacktracked bool
+}
+
+// ResultCacheStats contains the result cache statistics, see Context.ResultCacheStats
+// Apart from the number of entries only the results saved and queried through the context are counted.
+type ResultCacheStats struct {
+	Entries     int
+	PeakEntries int
+	Saves       int
+	Hits        int
+	Misses      int
+	Evictions   int
+	// EstimatedBytes is a rough estimate of the memory used by the cache entries, excluding the nodes and errors
+	EstimatedBytes int64
+}
+
+// HitRate returns with the ratio of the successful lookups
+func (s ResultCacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+type resultCacheKey struct {
+	parserIndex int
+	pos         Pos
+}
+
+// resultCacheTracker applies the cache policy and collects the statistics
+type resultCacheTracker struct {
+	policy             CachePolicy
+	stats              ResultCacheStats
+	lru                *list.List
+	lruElements        map[resultCacheKey]*list.Element
+	backtrackPositions []Pos
+	lowestBacktrackPos Pos
+	cutPos             Pos
+	evictedBefore      Pos
+	savesSinceEviction int
+	discarder          Discarder
+}
+
+func (t *resultCacheTracker) reset(policy CachePolicy, rc ResultCache) {
+	t.policy = policy
+	t.backtrackPositions = nil
+
+	t.lru, t.lruElements = nil, nil
+	if policy.MaxEntries > 0 {
+		t.lru = list.New()
+		t.lruElements = make(map[resultCacheKey]*list.Element, rc.Len())
+		rc.Each(func(parserIndex int, pos Pos, _ *Result) {
+			t.touch(resultCacheKey{parserIndex: parserIndex, pos: pos})
+		})
+		t.evictOverLimit(rc)
+	}
+
+	if n := rc.Len(); n > t.stats.PeakEntries {
+		t.stats.PeakEntries = n
+	}
+}
+
+func (t *resultCacheTracker) saved(rc ResultCache, key resultCacheKey) {
+	t.stats.Saves++
+	t.savesSinceEviction++
+
+	if t.lru != nil {
+		t.touch(key)
+		t.evictOverLimit(rc)
+	}
+
+	if n := rc.Len(); n > t.stats.PeakEntries {
+		t.stats.PeakEntries = n
+	}
+
+	if t.tracksBacktrackPoints() {
+		t.evictBacktracked(rc)
+	}
+}
+
+// tracksBacktrackPoints returns true if the backtrack points are used for evicting results or discarding the input
+func (t *resultCacheTracker) tracksBacktrackPoints() bool {
+	return t.policy.EvictBacktracked || t.discarder != nil
+}
+
+func (t *resultCacheTracker) touch(key resultCacheKey) {
+	if e, ok := t.lruElements[key]; ok {
+		t.lru.MoveToFront(e)
+		return
+	}
+	t.lruElements[key] = t.lru.PushFront(key)
+}
+
+func (t *resultCacheTracker) evictOverLimit(rc ResultCache) {
+	for t.lru.Len() > t.policy.MaxEntries {
+		key := t.lru.Remove(t.lru.Back()).(resultCacheKey)
+		delete(t.lruElements, key)
+		if rc.Delete(key.parserIndex, key.pos) {
+			t.stats.Evictions++
+		}
+	}
+}
+
+// evictBacktracked drops the results before the lowest backtrack position (or the last cut if it's higher)
+// To keep the amortized cost low it only runs if enough results were saved since the last run.
+func (t *resultCacheTracker) evictBacktracked(rc ResultCache) {
+	evictBefore := t.lowestBacktrackPos
+	if t.cutPos > evictBefore {
+		evictBefore = t.cutPos
+	}
+
+	if evictBefore <= t.evictedBefore {
+		return
+	}
+
+	interval := rc.Len() / 2
+	if interval < minBacktrackEvictionInterval {
+		interval = minBacktrackEvictionInterval
+	}
+	if t.savesSinceEviction < interval {
+		return
+	}
+
+	t.stats.Evictions += t.discard(rc, evictBefore)
+	t.evictedBefore = evictBefore
+	t.savesSinceEviction = 0
+}
+
+// discard deletes all results before the given position and returns with the number of deleted results
+func (t *resultCacheTracker) discard(rc ResultCache, pos Pos) int {
+	n := rc.Discard(pos)
+
+	if t.lru != nil && n > 0 {
+		for e := t.lru.Front(); e != nil; {
+			next := e.Next()
+			if key := e.Value.(resultCacheKey); key.pos < pos {
+				t.lru.Remove(e)
+				delete(t.lruElements, key)
+			}
+			e = next
+		}
+	}
+
+	return n
+}
+
+// enterBacktrackPoint registers a backtrack point
+// The nested backtrack points can't be before the outermost one, so only the outermost position is used.
+// As no parser can go back before the outermost backtrack point, the input before it is discarded.
+func (t *resultCacheTracker) enterBacktrackPoint(pos Pos) {
+	t.backtrackPositions = append(t.backtrackPositions, pos)
+	if len(t.backtrackPositions) == 1 {
+		t.lowestBacktrackPos = pos
+		if t.discarder != nil {
+			t.discarder.Discard(pos)
+		}
+	}
+}
+
+func (t *resultCacheTracker) exitBacktrackPoint(rc ResultCache) {
+	n := len(t.backtrackPositions)
+	if n == 0 {
+		return
+	}
+
+	t.backtrackPositions = t.backtrackPositions[:n-1]
+	t.evictBacktracked(rc)
+}
+
+// GetResult returns with a cached result using the context's cache policy, see ResultCache.Get
+func (c *Context) GetResult(parserIndex int, pos Pos, leftRecCtx data.IntMap) (*Result, bool) {
+	result, found := c.resultCache.Get(parserIndex, pos, leftRecCtx)
+	if !found {
+		c.cacheTracker.stats.Misses++
+		return nil, false
+	}
+
+	c.cacheTracker.stats.Hits++
+	if c.cacheTracker.lru != nil {
+		c.cacheTracker.touch(resultCacheKey{parserIndex: parserIndex, pos: pos})
+	}
+
+	return result, true
+}
+
+// SaveResult saves a result in the result cache and evicts the results based on the context's cache policy
+func (c *Context) SaveResult(parserIndex int, pos Pos, result *Result) {
+	c.resultCache.Save(parserIndex, pos, result)
+	c.cacheTracker.saved(c.resultCache, resultCacheKey{parserIndex: parserIndex, pos: pos})
+}
+
+// SetCachePolicy sets the cache policy, the current cache entries are evicted if they exceed the new limits
+func (c *Context) SetCachePolicy(policy CachePolicy) {
+	c.cacheTracker.reset(policy, c.resultCache)
+}
+
+// CachePolicy returns with the cache policy
+func (c *Context) CachePolicy() CachePolicy {
+	return c.cacheTracker.policy
+}
+
+// EnterBacktrackPoint registers that the caller might call parsers at the given position (or return a result ending
+// there) until ExitBacktrackPoint is called. The lowest registered position is used by the CachePolicy.EvictBacktracked
+// policy and by the readers discarding the input automatically (see AutoDiscarder).
+//
+// A parser which continues at an earlier position than the input read by the parsers it called has to register a
+// backtrack point. Otherwise a streamed input might be already discarded, while the evicted results would only have
+// to be parsed again.
+func (c *Context) EnterBacktrackPoint(pos Pos) {
+	if c.cacheTracker.tracksBacktrackPoints() {
+		c.cacheTracker.enterBacktrackPoint(pos)
+	}
+}
+
+// ExitBacktrackPoint removes the last backtrack point registered by EnterBacktrackPoint
+func (c *Context) ExitBacktrackPoint() {
+	if c.cacheTracker.tracksBacktrackPoints() {
+		c.cacheTracker.exitBacktrackPoint(c.resultCache)
+	}
+}
+
+// Cut registers that the enclosing parsers won't try other alternatives before the given position (see combinator.Cut)
+// With the CachePolicy.EvictBacktracked policy the results before the highest cut position can be evicted even if
+// there are lower backtrack points.
+func (c *Context) Cut(pos Pos) {
+	if c.cacheTracker.policy.EvictBacktracked && pos > c.cacheTracker.cutPos {
+		c.cacheTracker.cutPos = pos
+		c.cacheTracker.evictBacktracked(c.resultCache)
+	}
+}
+
+// ResultCacheStats returns with the result cache statistics
+func (c *Context) ResultCacheStats() ResultCacheStats {
+	stats := c.cacheTracker.stats
+	stats.Entries = c.resultCache.Len()
+	stats.EstimatedBytes = int64(stats.Entries) * resultCacheEntrySize
+	return stats
+}
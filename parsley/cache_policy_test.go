@@ -0,0 +1,207 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parsley_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/parsley/parsleyfakes"
+)
+
+var _ = Describe("CachePolicy", func() {
+	var ctx *parsley.Context
+
+	BeforeEach(func() {
+		ctx = parsley.NewContext(parsley.NewFileSet(), &parsleyfakes.FakeReader{})
+	})
+
+	isCached := func(parserIndex int, pos parsley.Pos) bool {
+		_, found := ctx.ResultCache().Get(parserIndex, pos, data.EmptyIntMap)
+		return found
+	}
+
+	It("should keep all results by default", func() {
+		for i := 0; i < 1000; i++ {
+			ctx.SaveResult(1, parsley.Pos(i), &parsley.Result{})
+		}
+		Expect(ctx.CachePolicy()).To(Equal(parsley.CachePolicy{}))
+		Expect(ctx.ResultCacheStats().Entries).To(Equal(1000))
+		Expect(ctx.ResultCacheStats().Evictions).To(Equal(0))
+	})
+
+	It("should collect the statistics", func() {
+		ctx.SaveResult(1, parsley.Pos(1), &parsley.Result{})
+		ctx.SaveResult(1, parsley.Pos(2), &parsley.Result{})
+		ctx.SaveResult(1, parsley.Pos(2), &parsley.Result{})
+		_, found := ctx.GetResult(1, parsley.Pos(1), data.EmptyIntMap)
+		Expect(found).To(BeTrue())
+		_, found = ctx.GetResult(1, parsley.Pos(3), data.EmptyIntMap)
+		Expect(found).To(BeFalse())
+		ctx.Commit(parsley.Pos(2))
+
+		stats := ctx.ResultCacheStats()
+		Expect(stats.Entries).To(Equal(1))
+		Expect(stats.PeakEntries).To(Equal(2))
+		Expect(stats.Saves).To(Equal(3))
+		Expect(stats.Hits).To(Equal(1))
+		Expect(stats.Misses).To(Equal(1))
+		Expect(stats.HitRate()).To(Equal(0.5))
+		Expect(stats.EstimatedBytes).To(BeNumerically(">", 0))
+	})
+
+	It("should count the entries of a new result cache", func() {
+		rc := parsley.NewResultCache()
+		rc.Save(1, parsley.Pos(1), &parsley.Result{})
+		rc.Save(2, parsley.Pos(1), &parsley.Result{})
+		ctx.SetResultCache(rc)
+		Expect(ctx.ResultCacheStats().Entries).To(Equal(2))
+	})
+
+	Context("when the number of entries is limited", func() {
+		BeforeEach(func() {
+			ctx.SetCachePolicy(parsley.CachePolicy{MaxEntries: 2})
+		})
+
+		It("should evict the least recently used result", func() {
+			ctx.SaveResult(1, parsley.Pos(1), &parsley.Result{})
+			ctx.SaveResult(1, parsley.Pos(2), &parsley.Result{})
+			_, _ = ctx.GetResult(1, parsley.Pos(1), data.EmptyIntMap)
+			ctx.SaveResult(1, parsley.Pos(3), &parsley.Result{})
+
+			Expect(isCached(1, parsley.Pos(1))).To(BeTrue())
+			Expect(isCached(1, parsley.Pos(2))).To(BeFalse())
+			Expect(isCached(1, parsley.Pos(3))).To(BeTrue())
+			Expect(ctx.ResultCacheStats().Entries).To(Equal(2))
+			Expect(ctx.ResultCacheStats().Evictions).To(Equal(1))
+		})
+
+		It("should not count the overwritten results as new entries", func() {
+			ctx.SaveResult(1, parsley.Pos(1), &parsley.Result{})
+			ctx.SaveResult(1, parsley.Pos(1), &parsley.Result{})
+			ctx.SaveResult(1, parsley.Pos(2), &parsley.Result{})
+
+			Expect(isCached(1, parsley.Pos(1))).To(BeTrue())
+			Expect(isCached(1, parsley.Pos(2))).To(BeTrue())
+			Expect(ctx.ResultCacheStats().Evictions).To(Equal(0))
+		})
+
+		It("should forget the committed results", func() {
+			ctx.SaveResult(1, parsley.Pos(1), &parsley.Result{})
+			ctx.SaveResult(1, parsley.Pos(2), &parsley.Result{})
+			ctx.Commit(parsley.Pos(2))
+			ctx.SaveResult(1, parsley.Pos(3), &parsley.Result{})
+
+			Expect(isCached(1, parsley.Pos(2))).To(BeTrue())
+			Expect(isCached(1, parsley.Pos(3))).To(BeTrue())
+			Expect(ctx.ResultCacheStats().Entries).To(Equal(2))
+			Expect(ctx.ResultCacheStats().Evictions).To(Equal(0))
+		})
+
+		It("should evict the existing results above the new limit", func() {
+			ctx.SaveResult(1, parsley.Pos(1), &parsley.Result{})
+			ctx.SaveResult(1, parsley.Pos(2), &parsley.Result{})
+			ctx.SetCachePolicy(parsley.CachePolicy{MaxEntries: 1})
+			Expect(ctx.ResultCacheStats().Entries).To(Equal(1))
+		})
+	})
+
+	Context("when the backtracked results are evicted", func() {
+		BeforeEach(func() {
+			ctx.SetCachePolicy(parsley.CachePolicy{EvictBacktracked: true})
+		})
+
+		It("should drop the results before the lowest backtrack point", func() {
+			ctx.EnterBacktrackPoint(parsley.Pos(0))
+			for i := 0; i < 300; i++ {
+				ctx.SaveResult(1, parsley.Pos(i), &parsley.Result{})
+			}
+			ctx.ExitBacktrackPoint()
+			Expect(ctx.ResultCacheStats().Entries).To(Equal(300))
+
+			ctx.EnterBacktrackPoint(parsley.Pos(300))
+			ctx.EnterBacktrackPoint(parsley.Pos(310))
+			ctx.ExitBacktrackPoint()
+			Expect(ctx.ResultCacheStats().Entries).To(Equal(0))
+			Expect(ctx.ResultCacheStats().Evictions).To(Equal(300))
+
+			ctx.SaveResult(1, parsley.Pos(300), &parsley.Result{})
+			ctx.ExitBacktrackPoint()
+			Expect(isCached(1, parsley.Pos(300))).To(BeTrue())
+		})
+
+		It("should not evict anything while the backtrack point is active", func() {
+			ctx.EnterBacktrackPoint(parsley.Pos(0))
+			for i := 0; i < 1000; i++ {
+				ctx.EnterBacktrackPoint(parsley.Pos(i))
+				ctx.SaveResult(1, parsley.Pos(i), &parsley.Result{})
+				ctx.ExitBacktrackPoint()
+			}
+			Expect(ctx.ResultCacheStats().Entries).To(Equal(1000))
+		})
+
+		It("should drop the results before a cut even if there is a lower backtrack point", func() {
+			ctx.EnterBacktrackPoint(parsley.Pos(0))
+			for i := 0; i < 300; i++ {
+				ctx.SaveResult(1, parsley.Pos(i), &parsley.Result{})
+			}
+			ctx.Cut(parsley.Pos(200))
+			Expect(ctx.ResultCacheStats().Entries).To(Equal(100))
+			Expect(isCached(1, parsley.Pos(199))).To(BeFalse())
+			Expect(isCached(1, parsley.Pos(200))).To(BeTrue())
+			ctx.ExitBacktrackPoint()
+		})
+	})
+
+	Context("when the reader discards the input automatically", func() {
+		var dr *discardingReader
+
+		BeforeEach(func() {
+			dr = &discardingReader{FakeReader: &parsleyfakes.FakeReader{}}
+			ctx = parsley.NewContext(parsley.NewFileSet(), autoDiscardingReader{discardingReader: dr})
+		})
+
+		It("should discard the input before the outermost backtrack point", func() {
+			ctx.EnterBacktrackPoint(parsley.Pos(2))
+			ctx.EnterBacktrackPoint(parsley.Pos(3))
+			Expect(dr.discardPos).To(Equal(parsley.Pos(2)))
+			ctx.ExitBacktrackPoint()
+			ctx.ExitBacktrackPoint()
+
+			ctx.EnterBacktrackPoint(parsley.Pos(4))
+			Expect(dr.discardPos).To(Equal(parsley.Pos(4)))
+			ctx.ExitBacktrackPoint()
+		})
+
+		It("should drop the results before the lowest backtrack point", func() {
+			for i := 0; i < 300; i++ {
+				ctx.SaveResult(1, parsley.Pos(i), &parsley.Result{})
+			}
+			ctx.EnterBacktrackPoint(parsley.Pos(300))
+			ctx.ExitBacktrackPoint()
+			Expect(ctx.ResultCacheStats().Entries).To(Equal(0))
+		})
+	})
+
+	It("should ignore the cuts if the backtracked results are not evicted", func() {
+		for i := 0; i < 300; i++ {
+			ctx.SaveResult(1, parsley.Pos(i), &parsley.Result{})
+		}
+		ctx.Cut(parsley.Pos(200))
+		Expect(ctx.ResultCacheStats().Entries).To(Equal(300))
+	})
+})
+
+type autoDiscardingReader struct {
+	*discardingReader
+}
+
+func (a autoDiscardingReader) AutoDiscard() bool {
+	return true
+}
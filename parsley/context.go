@@ -6,27 +6,47 @@
 
 package parsley
 
+import "errors"
+
+// DefaultErrorLimit is the default maximum number of recovered errors returned by Parse
+const DefaultErrorLimit = 10
+
 // Context is the parsing context passed to all parsers
 type Context struct {
 	fileSet               *FileSet
 	reader                Reader
 	resultCache           ResultCache
+	cacheTracker          resultCacheTracker
 	err                   Error
+	notFoundErrs          NotFoundErrors
+	errorLimit            int
+	recoveredErrs         []Error
+	committedPos          Pos
 	callCount             int
 	keywords              map[string]struct{}
 	transformationEnabled bool
 	staticCheckEnabled    bool
 	userCtx               interface{}
+	errorFormatter        ErrorFormatter
+	tracer                Tracer
+	delimiters            []Delimiter
 }
 
 // NewContext creates a new parsing context
 func NewContext(fileSet *FileSet, reader Reader) *Context {
-	return &Context{
+	ctx := &Context{
 		fileSet:     fileSet,
 		reader:      reader,
 		resultCache: NewResultCache(),
+		errorLimit:  DefaultErrorLimit,
 		keywords:    make(map[string]struct{}, 64),
 	}
+
+	if d, ok := reader.(AutoDiscarder); ok && d.AutoDiscard() {
+		ctx.cacheTracker.discarder = d
+	}
+
+	return ctx
 }
 
 // FileSet returns with the file set
@@ -44,6 +64,31 @@ func (c *Context) ResultCache() ResultCache {
 	return c.resultCache
 }
 
+// SetResultCache sets the result cache, e.g. to reuse the results of a previous parse after an edit
+func (c *Context) SetResultCache(resultCache ResultCache) {
+	c.resultCache = resultCache
+	c.cacheTracker.reset(c.cacheTracker.policy, resultCache)
+}
+
+// Commit signals that the parsing will never go back before the given position
+// The cached results before the position are deleted and the reader is allowed to discard the input (see Discarder).
+func (c *Context) Commit(pos Pos) {
+	if pos <= c.committedPos {
+		return
+	}
+
+	c.committedPos = pos
+	c.cacheTracker.discard(c.resultCache, pos)
+	if d, ok := c.reader.(Discarder); ok {
+		d.Discard(pos)
+	}
+}
+
+// CommittedPos returns with the highest committed position
+func (c *Context) CommittedPos() Pos {
+	return c.committedPos
+}
+
 // RegisterCall registers a call
 func (c *Context) RegisterCall() {
 	c.callCount++
@@ -54,12 +99,26 @@ func (c *Context) CallCount() int {
 	return c.callCount
 }
 
+// SetTracer sets a tracer which is notified about the parser calls
+// Set it to nil to disable tracing.
+func (c *Context) SetTracer(tracer Tracer) {
+	c.tracer = tracer
+}
+
+// Tracer returns with the tracer (if any)
+func (c *Context) Tracer() Tracer {
+	return c.tracer
+}
+
 // SetError saves the error if it has the highest position for found errors
+// Not found errors are also registered using RegisterNotFoundError.
 func (c *Context) SetError(err Error) {
 	if err == nil {
 		return
 	}
 
+	c.RegisterNotFoundError(err)
+
 	if c.err == nil || err.Pos() >= c.err.Pos() {
 		c.err = err
 	}
@@ -70,6 +129,121 @@ func (c *Context) Error() Error {
 	return c.err
 }
 
+// RestoreError overrides the parse error with a previously saved value (see Error)
+// It should be used when the errors of a parser are irrelevant, e.g. when it's only used for looking ahead.
+func (c *Context) RestoreError(err Error) {
+	c.err = err
+}
+
+// RegisterNotFoundError registers the expected input's name if the given error is a not found error
+// Only the names registered at the highest position are kept.
+func (c *Context) RegisterNotFoundError(err Error) {
+	if err == nil || err.Pos() < c.notFoundErrs.Pos {
+		return
+	}
+
+	notFoundErr, ok := err.Cause().(NotFoundError)
+	if !ok && !errors.As(err, &notFoundErr) {
+		return
+	}
+
+	if err.Pos() > c.notFoundErrs.Pos {
+		c.notFoundErrs = NotFoundErrors{Pos: err.Pos(), Names: []string{string(notFoundErr)}}
+		return
+	}
+
+	if !containsString(c.notFoundErrs.Names, string(notFoundErr)) {
+		c.notFoundErrs.Names = append(c.notFoundErrs.Names, string(notFoundErr))
+	}
+}
+
+// NotFoundErrors returns with the expected inputs registered at the highest position
+func (c *Context) NotFoundErrors() NotFoundErrors {
+	return c.notFoundErrs
+}
+
+// SetNotFoundErrors overrides the registered expected inputs
+func (c *Context) SetNotFoundErrors(notFoundErrs NotFoundErrors) {
+	c.notFoundErrs = notFoundErrs
+}
+
+// OverrideNotFoundErrors replaces the expected inputs registered at the error's position since the given state
+// with the given error. It should be used when a parser replaces the not found errors of its child parsers.
+func (c *Context) OverrideNotFoundErrors(prev NotFoundErrors, err Error) {
+	if c.notFoundErrs.Pos > err.Pos() {
+		return
+	}
+
+	if prev.Pos == err.Pos() {
+		c.notFoundErrs = prev
+	} else {
+		c.notFoundErrs = NotFoundErrors{Pos: err.Pos()}
+	}
+
+	c.RegisterNotFoundError(err)
+}
+
+// ExtendNotFoundError extends a not found error with all the expected inputs registered at the same position
+// and with a short description of the input found there, if the reader implements the InputDescriber interface.
+// Any other errors are returned unchanged.
+func (c *Context) ExtendNotFoundError(err Error) Error {
+	if err == nil || !IsNotFoundError(err) {
+		return err
+	}
+
+	var cause error = err.Cause()
+	if c.notFoundErrs.Pos == err.Pos() && len(c.notFoundErrs.Names) > 0 {
+		cause = c.notFoundErrs.Error()
+	}
+
+	if describer, ok := c.reader.(InputDescriber); ok {
+		cause = foundError{cause: cause, found: describer.DescribeInput(err.Pos())}
+	}
+
+	return NewError(err.Pos(), cause)
+}
+
+// SetErrorFormatter sets the formatter used for the errors returned by Parse and Evaluate
+// By default the errors are formatted by FileSet.ErrorWithPosition.
+func (c *Context) SetErrorFormatter(errorFormatter ErrorFormatter) {
+	c.errorFormatter = errorFormatter
+}
+
+// FormatError converts the error to a human-readable error using the error formatter
+func (c *Context) FormatError(err Error) error {
+	if c.errorFormatter == nil {
+		return c.fileSet.ErrorWithPosition(err)
+	}
+	return c.errorFormatter.FormatError(c.fileSet, err)
+}
+
+// SetErrorLimit sets the maximum number of recovered errors
+// When the limit is reached the parsers don't recover from any more errors and the parsing stops.
+// If the limit is zero or negative then there is no limit.
+func (c *Context) SetErrorLimit(limit int) {
+	c.errorLimit = limit
+}
+
+// ErrorLimit returns with the maximum number of recovered errors
+func (c *Context) ErrorLimit() int {
+	return c.errorLimit
+}
+
+// RegisterRecoveredError registers an error which the parser recovered from (see combinator.Recover)
+// It returns false if the error limit was already reached, then the parser should not recover from the error.
+func (c *Context) RegisterRecoveredError(err Error) bool {
+	if c.ErrorLimitReached() {
+		return false
+	}
+	c.recoveredErrs = append(c.recoveredErrs, err)
+	return true
+}
+
+// ErrorLimitReached returns true if the number of the recovered errors reached the error limit
+func (c *Context) ErrorLimitReached() bool {
+	return c.errorLimit > 0 && len(c.recoveredErrs) >= c.errorLimit
+}
+
 // RegisterKeywords registers one or more keywords
 func (c *Context) RegisterKeywords(keywords ...string) {
 	for _, keyword := range keywords {
@@ -0,0 +1,230 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parsley_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/parsley/parsleyfakes"
+)
+
+var _ = Describe("Context", func() {
+	var (
+		r   *parsleyfakes.FakeReader
+		ctx *parsley.Context
+	)
+
+	BeforeEach(func() {
+		r = &parsleyfakes.FakeReader{}
+		ctx = parsley.NewContext(parsley.NewFileSet(), r)
+	})
+
+	Describe("Commit()", func() {
+		It("should delete the cached results before the position", func() {
+			ctx.ResultCache().Save(1, parsley.Pos(1), &parsley.Result{})
+			ctx.ResultCache().Save(1, parsley.Pos(2), &parsley.Result{})
+			ctx.Commit(parsley.Pos(2))
+			Expect(ctx.CommittedPos()).To(Equal(parsley.Pos(2)))
+			_, found := ctx.ResultCache().Get(1, parsley.Pos(1), data.EmptyIntMap)
+			Expect(found).To(BeFalse())
+			_, found = ctx.ResultCache().Get(1, parsley.Pos(2), data.EmptyIntMap)
+			Expect(found).To(BeTrue())
+		})
+
+		It("should not go back to a lower position", func() {
+			ctx.Commit(parsley.Pos(2))
+			ctx.Commit(parsley.Pos(1))
+			Expect(ctx.CommittedPos()).To(Equal(parsley.Pos(2)))
+		})
+
+		Context("when the reader can discard the input", func() {
+			var dr *discardingReader
+
+			BeforeEach(func() {
+				dr = &discardingReader{FakeReader: r}
+				ctx = parsley.NewContext(parsley.NewFileSet(), dr)
+			})
+
+			It("should call the reader", func() {
+				ctx.Commit(parsley.Pos(2))
+				Expect(dr.discardPos).To(Equal(parsley.Pos(2)))
+			})
+		})
+	})
+
+	Describe("SetError()", func() {
+		It("should keep the error with the highest position", func() {
+			ctx.SetError(parsley.NewErrorf(parsley.Pos(2), "err1"))
+			ctx.SetError(parsley.NewErrorf(parsley.Pos(1), "err2"))
+			Expect(ctx.Error()).To(MatchError("err1"))
+		})
+
+		It("should register the not found errors", func() {
+			ctx.SetError(parsley.NewError(parsley.Pos(1), parsley.NotFoundError("x")))
+			Expect(ctx.NotFoundErrors()).To(Equal(parsley.NotFoundErrors{Pos: parsley.Pos(1), Names: []string{"x"}}))
+		})
+	})
+
+	Describe("RegisterNotFoundError()", func() {
+		It("should collect the names at the highest position", func() {
+			ctx.RegisterNotFoundError(parsley.NewError(parsley.Pos(1), parsley.NotFoundError("a")))
+			ctx.RegisterNotFoundError(parsley.NewError(parsley.Pos(2), parsley.NotFoundError("b")))
+			ctx.RegisterNotFoundError(parsley.NewError(parsley.Pos(2), parsley.NotFoundError("c")))
+			ctx.RegisterNotFoundError(parsley.NewError(parsley.Pos(2), parsley.NotFoundError("b")))
+			ctx.RegisterNotFoundError(parsley.NewError(parsley.Pos(1), parsley.NotFoundError("d")))
+			Expect(ctx.NotFoundErrors()).To(Equal(parsley.NotFoundErrors{Pos: parsley.Pos(2), Names: []string{"b", "c"}}))
+		})
+
+		It("should ignore other errors", func() {
+			ctx.RegisterNotFoundError(parsley.NewErrorf(parsley.Pos(1), "some error"))
+			ctx.RegisterNotFoundError(nil)
+			Expect(ctx.NotFoundErrors()).To(Equal(parsley.NotFoundErrors{}))
+		})
+	})
+
+	Describe("OverrideNotFoundErrors()", func() {
+		var prev parsley.NotFoundErrors
+
+		BeforeEach(func() {
+			ctx.RegisterNotFoundError(parsley.NewError(parsley.Pos(2), parsley.NotFoundError("a")))
+			prev = ctx.NotFoundErrors()
+			ctx.RegisterNotFoundError(parsley.NewError(parsley.Pos(2), parsley.NotFoundError("b")))
+			ctx.RegisterNotFoundError(parsley.NewError(parsley.Pos(2), parsley.NotFoundError("c")))
+		})
+
+		It("should replace the names registered since the previous state", func() {
+			ctx.OverrideNotFoundErrors(prev, parsley.NewError(parsley.Pos(2), parsley.NotFoundError("d")))
+			Expect(ctx.NotFoundErrors()).To(Equal(parsley.NotFoundErrors{Pos: parsley.Pos(2), Names: []string{"a", "d"}}))
+		})
+
+		It("should not change the names if there is a higher position", func() {
+			ctx.OverrideNotFoundErrors(prev, parsley.NewError(parsley.Pos(1), parsley.NotFoundError("d")))
+			Expect(ctx.NotFoundErrors()).To(Equal(parsley.NotFoundErrors{Pos: parsley.Pos(2), Names: []string{"a", "b", "c"}}))
+		})
+
+		Context("when the previous state has a lower position", func() {
+			BeforeEach(func() {
+				prev = parsley.NotFoundErrors{Pos: parsley.Pos(1), Names: []string{"x"}}
+			})
+
+			It("should only keep the new error", func() {
+				ctx.OverrideNotFoundErrors(prev, parsley.NewError(parsley.Pos(2), parsley.NotFoundError("d")))
+				Expect(ctx.NotFoundErrors()).To(Equal(parsley.NotFoundErrors{Pos: parsley.Pos(2), Names: []string{"d"}}))
+			})
+		})
+	})
+
+	Describe("ExtendNotFoundError()", func() {
+		BeforeEach(func() {
+			ctx.RegisterNotFoundError(parsley.NewError(parsley.Pos(2), parsley.NotFoundError("a")))
+			ctx.RegisterNotFoundError(parsley.NewError(parsley.Pos(2), parsley.NotFoundError("b")))
+			ctx.RegisterNotFoundError(parsley.NewError(parsley.Pos(2), parsley.NotFoundError("c")))
+		})
+
+		It("should list all the expected inputs at the same position", func() {
+			err := ctx.ExtendNotFoundError(parsley.NewError(parsley.Pos(2), parsley.NotFoundError("a")))
+			Expect(err).To(MatchError("was expecting a, b or c"))
+			Expect(err.Pos()).To(Equal(parsley.Pos(2)))
+			Expect(parsley.IsNotFoundError(err)).To(BeTrue())
+		})
+
+		It("should not change a not found error at a different position", func() {
+			err := ctx.ExtendNotFoundError(parsley.NewError(parsley.Pos(1), parsley.NotFoundError("x")))
+			Expect(err).To(MatchError("was expecting x"))
+		})
+
+		It("should not change other errors", func() {
+			origErr := parsley.NewError(parsley.Pos(2), errors.New("some error"))
+			Expect(ctx.ExtendNotFoundError(origErr)).To(Equal(origErr))
+		})
+
+		Context("when the reader can describe the input", func() {
+			BeforeEach(func() {
+				ctx = parsley.NewContext(parsley.NewFileSet(), describingReader{FakeReader: r})
+				ctx.RegisterNotFoundError(parsley.NewError(parsley.Pos(2), parsley.NotFoundError("a")))
+				ctx.RegisterNotFoundError(parsley.NewError(parsley.Pos(2), parsley.NotFoundError("b")))
+			})
+
+			It("should add the description of the found input", func() {
+				err := ctx.ExtendNotFoundError(parsley.NewError(parsley.Pos(2), parsley.NotFoundError("a")))
+				Expect(err).To(MatchError(`was expecting a or b, found "x"`))
+				Expect(parsley.IsNotFoundError(err)).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("FormatError()", func() {
+		var origErr parsley.Error
+
+		BeforeEach(func() {
+			f := &parsleyfakes.FakeFile{}
+			f.LenReturns(10)
+			position := &parsleyfakes.FakePosition{}
+			position.StringReturns("testpos")
+			f.PositionReturns(position)
+			ctx = parsley.NewContext(parsley.NewFileSet(f), r)
+			origErr = parsley.NewError(parsley.Pos(2), errors.New("some error"))
+		})
+
+		It("should add the position by default", func() {
+			Expect(ctx.FormatError(origErr)).To(MatchError("some error at testpos"))
+		})
+
+		Context("when an error formatter is set", func() {
+			BeforeEach(func() {
+				ctx.SetErrorFormatter(parsley.ErrorFormatterFunc(func(fs *parsley.FileSet, err parsley.Error) error {
+					Expect(fs).To(BeIdenticalTo(ctx.FileSet()))
+					return fmt.Errorf("formatted: %s", err.Error())
+				}))
+			})
+
+			It("should use the error formatter", func() {
+				Expect(ctx.FormatError(origErr)).To(MatchError("formatted: some error"))
+			})
+		})
+	})
+
+	Describe("OpenDelimiter()", func() {
+		It("should keep the open delimiters until they are closed", func() {
+			d1 := parsley.Delimiter{Name: "'['", Pos: parsley.Pos(1)}
+			d2 := parsley.Delimiter{Name: "'{'", Pos: parsley.Pos(2)}
+			ctx.OpenDelimiter(d1)
+			ctx.OpenDelimiter(d2)
+			Expect(ctx.OpenDelimiters()).To(Equal([]parsley.Delimiter{d1, d2}))
+
+			ctx.CloseDelimiter()
+			Expect(ctx.OpenDelimiters()).To(Equal([]parsley.Delimiter{d1}))
+		})
+
+		It("should panic if there are no open delimiters", func() {
+			Expect(func() { ctx.CloseDelimiter() }).To(Panic())
+		})
+	})
+})
+
+type discardingReader struct {
+	*parsleyfakes.FakeReader
+	discardPos parsley.Pos
+}
+
+func (d *discardingReader) Discard(pos parsley.Pos) {
+	d.discardPos = pos
+}
+
+type describingReader struct {
+	*parsleyfakes.FakeReader
+}
+
+func (d describingReader) DescribeInput(parsley.Pos) string {
+	return `"x"`
+}
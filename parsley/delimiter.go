@@ -0,0 +1,35 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parsley
+
+// Delimiter is an opening delimiter (e.g. a bracket) which is waiting for its closing pair
+type Delimiter struct {
+	// Name is the description of the opening delimiter used in the error messages, e.g. '['
+	Name string
+	// Pos is the position of the opening delimiter
+	Pos Pos
+	// Close is the parser matching the closing pair
+	Close Parser
+}
+
+// OpenDelimiter registers an opening delimiter until the next CloseDelimiter call (see combinator.Between)
+func (c *Context) OpenDelimiter(d Delimiter) {
+	c.delimiters = append(c.delimiters, d)
+}
+
+// CloseDelimiter removes the last opened delimiter
+func (c *Context) CloseDelimiter() {
+	if len(c.delimiters) == 0 {
+		panic("CloseDelimiter() was called without an open delimiter")
+	}
+	c.delimiters = c.delimiters[:len(c.delimiters)-1]
+}
+
+// OpenDelimiters returns with the delimiters enclosing the current parser, the innermost is the last one
+func (c *Context) OpenDelimiters() []Delimiter {
+	return c.delimiters
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parsley
+
+import (
+	"github.com/conflowio/parsley/data"
+)
+
+const (
+	densePageBits = 8
+	densePageSize = 1 << densePageBits
+	densePageMask = densePageSize - 1
+)
+
+// densePage contains the results of a parser for consecutive positions
+type densePage struct {
+	results [densePageSize]*Result
+	len     int
+}
+
+// denseResultCache stores the results in paged arrays indexed by the parser index and the position
+type denseResultCache struct {
+	parsers [][]*densePage
+	len     int
+}
+
+// NewDenseResultCache creates a new result cache which stores the results in paged arrays
+// The lookups don't need any hashing and the pages are only allocated for the input ranges where a parser saved a
+// result. It uses more memory than the default implementation if there are only a few results per parser in every
+// page.
+func NewDenseResultCache() ResultCache {
+	return &denseResultCache{}
+}
+
+// Save registers a parser result for a certain position
+func (rc *denseResultCache) Save(parserIndex int, pos Pos, result *Result) {
+	if parserIndex >= len(rc.parsers) {
+		rc.parsers = append(rc.parsers, make([][]*densePage, parserIndex+1-len(rc.parsers))...)
+	}
+
+	pages := rc.parsers[parserIndex]
+	pageIndex := int(pos) >> densePageBits
+	if pageIndex >= len(pages) {
+		pages = append(pages, make([]*densePage, pageIndex+1-len(pages))...)
+		rc.parsers[parserIndex] = pages
+	}
+
+	page := pages[pageIndex]
+	if page == nil {
+		page = &densePage{}
+		pages[pageIndex] = page
+	}
+
+	i := int(pos) & densePageMask
+	if page.results[i] == nil {
+		page.len++
+		rc.len++
+	}
+	page.results[i] = result
+}
+
+// Get return with a previously saved result
+func (rc *denseResultCache) Get(parserIndex int, pos Pos, leftRecCtx data.IntMap) (*Result, bool) {
+	page := rc.page(parserIndex, pos)
+	if page == nil {
+		return nil, false
+	}
+
+	result := page.results[int(pos)&densePageMask]
+	if result == nil || !result.usableIn(leftRecCtx) {
+		return nil, false
+	}
+
+	return result, true
+}
+
+func (rc *denseResultCache) page(parserIndex int, pos Pos) *densePage {
+	if parserIndex < 0 || parserIndex >= len(rc.parsers) {
+		return nil
+	}
+
+	pages := rc.parsers[parserIndex]
+	pageIndex := int(pos) >> densePageBits
+	if pageIndex < 0 || pageIndex >= len(pages) {
+		return nil
+	}
+
+	return pages[pageIndex]
+}
+
+// Delete deletes a result and returns true if it existed
+func (rc *denseResultCache) Delete(parserIndex int, pos Pos) bool {
+	page := rc.page(parserIndex, pos)
+	if page == nil {
+		return false
+	}
+
+	i := int(pos) & densePageMask
+	if page.results[i] == nil {
+		return false
+	}
+
+	page.results[i] = nil
+	page.len--
+	rc.len--
+	if page.len == 0 {
+		rc.parsers[parserIndex][int(pos)>>densePageBits] = nil
+	}
+
+	return true
+}
+
+// Discard deletes all results saved before the given position
+// The pages before the position are released.
+func (rc *denseResultCache) Discard(pos Pos) int {
+	var n int
+	lastPage := int(pos) >> densePageBits
+	for _, pages := range rc.parsers {
+		for pageIndex := 0; pageIndex < len(pages) && pageIndex <= lastPage; pageIndex++ {
+			page := pages[pageIndex]
+			if page == nil {
+				continue
+			}
+
+			if pageIndex < lastPage {
+				n += page.len
+				pages[pageIndex] = nil
+				continue
+			}
+
+			for i := 0; i < int(pos)&densePageMask; i++ {
+				if page.results[i] != nil {
+					page.results[i] = nil
+					page.len--
+					n++
+				}
+			}
+			if page.len == 0 {
+				pages[pageIndex] = nil
+			}
+		}
+	}
+	rc.len -= n
+	return n
+}
+
+// Len returns with the number of saved results
+func (rc *denseResultCache) Len() int {
+	return rc.len
+}
+
+// Each calls the given function for all saved results in the order of the parser indices and positions
+func (rc *denseResultCache) Each(f func(parserIndex int, pos Pos, result *Result)) {
+	for parserIndex, pages := range rc.parsers {
+		for pageIndex, page := range pages {
+			if page == nil {
+				continue
+			}
+			for i, result := range page.results {
+				if result != nil {
+					f(parserIndex, Pos(pageIndex<<densePageBits|i), result)
+				}
+			}
+		}
+	}
+}
+
+// ApplyEdit creates a new result cache which can be used after the input was edited
+func (rc *denseResultCache) ApplyEdit(e Edit) ResultCache {
+	return applyEdit(rc, e, NewDenseResultCache())
+}
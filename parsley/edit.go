@@ -0,0 +1,95 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parsley
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Edit describes a change in the input: the input between Pos and OldEnd was replaced and now ends at NewEnd
+type Edit struct {
+	Pos    Pos
+	OldEnd Pos
+	NewEnd Pos
+}
+
+// Delta returns with the change in the input length
+func (e Edit) Delta() int {
+	return int(e.NewEnd) - int(e.OldEnd)
+}
+
+// PosShifter is a node which can be moved to a different position
+// ShiftPos should change all the positions using the given shifter and return with the shifted node. Nodes with
+// pointer receivers should update themselves and return with the same node.
+//counterfeiter:generate . PosShifter
+type PosShifter interface {
+	ShiftPos(s *Shifter) Node
+}
+
+// Shifter moves nodes by a given delta, making sure every node is only moved once even if it's shared
+type Shifter struct {
+	delta int
+	nodes map[Node]struct{}
+	slots map[*Node]struct{}
+}
+
+// NewShifter creates a new shifter
+func NewShifter(delta int) *Shifter {
+	return &Shifter{
+		delta: delta,
+		nodes: map[Node]struct{}{},
+		slots: map[*Node]struct{}{},
+	}
+}
+
+// Pos returns with the shifted position
+func (s *Shifter) Pos(pos Pos) Pos {
+	return Pos(int(pos) + s.delta)
+}
+
+// Error returns with the shifted error
+func (s *Shifter) Error(err Error) Error {
+	if err == nil {
+		return nil
+	}
+	return NewError(s.Pos(err.Pos()), err.Cause())
+}
+
+// Node shifts the given node and returns with the shifted node
+// The node must implement the PosShifter interface.
+func (s *Shifter) Node(node Node) Node {
+	if node == nil {
+		return nil
+	}
+
+	ps, ok := node.(PosShifter)
+	if !ok {
+		panic(fmt.Sprintf("%T can not be shifted, you need to implement the parsley.PosShifter interface", node))
+	}
+
+	if reflect.ValueOf(node).Kind() != reflect.Ptr {
+		return ps.ShiftPos(s)
+	}
+
+	if _, shifted := s.nodes[node]; shifted {
+		return node
+	}
+	s.nodes[node] = struct{}{}
+	return ps.ShiftPos(s)
+}
+
+// Nodes shifts all the nodes in the given slice in place
+func (s *Shifter) Nodes(nodes []Node) {
+	for i := range nodes {
+		if _, shifted := s.slots[&nodes[i]]; shifted {
+			continue
+		}
+		s.slots[&nodes[i]] = struct{}{}
+		nodes[i] = s.Node(nodes[i])
+	}
+}
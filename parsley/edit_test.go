@@ -0,0 +1,105 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parsley_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/parsley/parsleyfakes"
+)
+
+type shiftableNode struct {
+	parsleyfakes.FakeNode
+	pos      parsley.Pos
+	children []parsley.Node
+}
+
+func (n *shiftableNode) Pos() parsley.Pos {
+	return n.pos
+}
+
+func (n *shiftableNode) ShiftPos(s *parsley.Shifter) parsley.Node {
+	n.pos = s.Pos(n.pos)
+	s.Nodes(n.children)
+	return n
+}
+
+type shiftableValueNode parsley.Pos
+
+func (n shiftableValueNode) Token() string          { return "VALUE" }
+func (n shiftableValueNode) Schema() interface{}    { return nil }
+func (n shiftableValueNode) Pos() parsley.Pos       { return parsley.Pos(n) }
+func (n shiftableValueNode) ReaderPos() parsley.Pos { return parsley.Pos(n) }
+
+func (n shiftableValueNode) ShiftPos(s *parsley.Shifter) parsley.Node {
+	return shiftableValueNode(s.Pos(parsley.Pos(n)))
+}
+
+var _ = Describe("Edit", func() {
+	It("Delta() should return with the change in the input length", func() {
+		Expect(parsley.Edit{Pos: 2, OldEnd: 5, NewEnd: 3}.Delta()).To(Equal(-2))
+		Expect(parsley.Edit{Pos: 2, OldEnd: 2, NewEnd: 4}.Delta()).To(Equal(2))
+	})
+})
+
+var _ = Describe("Shifter", func() {
+	var s *parsley.Shifter
+
+	BeforeEach(func() {
+		s = parsley.NewShifter(3)
+	})
+
+	It("Pos() should return with the shifted position", func() {
+		Expect(s.Pos(parsley.Pos(2))).To(Equal(parsley.Pos(5)))
+	})
+
+	It("Error() should return with the shifted error", func() {
+		err := s.Error(parsley.NewErrorf(parsley.Pos(2), "some error"))
+		Expect(err.Pos()).To(Equal(parsley.Pos(5)))
+		Expect(err).To(MatchError("some error"))
+	})
+
+	It("Error() should return nil for a nil error", func() {
+		Expect(s.Error(nil)).To(BeNil())
+	})
+
+	It("Node() should return nil for a nil node", func() {
+		Expect(s.Node(nil)).To(BeNil())
+	})
+
+	It("Node() should panic if the node can not be shifted", func() {
+		Expect(func() { s.Node(&parsleyfakes.FakeNode{}) }).To(Panic())
+	})
+
+	It("Node() should shift a node only once", func() {
+		n := &shiftableNode{pos: 2}
+		Expect(s.Node(n)).To(BeIdenticalTo(n))
+		Expect(s.Node(n)).To(BeIdenticalTo(n))
+		Expect(n.Pos()).To(Equal(parsley.Pos(5)))
+	})
+
+	It("Node() should return with the new node for value nodes", func() {
+		res := s.Node(shiftableValueNode(1))
+		Expect(res).To(Equal(shiftableValueNode(4)))
+	})
+
+	It("Nodes() should shift every slot only once", func() {
+		shared := &shiftableNode{pos: 2}
+		children := []parsley.Node{shiftableValueNode(1), shared}
+		n1 := &shiftableNode{pos: 1, children: children}
+		n2 := &shiftableNode{pos: 1, children: children}
+
+		s.Nodes([]parsley.Node{n1, n2})
+
+		Expect(n1.Pos()).To(Equal(parsley.Pos(4)))
+		Expect(n2.Pos()).To(Equal(parsley.Pos(4)))
+		Expect(shared.Pos()).To(Equal(parsley.Pos(5)))
+		Expect(children[0]).To(Equal(shiftableValueNode(4)))
+	})
+})
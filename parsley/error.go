@@ -9,6 +9,7 @@ package parsley
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // Error is an error with a position
@@ -68,6 +69,45 @@ func (e err) Unwrap() error {
 	return e.cause
 }
 
+type cutError struct {
+	err Error
+}
+
+// NewCutError marks the error as a hard error which stops the enclosing parsers from trying other alternatives
+// It's returned by the sequences when a parser fails after a cut (see combinator.Cut).
+func NewCutError(err Error) Error {
+	if err == nil || IsCutError(err) {
+		return err
+	}
+	return cutError{err: err}
+}
+
+// Error returns with the error message of the wrapped error
+func (c cutError) Error() string {
+	return c.err.Error()
+}
+
+// Pos returns with the error's position
+func (c cutError) Pos() Pos {
+	return c.err.Pos()
+}
+
+// Cause returns with the cause of the wrapped error
+func (c cutError) Cause() error {
+	return c.err.Cause()
+}
+
+// Unwrap returns the wrapped error
+func (c cutError) Unwrap() error {
+	return c.err
+}
+
+// IsCutError returns true if the error was created by NewCutError
+func IsCutError(err error) bool {
+	var c cutError
+	return errors.As(err, &c)
+}
+
 type whitespaceError string
 
 func (w whitespaceError) Error() string {
@@ -96,3 +136,64 @@ var emptyNotFoundError NotFoundError
 func IsNotFoundError(err error) bool {
 	return errors.As(err, &emptyNotFoundError)
 }
+
+// NotFoundErrors contains the names of all the expected inputs at a given position
+type NotFoundErrors struct {
+	Pos   Pos
+	Names []string
+}
+
+// Error returns with a not found error listing all the expected inputs
+func (n NotFoundErrors) Error() NotFoundError {
+	names := make([]string, 0, len(n.Names))
+	for _, name := range n.Names {
+		if !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 1 {
+		return NotFoundError(names[0])
+	}
+
+	return NotFoundError(strings.Join(names[0:len(names)-1], ", ") + " or " + names[len(names)-1])
+}
+
+type foundError struct {
+	cause error
+	found string
+}
+
+func (f foundError) Error() string {
+	return fmt.Sprintf("%s, found %s", f.cause.Error(), f.found)
+}
+
+func (f foundError) Unwrap() error {
+	return f.cause
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorList contains multiple errors, e.g. all the recovered errors during parsing
+type ErrorList []error
+
+// Error returns with all the error messages, separated by new lines
+func (e ErrorList) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the wrapped errors
+func (e ErrorList) Unwrap() []error {
+	return e
+}
@@ -0,0 +1,20 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parsley
+
+// ErrorFormatter converts an error to a human-readable error, e.g. by adding the position or a source snippet
+type ErrorFormatter interface {
+	FormatError(fs *FileSet, err Error) error
+}
+
+// ErrorFormatterFunc is a function which implements the ErrorFormatter interface
+type ErrorFormatterFunc func(fs *FileSet, err Error) error
+
+// FormatError calls the function
+func (f ErrorFormatterFunc) FormatError(fs *FileSet, err Error) error {
+	return f(fs, err)
+}
@@ -111,3 +111,31 @@ var _ = Describe("NewErrorf", func() {
 		})
 	})
 })
+
+var _ = Describe("NewCutError", func() {
+	It("should keep the original error", func() {
+		orig := parsley.NewError(parsley.Pos(2), parsley.NotFoundError("x"))
+		err := parsley.NewCutError(orig)
+		Expect(err.Error()).To(Equal("was expecting x"))
+		Expect(err.Pos()).To(Equal(parsley.Pos(2)))
+		Expect(err.Cause()).To(Equal(parsley.NotFoundError("x")))
+		Expect(parsley.IsNotFoundError(err)).To(BeTrue())
+		Expect(errors.Is(err, orig)).To(BeTrue())
+	})
+
+	It("should mark the error as a cut error", func() {
+		err := parsley.NewErrorf(parsley.Pos(1), "some error")
+		Expect(parsley.IsCutError(err)).To(BeFalse())
+		Expect(parsley.IsCutError(parsley.NewCutError(err))).To(BeTrue())
+		Expect(parsley.IsCutError(nil)).To(BeFalse())
+	})
+
+	It("should not wrap a cut error again", func() {
+		err := parsley.NewCutError(parsley.NewErrorf(parsley.Pos(1), "some error"))
+		Expect(parsley.NewCutError(err)).To(Equal(err))
+	})
+
+	It("should return nil for nil", func() {
+		Expect(parsley.NewCutError(nil)).To(BeNil())
+	})
+})
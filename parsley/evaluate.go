@@ -35,7 +35,7 @@ func Evaluate(ctx *Context, p Parser) (interface{}, error) {
 
 	value, evalErr := EvaluateNode(ctx.UserContext(), node)
 	if evalErr != nil {
-		return nil, ctx.FileSet().ErrorWithPosition(evalErr)
+		return nil, ctx.FormatError(evalErr)
 	}
 
 	return value, nil
@@ -7,6 +7,8 @@
 package parsley_test
 
 import (
+	"errors"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -108,5 +110,16 @@ var _ = Describe("Evaluate", func() {
 			Expect(val).To(BeNil())
 			Expect(err).To(MatchError("some error at testpos"))
 		})
+
+		Context("if an error formatter is set", func() {
+			BeforeEach(func() {
+				ctx.SetErrorFormatter(parsley.ErrorFormatterFunc(func(fs *parsley.FileSet, err parsley.Error) error {
+					return errors.New("formatted " + err.Error())
+				}))
+			})
+			It("should use the error formatter", func() {
+				Expect(err).To(MatchError("formatted some error"))
+			})
+		})
 	})
 })
@@ -14,3 +14,26 @@ type File interface {
 	Len() int
 	SetOffset(int)
 }
+
+// EditableFile is a file which content can be changed
+// Edit replaces the content between the start and end offsets with the given data and returns with the length of
+// the inserted data.
+//counterfeiter:generate . EditableFile
+type EditableFile interface {
+	File
+	Edit(start, end int, data []byte) int
+}
+
+// NamedFile is a file which has a name
+type NamedFile interface {
+	File
+	Filename() string
+}
+
+// LineFile is a file which can translate between byte offsets and line and column numbers
+// The line and column numbers start from 1.
+type LineFile interface {
+	NamedFile
+	LineColumn(offset int) (line int, column int, ok bool)
+	Offset(line int, column int) (offset int, ok bool)
+}
@@ -54,11 +54,103 @@ func (fs *FileSet) Position(pos Pos) Position {
 	return fs.files[i].Position(int(pos) - fs.offset[i])
 }
 
+// File returns with the file containing the given global position and the offset in the file
+// It returns with a nil file if the position is invalid.
+func (fs *FileSet) File(pos Pos) (File, int) {
+	if pos == 0 || int(pos) >= fs.pos {
+		return nil, 0
+	}
+
+	i := sort.Search(len(fs.offset), func(i int) bool { return fs.offset[i] > int(pos) }) - 1
+	return fs.files[i], int(pos) - fs.offset[i]
+}
+
+// Range returns with the range between the start and end positions
+// It returns false if the positions are invalid or they are in different files.
+func (fs *FileSet) Range(start Pos, end Pos) (Range, bool) {
+	f, startOffset := fs.File(start)
+	if f == nil || end < start {
+		return Range{}, false
+	}
+	endOffset := startOffset + int(end-start)
+	if endOffset > f.Len() {
+		return Range{}, false
+	}
+
+	r := Range{
+		Start: Location{Pos: start, Offset: startOffset},
+		End:   Location{Pos: end, Offset: endOffset},
+	}
+
+	if nf, ok := f.(NamedFile); ok {
+		r.Filename = nf.Filename()
+	}
+
+	if lf, ok := f.(LineFile); ok {
+		r.Start.Line, r.Start.Column, _ = lf.LineColumn(startOffset)
+		r.End.Line, r.End.Column, _ = lf.LineColumn(endOffset)
+	}
+
+	return r, true
+}
+
+// NodeRange returns with the range of the given node
+func (fs *FileSet) NodeRange(node Node) (Range, bool) {
+	return fs.Range(node.Pos(), node.ReaderPos())
+}
+
+// LookupPos returns with the global position for the given file name, line and column
+// It returns false if the file doesn't exist, it doesn't implement LineFile or the line and column are invalid.
+func (fs *FileSet) LookupPos(filename string, line int, column int) (Pos, bool) {
+	for i, f := range fs.files {
+		lf, ok := f.(LineFile)
+		if !ok || lf.Filename() != filename {
+			continue
+		}
+		offset, ok := lf.Offset(line, column)
+		if !ok {
+			return NilPos, false
+		}
+		return Pos(fs.offset[i] + offset), true
+	}
+	return NilPos, false
+}
+
 // ErrorWithPosition creates an error with a human-readable position
 func (fs *FileSet) ErrorWithPosition(err Error) error {
 	pos := fs.Position(err.Pos())
 	if pos == NilPosition {
 		return err
 	}
-	return fmt.Errorf("%s at %s", err.Error(), pos.String())
+	return fmt.Errorf("%w at %s", err, pos.String())
+}
+
+// Edit replaces the input between the start and end positions with the given data
+// The positions have to be in the same file which must implement the EditableFile interface. The files after the
+// edited file are moved accordingly.
+func (fs *FileSet) Edit(start Pos, end Pos, data []byte) (Edit, error) {
+	if start == 0 || end < start || int(end) >= fs.pos {
+		return Edit{}, fmt.Errorf("invalid edit range: %d..%d", start, end)
+	}
+
+	i := sort.Search(len(fs.offset), func(i int) bool { return fs.offset[i] > int(start) }) - 1
+	if int(end) > fs.offset[i]+fs.files[i].Len() {
+		return Edit{}, fmt.Errorf("invalid edit range: %d..%d", start, end)
+	}
+
+	f, ok := fs.files[i].(EditableFile)
+	if !ok {
+		return Edit{}, fmt.Errorf("%T is not editable", fs.files[i])
+	}
+
+	n := f.Edit(int(start)-fs.offset[i], int(end)-fs.offset[i], data)
+	e := Edit{Pos: start, OldEnd: end, NewEnd: start + Pos(n)}
+
+	for j := i + 1; j < len(fs.files); j++ {
+		fs.offset[j] += e.Delta()
+		fs.files[j].SetOffset(fs.offset[j])
+	}
+	fs.pos += e.Delta()
+
+	return e, nil
 }
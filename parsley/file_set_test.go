@@ -7,7 +7,11 @@
 package parsley_test
 
 import (
+	"errors"
+	"fmt"
+
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
 	"github.com/conflowio/parsley/parsley"
@@ -141,6 +145,127 @@ var _ = Describe("File set", func() {
 		})
 	})
 
+	Describe("File()", func() {
+		var f1, f2 *parsleyfakes.FakeFile
+
+		BeforeEach(func() {
+			f1 = &parsleyfakes.FakeFile{}
+			f2 = &parsleyfakes.FakeFile{}
+			f1.LenReturns(10)
+			f2.LenReturns(20)
+			files = []parsley.File{f1, f2}
+		})
+
+		It("returns with the file and the offset in the file", func() {
+			f, offset := fs.File(parsley.Pos(5))
+			Expect(f).To(BeIdenticalTo(f1))
+			Expect(offset).To(Equal(4))
+
+			f, offset = fs.File(parsley.Pos(12))
+			Expect(f).To(BeIdenticalTo(f2))
+			Expect(offset).To(Equal(0))
+		})
+
+		It("returns with a nil file for an invalid position", func() {
+			f, _ := fs.File(parsley.NilPos)
+			Expect(f).To(BeNil())
+
+			f, _ = fs.File(parsley.Pos(33))
+			Expect(f).To(BeNil())
+		})
+	})
+
+	Describe("Range()", func() {
+		var f1, f2 *parsleyfakes.FakeFile
+
+		BeforeEach(func() {
+			f1 = &parsleyfakes.FakeFile{}
+			f1.LenReturns(10)
+			f2 = &parsleyfakes.FakeFile{}
+			f2.LenReturns(20)
+			files = []parsley.File{lineFile{FakeFile: f1, filename: "file1"}, f2}
+		})
+
+		It("returns with the range", func() {
+			r, ok := fs.Range(parsley.Pos(2), parsley.Pos(5))
+			Expect(ok).To(BeTrue())
+			Expect(r).To(Equal(parsley.Range{
+				Filename: "file1",
+				Start:    parsley.Location{Pos: 2, Offset: 1, Line: 1, Column: 2},
+				End:      parsley.Location{Pos: 5, Offset: 4, Line: 1, Column: 5},
+			}))
+		})
+
+		It("returns with the range for the end of the file", func() {
+			r, ok := fs.Range(parsley.Pos(11), parsley.Pos(11))
+			Expect(ok).To(BeTrue())
+			Expect(r.End).To(Equal(parsley.Location{Pos: 11, Offset: 10, Line: 1, Column: 11}))
+		})
+
+		It("returns with the byte offsets only if the file doesn't have lines", func() {
+			r, ok := fs.Range(parsley.Pos(13), parsley.Pos(15))
+			Expect(ok).To(BeTrue())
+			Expect(r).To(Equal(parsley.Range{
+				Start: parsley.Location{Pos: 13, Offset: 1},
+				End:   parsley.Location{Pos: 15, Offset: 3},
+			}))
+		})
+
+		DescribeTable("returns false for invalid ranges",
+			func(start, end int) {
+				_, ok := fs.Range(parsley.Pos(start), parsley.Pos(end))
+				Expect(ok).To(BeFalse())
+			},
+			Entry("nil position", 0, 1),
+			Entry("end before start", 5, 4),
+			Entry("spanning multiple files", 5, 13),
+			Entry("after the last file", 40, 41),
+		)
+	})
+
+	Describe("NodeRange()", func() {
+		BeforeEach(func() {
+			f := &parsleyfakes.FakeFile{}
+			f.LenReturns(10)
+			files = []parsley.File{lineFile{FakeFile: f, filename: "file1"}}
+		})
+
+		It("returns with the range of the node", func() {
+			node := &parsleyfakes.FakeNode{}
+			node.PosReturns(parsley.Pos(3))
+			node.ReaderPosReturns(parsley.Pos(6))
+			r, ok := fs.NodeRange(node)
+			Expect(ok).To(BeTrue())
+			Expect(r.String()).To(Equal("file1:1:3-1:6"))
+		})
+	})
+
+	Describe("LookupPos()", func() {
+		BeforeEach(func() {
+			f1 := &parsleyfakes.FakeFile{}
+			f1.LenReturns(10)
+			f2 := &parsleyfakes.FakeFile{}
+			f2.LenReturns(20)
+			files = []parsley.File{lineFile{FakeFile: f1, filename: "file1"}, lineFile{FakeFile: f2, filename: "file2"}}
+		})
+
+		It("returns with the global position", func() {
+			pos, ok := fs.LookupPos("file2", 1, 3)
+			Expect(ok).To(BeTrue())
+			Expect(pos).To(Equal(parsley.Pos(14)))
+		})
+
+		It("returns false if the file doesn't exist", func() {
+			_, ok := fs.LookupPos("other", 1, 1)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("returns false if the position is invalid", func() {
+			_, ok := fs.LookupPos("file1", 2, 1)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
 	Describe("ErrorWithPosition()", func() {
 		var (
 			f        *parsleyfakes.FakeFile
@@ -162,6 +287,7 @@ var _ = Describe("File set", func() {
 			err.PosReturns(parsley.Pos(2))
 			errWithPos := fs.ErrorWithPosition(err)
 			Expect(errWithPos).To(MatchError("test error at testpos"))
+			Expect(errors.Unwrap(errWithPos)).To(BeIdenticalTo(err))
 
 			Expect(f.PositionCallCount()).To(Equal(1))
 			passedPos := f.PositionArgsForCall(0)
@@ -188,4 +314,71 @@ var _ = Describe("File set", func() {
 			})
 		})
 	})
+
+	Describe("Edit()", func() {
+		var (
+			f1 *parsleyfakes.FakeEditableFile
+			f2 *parsleyfakes.FakeFile
+		)
+
+		BeforeEach(func() {
+			f1 = &parsleyfakes.FakeEditableFile{}
+			f1.LenReturns(10)
+			f1.EditReturns(5)
+			f2 = &parsleyfakes.FakeFile{}
+			f2.LenReturns(20)
+			files = []parsley.File{f1, f2}
+		})
+
+		It("should edit the file and move the subsequent files", func() {
+			e, err := fs.Edit(parsley.Pos(3), parsley.Pos(5), []byte("abcde"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(e).To(Equal(parsley.Edit{Pos: 3, OldEnd: 5, NewEnd: 8}))
+			Expect(e.Delta()).To(Equal(3))
+
+			Expect(f1.EditCallCount()).To(Equal(1))
+			start, end, data := f1.EditArgsForCall(0)
+			Expect(start).To(Equal(2))
+			Expect(end).To(Equal(4))
+			Expect(data).To(Equal([]byte("abcde")))
+
+			Expect(f2.SetOffsetCallCount()).To(Equal(2))
+			Expect(f2.SetOffsetArgsForCall(1)).To(Equal(15))
+		})
+
+		It("should return an error if the file is not editable", func() {
+			_, err := fs.Edit(parsley.Pos(13), parsley.Pos(14), []byte("x"))
+			Expect(err).To(MatchError("*parsleyfakes.FakeFile is not editable"))
+		})
+
+		DescribeTable("should return an error for invalid ranges",
+			func(start, end int) {
+				_, err := fs.Edit(parsley.Pos(start), parsley.Pos(end), nil)
+				Expect(err).To(MatchError(fmt.Sprintf("invalid edit range: %d..%d", start, end)))
+				Expect(f1.EditCallCount()).To(Equal(0))
+			},
+			Entry("nil start", 0, 1),
+			Entry("end before start", 3, 2),
+			Entry("spanning multiple files", 10, 13),
+			Entry("outside of the file set", 33, 33),
+		)
+	})
 })
+
+// lineFile is a single-line file
+type lineFile struct {
+	*parsleyfakes.FakeFile
+	filename string
+}
+
+func (l lineFile) Filename() string {
+	return l.filename
+}
+
+func (l lineFile) LineColumn(offset int) (int, int, bool) {
+	return 1, offset + 1, offset <= l.Len()
+}
+
+func (l lineFile) Offset(line int, column int) (int, bool) {
+	return column - 1, line == 1 && column >= 1 && column-1 <= l.Len()
+}
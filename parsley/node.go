@@ -33,3 +33,10 @@ type NonTerminalNode interface {
 	NonLiteralNode
 	Children() []Node
 }
+
+// ErrorNode represents a part of the input which couldn't be parsed but the parser recovered from the error
+//counterfeiter:generate . ErrorNode
+type ErrorNode interface {
+	Node
+	Error() Error
+}
@@ -15,32 +15,69 @@ import (
 // Parse parses the given input and returns with the root node of the AST.
 // If a transformer is set on the context then the result will be transformed using it.
 // If there are multiple possible parse trees only the first one is returned.
+// If the parser recovered from any errors (see ErrorNode) then the partial tree is returned with an ErrorList
+// containing the recovered errors. If the context's error limit was reached then the parsing stops, and only the
+// recovered errors are returned without a tree.
+// If the reader implements ErrorReporter and it has an error (e.g. a stream couldn't be read) then that error is returned.
 func Parse(ctx *Context, p Parser) (Node, error) {
 	var err Error
 	var node Node
 
-	if node, _, err = p.Parse(ctx, data.EmptyIntMap, ctx.Reader().Pos(0)); err != nil {
+	node, _, err = p.Parse(ctx, data.EmptyIntMap, ctx.Reader().Pos(0))
+
+	if er, ok := ctx.Reader().(ErrorReporter); ok {
+		if readErr := er.Err(); readErr != nil {
+			return nil, fmt.Errorf("failed to parse the input: %w", readErr)
+		}
+	}
+
+	if err != nil && ctx.ErrorLimitReached() {
+		errs := make(ErrorList, 0, len(ctx.recoveredErrs))
+		for _, recoveredErr := range ctx.recoveredErrs {
+			errs = append(errs, ctx.FormatError(recoveredErr))
+		}
+		return nil, fmt.Errorf("failed to parse the input: %w", errs)
+	}
+
+	if err != nil {
 		if !IsWhitespaceError(err) {
+			ctx.RegisterNotFoundError(err)
 			if ctxErr := ctx.Error(); ctxErr != nil && ctxErr.Pos() > err.Pos() {
 				err = ctxErr
 			}
+			err = ctx.ExtendNotFoundError(err)
 		}
 
-		return nil, fmt.Errorf("failed to parse the input: %w", ctx.FileSet().ErrorWithPosition(err))
+		return nil, fmt.Errorf("failed to parse the input: %w", ctx.FormatError(err))
+	}
+
+	if errs := recoveredErrors(ctx, node); len(errs) > 0 {
+		return node, fmt.Errorf("failed to parse the input: %w", errs)
 	}
 
 	if ctx.TransformationEnabled() {
 		node, err = Transform(ctx.UserContext(), node)
 		if err != nil {
-			return nil, ctx.FileSet().ErrorWithPosition(err)
+			return nil, ctx.FormatError(err)
 		}
 	}
 
 	if ctx.StaticCheckEnabled() {
 		if err = StaticCheck(ctx.UserContext(), node); err != nil {
-			return nil, ctx.FileSet().ErrorWithPosition(err)
+			return nil, ctx.FormatError(err)
 		}
 	}
 
 	return node, nil
 }
+
+func recoveredErrors(ctx *Context, node Node) ErrorList {
+	var errs ErrorList
+	Walk(node, func(n Node) bool {
+		if errorNode, ok := n.(ErrorNode); ok {
+			errs = append(errs, ctx.FormatError(errorNode.Error()))
+		}
+		return ctx.ErrorLimit() > 0 && len(errs) >= ctx.ErrorLimit()
+	})
+	return errs
+}
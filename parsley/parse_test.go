@@ -210,6 +210,53 @@ var _ = Describe("Parse", func() {
 		})
 	})
 
+	Context("if the parser recovered from errors", func() {
+		var errorNode1, errorNode2 *parsleyfakes.FakeErrorNode
+
+		BeforeEach(func() {
+			errorNode1 = &parsleyfakes.FakeErrorNode{}
+			errorNode1.ErrorReturns(parsley.NewErrorf(parsley.Pos(1), "error 1"))
+			errorNode2 = &parsleyfakes.FakeErrorNode{}
+			errorNode2.ErrorReturns(parsley.NewErrorf(parsley.Pos(2), "error 2"))
+			resultNode := &parsleyfakes.FakeNonTerminalNode{}
+			resultNode.ChildrenReturns([]parsley.Node{errorNode1, errorNode2})
+			parserRes = resultNode
+			ctx.EnableStaticCheck()
+		})
+
+		It("should return the partial result", func() {
+			Expect(res).To(Equal(parserRes))
+		})
+
+		It("should return all errors", func() {
+			Expect(err).To(MatchError("failed to parse the input: error 1 at testpos\nerror 2 at testpos"))
+			var errs parsley.ErrorList
+			Expect(errors.As(err, &errs)).To(BeTrue())
+			Expect(errs).To(HaveLen(2))
+		})
+
+		Context("if the error limit is reached", func() {
+			BeforeEach(func() {
+				ctx.SetErrorLimit(1)
+			})
+
+			It("should return the errors up to the limit", func() {
+				Expect(err).To(MatchError("failed to parse the input: error 1 at testpos"))
+			})
+		})
+	})
+
+	Context("if the reader has an error", func() {
+		BeforeEach(func() {
+			ctx = parsley.NewContext(ctx.FileSet(), failingReader{FakeReader: r})
+		})
+
+		It("should return the reader error", func() {
+			Expect(res).To(BeNil())
+			Expect(err).To(MatchError("failed to parse the input: read error"))
+		})
+	})
+
 	Context("if the parser has an error", func() {
 		BeforeEach(func() {
 			parserRes = nil
@@ -233,6 +280,25 @@ var _ = Describe("Parse", func() {
 				Expect(err).To(MatchError("failed to parse the input: context error at testpos"))
 			})
 		})
+
+		Context("if an error formatter is set", func() {
+			BeforeEach(func() {
+				ctx.SetErrorFormatter(parsley.ErrorFormatterFunc(func(fs *parsley.FileSet, err parsley.Error) error {
+					return errors.New("formatted " + err.Error())
+				}))
+			})
+			It("should use the error formatter", func() {
+				Expect(err).To(MatchError("failed to parse the input: formatted some error"))
+			})
+		})
 	})
 
 })
+
+type failingReader struct {
+	*parsleyfakes.FakeReader
+}
+
+func (f failingReader) Err() error {
+	return errors.New("read error")
+}
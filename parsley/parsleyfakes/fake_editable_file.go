@@ -0,0 +1,372 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package parsleyfakes
+
+import (
+	"sync"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+type FakeEditableFile struct {
+	EditStub        func(int, int, []byte) int
+	editMutex       sync.RWMutex
+	editArgsForCall []struct {
+		arg1 int
+		arg2 int
+		arg3 []byte
+	}
+	editReturns struct {
+		result1 int
+	}
+	editReturnsOnCall map[int]struct {
+		result1 int
+	}
+	LenStub        func() int
+	lenMutex       sync.RWMutex
+	lenArgsForCall []struct {
+	}
+	lenReturns struct {
+		result1 int
+	}
+	lenReturnsOnCall map[int]struct {
+		result1 int
+	}
+	PosStub        func(int) parsley.Pos
+	posMutex       sync.RWMutex
+	posArgsForCall []struct {
+		arg1 int
+	}
+	posReturns struct {
+		result1 parsley.Pos
+	}
+	posReturnsOnCall map[int]struct {
+		result1 parsley.Pos
+	}
+	PositionStub        func(int) parsley.Position
+	positionMutex       sync.RWMutex
+	positionArgsForCall []struct {
+		arg1 int
+	}
+	positionReturns struct {
+		result1 parsley.Position
+	}
+	positionReturnsOnCall map[int]struct {
+		result1 parsley.Position
+	}
+	SetOffsetStub        func(int)
+	setOffsetMutex       sync.RWMutex
+	setOffsetArgsForCall []struct {
+		arg1 int
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeEditableFile) Edit(arg1 int, arg2 int, arg3 []byte) int {
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.editMutex.Lock()
+	ret, specificReturn := fake.editReturnsOnCall[len(fake.editArgsForCall)]
+	fake.editArgsForCall = append(fake.editArgsForCall, struct {
+		arg1 int
+		arg2 int
+		arg3 []byte
+	}{arg1, arg2, arg3Copy})
+	stub := fake.EditStub
+	fakeReturns := fake.editReturns
+	fake.recordInvocation("Edit", []interface{}{arg1, arg2, arg3Copy})
+	fake.editMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeEditableFile) EditCallCount() int {
+	fake.editMutex.RLock()
+	defer fake.editMutex.RUnlock()
+	return len(fake.editArgsForCall)
+}
+
+func (fake *FakeEditableFile) EditCalls(stub func(int, int, []byte) int) {
+	fake.editMutex.Lock()
+	defer fake.editMutex.Unlock()
+	fake.EditStub = stub
+}
+
+func (fake *FakeEditableFile) EditArgsForCall(i int) (int, int, []byte) {
+	fake.editMutex.RLock()
+	defer fake.editMutex.RUnlock()
+	argsForCall := fake.editArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeEditableFile) EditReturns(result1 int) {
+	fake.editMutex.Lock()
+	defer fake.editMutex.Unlock()
+	fake.EditStub = nil
+	fake.editReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeEditableFile) EditReturnsOnCall(i int, result1 int) {
+	fake.editMutex.Lock()
+	defer fake.editMutex.Unlock()
+	fake.EditStub = nil
+	if fake.editReturnsOnCall == nil {
+		fake.editReturnsOnCall = make(map[int]struct {
+			result1 int
+		})
+	}
+	fake.editReturnsOnCall[i] = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeEditableFile) Len() int {
+	fake.lenMutex.Lock()
+	ret, specificReturn := fake.lenReturnsOnCall[len(fake.lenArgsForCall)]
+	fake.lenArgsForCall = append(fake.lenArgsForCall, struct {
+	}{})
+	stub := fake.LenStub
+	fakeReturns := fake.lenReturns
+	fake.recordInvocation("Len", []interface{}{})
+	fake.lenMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeEditableFile) LenCallCount() int {
+	fake.lenMutex.RLock()
+	defer fake.lenMutex.RUnlock()
+	return len(fake.lenArgsForCall)
+}
+
+func (fake *FakeEditableFile) LenCalls(stub func() int) {
+	fake.lenMutex.Lock()
+	defer fake.lenMutex.Unlock()
+	fake.LenStub = stub
+}
+
+func (fake *FakeEditableFile) LenReturns(result1 int) {
+	fake.lenMutex.Lock()
+	defer fake.lenMutex.Unlock()
+	fake.LenStub = nil
+	fake.lenReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeEditableFile) LenReturnsOnCall(i int, result1 int) {
+	fake.lenMutex.Lock()
+	defer fake.lenMutex.Unlock()
+	fake.LenStub = nil
+	if fake.lenReturnsOnCall == nil {
+		fake.lenReturnsOnCall = make(map[int]struct {
+			result1 int
+		})
+	}
+	fake.lenReturnsOnCall[i] = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeEditableFile) Pos(arg1 int) parsley.Pos {
+	fake.posMutex.Lock()
+	ret, specificReturn := fake.posReturnsOnCall[len(fake.posArgsForCall)]
+	fake.posArgsForCall = append(fake.posArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.PosStub
+	fakeReturns := fake.posReturns
+	fake.recordInvocation("Pos", []interface{}{arg1})
+	fake.posMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeEditableFile) PosCallCount() int {
+	fake.posMutex.RLock()
+	defer fake.posMutex.RUnlock()
+	return len(fake.posArgsForCall)
+}
+
+func (fake *FakeEditableFile) PosCalls(stub func(int) parsley.Pos) {
+	fake.posMutex.Lock()
+	defer fake.posMutex.Unlock()
+	fake.PosStub = stub
+}
+
+func (fake *FakeEditableFile) PosArgsForCall(i int) int {
+	fake.posMutex.RLock()
+	defer fake.posMutex.RUnlock()
+	argsForCall := fake.posArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeEditableFile) PosReturns(result1 parsley.Pos) {
+	fake.posMutex.Lock()
+	defer fake.posMutex.Unlock()
+	fake.PosStub = nil
+	fake.posReturns = struct {
+		result1 parsley.Pos
+	}{result1}
+}
+
+func (fake *FakeEditableFile) PosReturnsOnCall(i int, result1 parsley.Pos) {
+	fake.posMutex.Lock()
+	defer fake.posMutex.Unlock()
+	fake.PosStub = nil
+	if fake.posReturnsOnCall == nil {
+		fake.posReturnsOnCall = make(map[int]struct {
+			result1 parsley.Pos
+		})
+	}
+	fake.posReturnsOnCall[i] = struct {
+		result1 parsley.Pos
+	}{result1}
+}
+
+func (fake *FakeEditableFile) Position(arg1 int) parsley.Position {
+	fake.positionMutex.Lock()
+	ret, specificReturn := fake.positionReturnsOnCall[len(fake.positionArgsForCall)]
+	fake.positionArgsForCall = append(fake.positionArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.PositionStub
+	fakeReturns := fake.positionReturns
+	fake.recordInvocation("Position", []interface{}{arg1})
+	fake.positionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeEditableFile) PositionCallCount() int {
+	fake.positionMutex.RLock()
+	defer fake.positionMutex.RUnlock()
+	return len(fake.positionArgsForCall)
+}
+
+func (fake *FakeEditableFile) PositionCalls(stub func(int) parsley.Position) {
+	fake.positionMutex.Lock()
+	defer fake.positionMutex.Unlock()
+	fake.PositionStub = stub
+}
+
+func (fake *FakeEditableFile) PositionArgsForCall(i int) int {
+	fake.positionMutex.RLock()
+	defer fake.positionMutex.RUnlock()
+	argsForCall := fake.positionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeEditableFile) PositionReturns(result1 parsley.Position) {
+	fake.positionMutex.Lock()
+	defer fake.positionMutex.Unlock()
+	fake.PositionStub = nil
+	fake.positionReturns = struct {
+		result1 parsley.Position
+	}{result1}
+}
+
+func (fake *FakeEditableFile) PositionReturnsOnCall(i int, result1 parsley.Position) {
+	fake.positionMutex.Lock()
+	defer fake.positionMutex.Unlock()
+	fake.PositionStub = nil
+	if fake.positionReturnsOnCall == nil {
+		fake.positionReturnsOnCall = make(map[int]struct {
+			result1 parsley.Position
+		})
+	}
+	fake.positionReturnsOnCall[i] = struct {
+		result1 parsley.Position
+	}{result1}
+}
+
+func (fake *FakeEditableFile) SetOffset(arg1 int) {
+	fake.setOffsetMutex.Lock()
+	fake.setOffsetArgsForCall = append(fake.setOffsetArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.SetOffsetStub
+	fake.recordInvocation("SetOffset", []interface{}{arg1})
+	fake.setOffsetMutex.Unlock()
+	if stub != nil {
+		fake.SetOffsetStub(arg1)
+	}
+}
+
+func (fake *FakeEditableFile) SetOffsetCallCount() int {
+	fake.setOffsetMutex.RLock()
+	defer fake.setOffsetMutex.RUnlock()
+	return len(fake.setOffsetArgsForCall)
+}
+
+func (fake *FakeEditableFile) SetOffsetCalls(stub func(int)) {
+	fake.setOffsetMutex.Lock()
+	defer fake.setOffsetMutex.Unlock()
+	fake.SetOffsetStub = stub
+}
+
+func (fake *FakeEditableFile) SetOffsetArgsForCall(i int) int {
+	fake.setOffsetMutex.RLock()
+	defer fake.setOffsetMutex.RUnlock()
+	argsForCall := fake.setOffsetArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeEditableFile) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.editMutex.RLock()
+	defer fake.editMutex.RUnlock()
+	fake.lenMutex.RLock()
+	defer fake.lenMutex.RUnlock()
+	fake.posMutex.RLock()
+	defer fake.posMutex.RUnlock()
+	fake.positionMutex.RLock()
+	defer fake.positionMutex.RUnlock()
+	fake.setOffsetMutex.RLock()
+	defer fake.setOffsetMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeEditableFile) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ parsley.EditableFile = new(FakeEditableFile)
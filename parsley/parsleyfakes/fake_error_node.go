@@ -0,0 +1,362 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package parsleyfakes
+
+import (
+	"sync"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+type FakeErrorNode struct {
+	ErrorStub        func() parsley.Error
+	errorMutex       sync.RWMutex
+	errorArgsForCall []struct {
+	}
+	errorReturns struct {
+		result1 parsley.Error
+	}
+	errorReturnsOnCall map[int]struct {
+		result1 parsley.Error
+	}
+	PosStub        func() parsley.Pos
+	posMutex       sync.RWMutex
+	posArgsForCall []struct {
+	}
+	posReturns struct {
+		result1 parsley.Pos
+	}
+	posReturnsOnCall map[int]struct {
+		result1 parsley.Pos
+	}
+	ReaderPosStub        func() parsley.Pos
+	readerPosMutex       sync.RWMutex
+	readerPosArgsForCall []struct {
+	}
+	readerPosReturns struct {
+		result1 parsley.Pos
+	}
+	readerPosReturnsOnCall map[int]struct {
+		result1 parsley.Pos
+	}
+	SchemaStub        func() interface{}
+	schemaMutex       sync.RWMutex
+	schemaArgsForCall []struct {
+	}
+	schemaReturns struct {
+		result1 interface{}
+	}
+	schemaReturnsOnCall map[int]struct {
+		result1 interface{}
+	}
+	TokenStub        func() string
+	tokenMutex       sync.RWMutex
+	tokenArgsForCall []struct {
+	}
+	tokenReturns struct {
+		result1 string
+	}
+	tokenReturnsOnCall map[int]struct {
+		result1 string
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeErrorNode) Error() parsley.Error {
+	fake.errorMutex.Lock()
+	ret, specificReturn := fake.errorReturnsOnCall[len(fake.errorArgsForCall)]
+	fake.errorArgsForCall = append(fake.errorArgsForCall, struct {
+	}{})
+	stub := fake.ErrorStub
+	fakeReturns := fake.errorReturns
+	fake.recordInvocation("Error", []interface{}{})
+	fake.errorMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeErrorNode) ErrorCallCount() int {
+	fake.errorMutex.RLock()
+	defer fake.errorMutex.RUnlock()
+	return len(fake.errorArgsForCall)
+}
+
+func (fake *FakeErrorNode) ErrorCalls(stub func() parsley.Error) {
+	fake.errorMutex.Lock()
+	defer fake.errorMutex.Unlock()
+	fake.ErrorStub = stub
+}
+
+func (fake *FakeErrorNode) ErrorReturns(result1 parsley.Error) {
+	fake.errorMutex.Lock()
+	defer fake.errorMutex.Unlock()
+	fake.ErrorStub = nil
+	fake.errorReturns = struct {
+		result1 parsley.Error
+	}{result1}
+}
+
+func (fake *FakeErrorNode) ErrorReturnsOnCall(i int, result1 parsley.Error) {
+	fake.errorMutex.Lock()
+	defer fake.errorMutex.Unlock()
+	fake.ErrorStub = nil
+	if fake.errorReturnsOnCall == nil {
+		fake.errorReturnsOnCall = make(map[int]struct {
+			result1 parsley.Error
+		})
+	}
+	fake.errorReturnsOnCall[i] = struct {
+		result1 parsley.Error
+	}{result1}
+}
+
+func (fake *FakeErrorNode) Pos() parsley.Pos {
+	fake.posMutex.Lock()
+	ret, specificReturn := fake.posReturnsOnCall[len(fake.posArgsForCall)]
+	fake.posArgsForCall = append(fake.posArgsForCall, struct {
+	}{})
+	stub := fake.PosStub
+	fakeReturns := fake.posReturns
+	fake.recordInvocation("Pos", []interface{}{})
+	fake.posMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeErrorNode) PosCallCount() int {
+	fake.posMutex.RLock()
+	defer fake.posMutex.RUnlock()
+	return len(fake.posArgsForCall)
+}
+
+func (fake *FakeErrorNode) PosCalls(stub func() parsley.Pos) {
+	fake.posMutex.Lock()
+	defer fake.posMutex.Unlock()
+	fake.PosStub = stub
+}
+
+func (fake *FakeErrorNode) PosReturns(result1 parsley.Pos) {
+	fake.posMutex.Lock()
+	defer fake.posMutex.Unlock()
+	fake.PosStub = nil
+	fake.posReturns = struct {
+		result1 parsley.Pos
+	}{result1}
+}
+
+func (fake *FakeErrorNode) PosReturnsOnCall(i int, result1 parsley.Pos) {
+	fake.posMutex.Lock()
+	defer fake.posMutex.Unlock()
+	fake.PosStub = nil
+	if fake.posReturnsOnCall == nil {
+		fake.posReturnsOnCall = make(map[int]struct {
+			result1 parsley.Pos
+		})
+	}
+	fake.posReturnsOnCall[i] = struct {
+		result1 parsley.Pos
+	}{result1}
+}
+
+func (fake *FakeErrorNode) ReaderPos() parsley.Pos {
+	fake.readerPosMutex.Lock()
+	ret, specificReturn := fake.readerPosReturnsOnCall[len(fake.readerPosArgsForCall)]
+	fake.readerPosArgsForCall = append(fake.readerPosArgsForCall, struct {
+	}{})
+	stub := fake.ReaderPosStub
+	fakeReturns := fake.readerPosReturns
+	fake.recordInvocation("ReaderPos", []interface{}{})
+	fake.readerPosMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeErrorNode) ReaderPosCallCount() int {
+	fake.readerPosMutex.RLock()
+	defer fake.readerPosMutex.RUnlock()
+	return len(fake.readerPosArgsForCall)
+}
+
+func (fake *FakeErrorNode) ReaderPosCalls(stub func() parsley.Pos) {
+	fake.readerPosMutex.Lock()
+	defer fake.readerPosMutex.Unlock()
+	fake.ReaderPosStub = stub
+}
+
+func (fake *FakeErrorNode) ReaderPosReturns(result1 parsley.Pos) {
+	fake.readerPosMutex.Lock()
+	defer fake.readerPosMutex.Unlock()
+	fake.ReaderPosStub = nil
+	fake.readerPosReturns = struct {
+		result1 parsley.Pos
+	}{result1}
+}
+
+func (fake *FakeErrorNode) ReaderPosReturnsOnCall(i int, result1 parsley.Pos) {
+	fake.readerPosMutex.Lock()
+	defer fake.readerPosMutex.Unlock()
+	fake.ReaderPosStub = nil
+	if fake.readerPosReturnsOnCall == nil {
+		fake.readerPosReturnsOnCall = make(map[int]struct {
+			result1 parsley.Pos
+		})
+	}
+	fake.readerPosReturnsOnCall[i] = struct {
+		result1 parsley.Pos
+	}{result1}
+}
+
+func (fake *FakeErrorNode) Schema() interface{} {
+	fake.schemaMutex.Lock()
+	ret, specificReturn := fake.schemaReturnsOnCall[len(fake.schemaArgsForCall)]
+	fake.schemaArgsForCall = append(fake.schemaArgsForCall, struct {
+	}{})
+	stub := fake.SchemaStub
+	fakeReturns := fake.schemaReturns
+	fake.recordInvocation("Schema", []interface{}{})
+	fake.schemaMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeErrorNode) SchemaCallCount() int {
+	fake.schemaMutex.RLock()
+	defer fake.schemaMutex.RUnlock()
+	return len(fake.schemaArgsForCall)
+}
+
+func (fake *FakeErrorNode) SchemaCalls(stub func() interface{}) {
+	fake.schemaMutex.Lock()
+	defer fake.schemaMutex.Unlock()
+	fake.SchemaStub = stub
+}
+
+func (fake *FakeErrorNode) SchemaReturns(result1 interface{}) {
+	fake.schemaMutex.Lock()
+	defer fake.schemaMutex.Unlock()
+	fake.SchemaStub = nil
+	fake.schemaReturns = struct {
+		result1 interface{}
+	}{result1}
+}
+
+func (fake *FakeErrorNode) SchemaReturnsOnCall(i int, result1 interface{}) {
+	fake.schemaMutex.Lock()
+	defer fake.schemaMutex.Unlock()
+	fake.SchemaStub = nil
+	if fake.schemaReturnsOnCall == nil {
+		fake.schemaReturnsOnCall = make(map[int]struct {
+			result1 interface{}
+		})
+	}
+	fake.schemaReturnsOnCall[i] = struct {
+		result1 interface{}
+	}{result1}
+}
+
+func (fake *FakeErrorNode) Token() string {
+	fake.tokenMutex.Lock()
+	ret, specificReturn := fake.tokenReturnsOnCall[len(fake.tokenArgsForCall)]
+	fake.tokenArgsForCall = append(fake.tokenArgsForCall, struct {
+	}{})
+	stub := fake.TokenStub
+	fakeReturns := fake.tokenReturns
+	fake.recordInvocation("Token", []interface{}{})
+	fake.tokenMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeErrorNode) TokenCallCount() int {
+	fake.tokenMutex.RLock()
+	defer fake.tokenMutex.RUnlock()
+	return len(fake.tokenArgsForCall)
+}
+
+func (fake *FakeErrorNode) TokenCalls(stub func() string) {
+	fake.tokenMutex.Lock()
+	defer fake.tokenMutex.Unlock()
+	fake.TokenStub = stub
+}
+
+func (fake *FakeErrorNode) TokenReturns(result1 string) {
+	fake.tokenMutex.Lock()
+	defer fake.tokenMutex.Unlock()
+	fake.TokenStub = nil
+	fake.tokenReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeErrorNode) TokenReturnsOnCall(i int, result1 string) {
+	fake.tokenMutex.Lock()
+	defer fake.tokenMutex.Unlock()
+	fake.TokenStub = nil
+	if fake.tokenReturnsOnCall == nil {
+		fake.tokenReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.tokenReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeErrorNode) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.errorMutex.RLock()
+	defer fake.errorMutex.RUnlock()
+	fake.posMutex.RLock()
+	defer fake.posMutex.RUnlock()
+	fake.readerPosMutex.RLock()
+	defer fake.readerPosMutex.RUnlock()
+	fake.schemaMutex.RLock()
+	defer fake.schemaMutex.RUnlock()
+	fake.tokenMutex.RLock()
+	defer fake.tokenMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeErrorNode) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ parsley.ErrorNode = new(FakeErrorNode)
@@ -0,0 +1,111 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package parsleyfakes
+
+import (
+	"sync"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+type FakePosShifter struct {
+	ShiftPosStub        func(*parsley.Shifter) parsley.Node
+	shiftPosMutex       sync.RWMutex
+	shiftPosArgsForCall []struct {
+		arg1 *parsley.Shifter
+	}
+	shiftPosReturns struct {
+		result1 parsley.Node
+	}
+	shiftPosReturnsOnCall map[int]struct {
+		result1 parsley.Node
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePosShifter) ShiftPos(arg1 *parsley.Shifter) parsley.Node {
+	fake.shiftPosMutex.Lock()
+	ret, specificReturn := fake.shiftPosReturnsOnCall[len(fake.shiftPosArgsForCall)]
+	fake.shiftPosArgsForCall = append(fake.shiftPosArgsForCall, struct {
+		arg1 *parsley.Shifter
+	}{arg1})
+	stub := fake.ShiftPosStub
+	fakeReturns := fake.shiftPosReturns
+	fake.recordInvocation("ShiftPos", []interface{}{arg1})
+	fake.shiftPosMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePosShifter) ShiftPosCallCount() int {
+	fake.shiftPosMutex.RLock()
+	defer fake.shiftPosMutex.RUnlock()
+	return len(fake.shiftPosArgsForCall)
+}
+
+func (fake *FakePosShifter) ShiftPosCalls(stub func(*parsley.Shifter) parsley.Node) {
+	fake.shiftPosMutex.Lock()
+	defer fake.shiftPosMutex.Unlock()
+	fake.ShiftPosStub = stub
+}
+
+func (fake *FakePosShifter) ShiftPosArgsForCall(i int) *parsley.Shifter {
+	fake.shiftPosMutex.RLock()
+	defer fake.shiftPosMutex.RUnlock()
+	argsForCall := fake.shiftPosArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePosShifter) ShiftPosReturns(result1 parsley.Node) {
+	fake.shiftPosMutex.Lock()
+	defer fake.shiftPosMutex.Unlock()
+	fake.ShiftPosStub = nil
+	fake.shiftPosReturns = struct {
+		result1 parsley.Node
+	}{result1}
+}
+
+func (fake *FakePosShifter) ShiftPosReturnsOnCall(i int, result1 parsley.Node) {
+	fake.shiftPosMutex.Lock()
+	defer fake.shiftPosMutex.Unlock()
+	fake.ShiftPosStub = nil
+	if fake.shiftPosReturnsOnCall == nil {
+		fake.shiftPosReturnsOnCall = make(map[int]struct {
+			result1 parsley.Node
+		})
+	}
+	fake.shiftPosReturnsOnCall[i] = struct {
+		result1 parsley.Node
+	}{result1}
+}
+
+func (fake *FakePosShifter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.shiftPosMutex.RLock()
+	defer fake.shiftPosMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakePosShifter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ parsley.PosShifter = new(FakePosShifter)
@@ -0,0 +1,62 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parsley
+
+// NodeAt returns with the innermost node which covers the given position, or nil if there is none
+// See PathAt for the details.
+func NodeAt(root Node, pos Pos) Node {
+	path := PathAt(root, pos)
+	if len(path) == 0 {
+		return nil
+	}
+	return path[len(path)-1]
+}
+
+// PathAt returns with the nodes covering the given position, starting with the root and ending with the innermost node
+//
+// A node covers the positions from Pos() until ReaderPos(), excluding ReaderPos(), so empty nodes never cover any
+// position. The children of a Walkable node are not known, so all the covering nodes visited by its Walk method
+// are added to the path in reverse order of the visit, as the children are visited before their parents.
+func PathAt(root Node, pos Pos) []Node {
+	var path []Node
+	appendPath(&path, root, pos)
+	return path
+}
+
+func appendPath(path *[]Node, node Node, pos Pos) bool {
+	if node == nil || !covers(node, pos) {
+		return false
+	}
+
+	*path = append(*path, node)
+
+	switch n := node.(type) {
+	case Walkable:
+		var nodes []Node
+		n.Walk(func(child Node) bool {
+			if covers(child, pos) {
+				nodes = append(nodes, child)
+			}
+			return false
+		})
+		for i := len(nodes) - 1; i >= 0; i-- {
+			*path = append(*path, nodes[i])
+		}
+	case NonTerminalNode:
+		for _, child := range n.Children() {
+			if appendPath(path, child, pos) {
+				break
+			}
+		}
+	}
+
+	return true
+}
+
+func covers(node Node, pos Pos) bool {
+	return node.Pos() <= pos && pos < node.ReaderPos()
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parsley_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/parsley/parsleyfakes"
+)
+
+var _ = Describe("PathAt", func() {
+
+	var (
+		a, b, c, d parsley.Node
+		inner      parsley.Node
+		empty      parsley.Node
+		root       parsley.Node
+	)
+
+	BeforeEach(func() {
+		// root: 1..9
+		//   a: 1..3
+		//   empty: 3..3
+		//   inner: 3..7
+		//     b: 3..5
+		//     c: 5..7
+		//   d: 7..9
+		a = ast.NewTerminalNode(nil, "A", "a", parsley.Pos(1), parsley.Pos(3))
+		b = ast.NewTerminalNode(nil, "B", "b", parsley.Pos(3), parsley.Pos(5))
+		c = ast.NewTerminalNode(nil, "C", "c", parsley.Pos(5), parsley.Pos(7))
+		d = ast.NewTerminalNode(nil, "D", "d", parsley.Pos(7), parsley.Pos(9))
+		empty = ast.EmptyNode(3)
+		inner = ast.NewNonTerminalNode("INNER", []parsley.Node{b, c}, nil)
+		root = ast.NewNonTerminalNode("ROOT", []parsley.Node{a, empty, inner, d}, nil)
+	})
+
+	It("should return the path to the innermost node", func() {
+		Expect(parsley.PathAt(root, parsley.Pos(6))).To(Equal([]parsley.Node{root, inner, c}))
+		Expect(parsley.NodeAt(root, parsley.Pos(6))).To(BeIdenticalTo(c))
+	})
+
+	It("should use the start position as inclusive and the end position as exclusive", func() {
+		Expect(parsley.PathAt(root, parsley.Pos(3))).To(Equal([]parsley.Node{root, inner, b}))
+		Expect(parsley.PathAt(root, parsley.Pos(8))).To(Equal([]parsley.Node{root, d}))
+	})
+
+	It("should return nil if the root doesn't cover the position", func() {
+		Expect(parsley.PathAt(root, parsley.Pos(9))).To(BeNil())
+		Expect(parsley.NodeAt(root, parsley.Pos(9))).To(BeNil())
+	})
+
+	It("should never return an empty node", func() {
+		Expect(parsley.PathAt(empty, parsley.Pos(3))).To(BeNil())
+	})
+
+	It("should return nil for a nil root", func() {
+		Expect(parsley.PathAt(nil, parsley.Pos(1))).To(BeNil())
+	})
+
+	Context("when the tree contains a node list", func() {
+		It("should use the first node of the list", func() {
+			other := ast.NewNonTerminalNode("OTHER", []parsley.Node{b, c}, nil)
+			list := ast.NodeList{inner, other}
+			Expect(parsley.PathAt(list, parsley.Pos(4))).To(Equal([]parsley.Node{list, inner, b}))
+		})
+	})
+
+	Context("when the tree contains a walkable node", func() {
+		It("should add the covering nodes visited by the walk", func() {
+			walkable := &parsleyfakes.FakeWalkableNode{}
+			walkable.PosReturns(parsley.Pos(1))
+			walkable.ReaderPosReturns(parsley.Pos(9))
+			walkable.WalkStub = func(f func(parsley.Node) bool) bool {
+				for _, n := range []parsley.Node{a, b, c, inner, d} {
+					if f(n) {
+						return true
+					}
+				}
+				return false
+			}
+
+			root = ast.NewNonTerminalNode("ROOT", []parsley.Node{walkable}, nil)
+			Expect(parsley.PathAt(root, parsley.Pos(5))).To(Equal([]parsley.Node{root, walkable, inner, c}))
+		})
+	})
+})
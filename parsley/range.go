@@ -0,0 +1,47 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parsley
+
+import "fmt"
+
+// Location is a position in a file
+// The line and column are zero if the file doesn't implement the LineFile interface.
+type Location struct {
+	Pos    Pos
+	Offset int
+	Line   int
+	Column int
+}
+
+// Range is a part of a file, the end location is exclusive
+type Range struct {
+	Filename string
+	Start    Location
+	End      Location
+}
+
+// String returns with the range in a file:line:column-line:column format
+// If the line numbers are not available then the byte offsets are used.
+func (r Range) String() string {
+	var res string
+	if r.Start.Line > 0 {
+		res = fmt.Sprintf("%d:%d-%d:%d", r.Start.Line, r.Start.Column, r.End.Line, r.End.Column)
+	} else {
+		res = fmt.Sprintf("%d-%d", r.Start.Offset, r.End.Offset)
+	}
+
+	if r.Filename != "" {
+		return r.Filename + ":" + res
+	}
+	return res
+}
+
+// Contains returns true if the given position is in the range
+// An empty range contains its start position.
+func (r Range) Contains(pos Pos) bool {
+	return pos == r.Start.Pos || pos >= r.Start.Pos && pos < r.End.Pos
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parsley_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+var _ = Describe("Range", func() {
+
+	DescribeTable("String()",
+		func(r parsley.Range, expected string) {
+			Expect(r.String()).To(Equal(expected))
+		},
+		Entry("with lines", parsley.Range{
+			Filename: "file",
+			Start:    parsley.Location{Pos: 2, Offset: 1, Line: 1, Column: 2},
+			End:      parsley.Location{Pos: 8, Offset: 7, Line: 2, Column: 3},
+		}, "file:1:2-2:3"),
+		Entry("without lines", parsley.Range{
+			Filename: "file",
+			Start:    parsley.Location{Pos: 2, Offset: 1},
+			End:      parsley.Location{Pos: 8, Offset: 7},
+		}, "file:1-7"),
+		Entry("without a file name", parsley.Range{
+			Start: parsley.Location{Pos: 2, Offset: 1, Line: 1, Column: 2},
+			End:   parsley.Location{Pos: 3, Offset: 2, Line: 1, Column: 3},
+		}, "1:2-1:3"),
+	)
+
+	DescribeTable("Contains()",
+		func(start, end, pos int, expected bool) {
+			r := parsley.Range{
+				Start: parsley.Location{Pos: parsley.Pos(start)},
+				End:   parsley.Location{Pos: parsley.Pos(end)},
+			}
+			Expect(r.Contains(parsley.Pos(pos))).To(Equal(expected))
+		},
+		Entry("before", 2, 5, 1, false),
+		Entry("start", 2, 5, 2, true),
+		Entry("inside", 2, 5, 4, true),
+		Entry("end", 2, 5, 5, false),
+		Entry("empty range start", 2, 2, 2, true),
+	)
+})
@@ -13,3 +13,43 @@ type Reader interface {
 	Remaining(Pos) int
 	IsEOF(Pos) bool
 }
+
+// InputDescriber is an optional interface for readers to give a short description of the input at a given position
+// It is used for creating error messages like "was expecting X, found Y".
+type InputDescriber interface {
+	DescribeInput(Pos) string
+}
+
+// MatchDescriber is an optional interface for readers to give a short description of the input between two positions
+// It is used for creating error messages like "was expecting anything but X".
+type MatchDescriber interface {
+	DescribeMatch(start Pos, end Pos) string
+}
+
+// Discarder is an optional interface for readers which can free up the input before a given position
+// It is called by Context.Commit.
+type Discarder interface {
+	Discard(Pos)
+}
+
+// AutoDiscarder is an optional interface for discarders which should free up the input automatically (e.g. streams)
+// If AutoDiscard returns true, the context calls Discard with the position of every outermost backtrack point, as the
+// parsers can't go back before it (see Context.EnterBacktrackPoint).
+type AutoDiscarder interface {
+	Discarder
+	AutoDiscard() bool
+}
+
+// ErrorReporter is an optional interface for readers which can fail while reading the input (e.g. streams)
+// If the reader has an error after parsing then it's returned by Parse.
+type ErrorReporter interface {
+	Err() error
+}
+
+// ReadTracker is an optional interface for readers which keep track of the furthest position read
+// ReadEnd should return with the position after the last byte examined since the last SetReadEnd call.
+// It's used for recording which part of the input a cached result depends on (see ResultCache.ApplyEdit).
+type ReadTracker interface {
+	ReadEnd() Pos
+	SetReadEnd(Pos)
+}
@@ -16,36 +16,161 @@ type Result struct {
 	CurtailingParsers data.IntSet
 	Error             Error
 	Node              Node
+	ReadEnd           Pos
+}
+
+// usableIn returns true if the result can be used with the given left-recursion context
+func (r *Result) usableIn(leftRecCtx data.IntMap) bool {
+	for _, key := range r.LeftRecCtx.Keys() {
+		if r.LeftRecCtx.Get(key) > leftRecCtx.Get(key) {
+			return false
+		}
+	}
+	return true
 }
 
 // ResultCache records information about parser calls
-type ResultCache map[int]map[Pos]*Result
+//
+// NewResultCache creates the default map based implementation and NewDenseResultCache creates an array based
+// implementation for the inputs where most positions have cached results.
+type ResultCache interface {
+	// Save registers a parser result for a certain position
+	Save(parserIndex int, pos Pos, result *Result)
+	// Get returns with a previously saved result if it can be used in the given left-recursion context
+	Get(parserIndex int, pos Pos, leftRecCtx data.IntMap) (*Result, bool)
+	// Delete deletes a result and returns true if it existed
+	Delete(parserIndex int, pos Pos) bool
+	// Discard deletes all results saved before the given position and returns with the number of deleted results
+	Discard(pos Pos) int
+	// Len returns with the number of saved results
+	Len() int
+	// Each calls the given function for all saved results in an unspecified order
+	Each(f func(parserIndex int, pos Pos, result *Result))
+	// ApplyEdit creates a new result cache which can be used after the input was edited
+	// The results which only read the input before the edit are kept, the results after the edit are shifted and
+	// all other results are dropped. The results are only kept if the reader tracked the read positions
+	// (see ReadTracker). The nodes are shifted in place, so any previous parse tree containing them shouldn't be
+	// used anymore.
+	ApplyEdit(e Edit) ResultCache
+}
+
+// applyEdit saves the results which are still valid after the edit in the target cache
+func applyEdit(rc ResultCache, e Edit, target ResultCache) ResultCache {
+	shifter := NewShifter(e.Delta())
+
+	rc.Each(func(parserIndex int, pos Pos, result *Result) {
+		if result.CurtailingParsers.Len() > 0 {
+			return
+		}
 
-// NewResultCache creates a history instance
+		switch {
+		case pos < e.Pos && result.ReadEnd != 0 && result.ReadEnd <= e.Pos:
+			target.Save(parserIndex, pos, result)
+		case pos >= e.OldEnd:
+			shifted := &Result{
+				LeftRecCtx:        result.LeftRecCtx,
+				CurtailingParsers: result.CurtailingParsers,
+				Error:             shifter.Error(result.Error),
+				Node:              shifter.Node(result.Node),
+			}
+			if result.ReadEnd != 0 {
+				shifted.ReadEnd = shifter.Pos(result.ReadEnd)
+			}
+			target.Save(parserIndex, shifter.Pos(pos), shifted)
+		}
+	})
+
+	return target
+}
+
+// mapResultCache stores the results in maps by parser index and position
+type mapResultCache struct {
+	results map[int]map[Pos]*Result
+	len     int
+}
+
+// NewResultCache creates a new map based result cache
 func NewResultCache() ResultCache {
-	return make(map[int]map[Pos]*Result)
+	return &mapResultCache{
+		results: make(map[int]map[Pos]*Result),
+	}
 }
 
 // Save registers a parser result for a certain position
-func (rc ResultCache) Save(parserIndex int, pos Pos, result *Result) {
-	if _, ok := rc[parserIndex]; !ok {
-		rc[parserIndex] = make(map[Pos]*Result)
+func (rc *mapResultCache) Save(parserIndex int, pos Pos, result *Result) {
+	results, ok := rc.results[parserIndex]
+	if !ok {
+		results = make(map[Pos]*Result)
+		rc.results[parserIndex] = results
 	}
-	rc[parserIndex][pos] = result
+	if _, exists := results[pos]; !exists {
+		rc.len++
+	}
+	results[pos] = result
 }
 
 // Get return with a previously saved result
-func (rc ResultCache) Get(parserIndex int, pos Pos, leftRecCtx data.IntMap) (*Result, bool) {
-	result, found := rc[parserIndex][pos]
-	if !found {
+func (rc *mapResultCache) Get(parserIndex int, pos Pos, leftRecCtx data.IntMap) (*Result, bool) {
+	result, found := rc.results[parserIndex][pos]
+	if !found || !result.usableIn(leftRecCtx) {
 		return nil, false
 	}
 
-	for _, key := range result.LeftRecCtx.Keys() {
-		if result.LeftRecCtx.Get(key) > leftRecCtx.Get(key) {
-			return nil, false
+	return result, true
+}
+
+// Delete deletes a result and returns true if it existed
+func (rc *mapResultCache) Delete(parserIndex int, pos Pos) bool {
+	results, ok := rc.results[parserIndex]
+	if !ok {
+		return false
+	}
+	if _, ok := results[pos]; !ok {
+		return false
+	}
+
+	delete(results, pos)
+	if len(results) == 0 {
+		delete(rc.results, parserIndex)
+	}
+	rc.len--
+
+	return true
+}
+
+// Discard deletes all results saved before the given position
+func (rc *mapResultCache) Discard(pos Pos) int {
+	var n int
+	for parserIndex, results := range rc.results {
+		for p := range results {
+			if p < pos {
+				delete(results, p)
+				n++
+			}
+		}
+		if len(results) == 0 {
+			delete(rc.results, parserIndex)
+		}
+	}
+	rc.len -= n
+	return n
+}
+
+// Len returns with the number of saved results
+func (rc *mapResultCache) Len() int {
+	return rc.len
+}
+
+// Each calls the given function for all saved results
+func (rc *mapResultCache) Each(f func(parserIndex int, pos Pos, result *Result)) {
+	for parserIndex, results := range rc.results {
+		for pos, result := range results {
+			f(parserIndex, pos, result)
 		}
 	}
+}
 
-	return result, true
+// ApplyEdit creates a new result cache which can be used after the input was edited
+func (rc *mapResultCache) ApplyEdit(e Edit) ResultCache {
+	return applyEdit(rc, e, NewResultCache())
 }
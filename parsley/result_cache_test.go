@@ -7,6 +7,8 @@
 package parsley_test
 
 import (
+	"testing"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -16,12 +18,41 @@ import (
 )
 
 var _ = Describe("Result Cache", func() {
+	describeResultCache(parsley.NewResultCache)
+})
+
+var _ = Describe("Dense Result Cache", func() {
+	describeResultCache(parsley.NewDenseResultCache)
+
+	It("should handle positions in different pages", func() {
+		rc := parsley.NewDenseResultCache()
+		for i := 0; i < 1000; i += 3 {
+			rc.Save(i%2, parsley.Pos(i), &parsley.Result{ReadEnd: parsley.Pos(i)})
+		}
+		Expect(rc.Len()).To(Equal(334))
+
+		Expect(rc.Discard(parsley.Pos(600))).To(Equal(200))
+		Expect(rc.Len()).To(Equal(134))
+
+		var positions []parsley.Pos
+		rc.Each(func(parserIndex int, pos parsley.Pos, result *parsley.Result) {
+			Expect(parserIndex).To(Equal(int(pos) % 2))
+			Expect(result.ReadEnd).To(Equal(pos))
+			positions = append(positions, pos)
+		})
+		Expect(positions).To(HaveLen(134))
+		Expect(positions).To(ContainElement(parsley.Pos(600)))
+		Expect(positions).ToNot(ContainElement(parsley.Pos(597)))
+	})
+})
+
+func describeResultCache(newResultCache func() parsley.ResultCache) {
 	var (
 		rc parsley.ResultCache
 	)
 
 	BeforeEach(func() {
-		rc = parsley.NewResultCache()
+		rc = newResultCache()
 	})
 
 	Describe("SaveResult/GetResult", func() {
@@ -131,4 +162,135 @@ var _ = Describe("Result Cache", func() {
 			})
 		})
 	})
-})
+	Describe("Discard", func() {
+		It("should delete the results before the position", func() {
+			rc.Save(1, parsley.Pos(1), &parsley.Result{})
+			rc.Save(1, parsley.Pos(2), &parsley.Result{})
+			rc.Save(2, parsley.Pos(1), &parsley.Result{})
+
+			Expect(rc.Discard(parsley.Pos(2))).To(Equal(2))
+
+			_, found := rc.Get(1, parsley.Pos(1), data.EmptyIntMap)
+			Expect(found).To(BeFalse())
+			_, found = rc.Get(1, parsley.Pos(2), data.EmptyIntMap)
+			Expect(found).To(BeTrue())
+			_, found = rc.Get(2, parsley.Pos(1), data.EmptyIntMap)
+			Expect(found).To(BeFalse())
+			Expect(rc.Len()).To(Equal(1))
+		})
+	})
+
+	Describe("Delete", func() {
+		It("should delete the result", func() {
+			rc.Save(1, parsley.Pos(1), &parsley.Result{})
+			rc.Save(1, parsley.Pos(2), &parsley.Result{})
+
+			Expect(rc.Delete(1, parsley.Pos(1))).To(BeTrue())
+			Expect(rc.Delete(1, parsley.Pos(1))).To(BeFalse())
+			Expect(rc.Delete(2, parsley.Pos(1))).To(BeFalse())
+
+			_, found := rc.Get(1, parsley.Pos(1), data.EmptyIntMap)
+			Expect(found).To(BeFalse())
+			Expect(rc.Len()).To(Equal(1))
+		})
+	})
+
+	Describe("Len", func() {
+		It("should not count the overwritten results", func() {
+			rc.Save(1, parsley.Pos(1), &parsley.Result{})
+			rc.Save(1, parsley.Pos(1), &parsley.Result{})
+			rc.Save(2, parsley.Pos(1), &parsley.Result{})
+			Expect(rc.Len()).To(Equal(2))
+		})
+	})
+
+	Describe("ApplyEdit", func() {
+		var (
+			e                                     parsley.Edit
+			before, overlapping, after, curtailed *parsley.Result
+			untracked                             *parsley.Result
+			node                                  *shiftableNode
+			res                                   parsley.ResultCache
+		)
+
+		BeforeEach(func() {
+			e = parsley.Edit{Pos: 5, OldEnd: 7, NewEnd: 10}
+			before = &parsley.Result{CurtailingParsers: data.EmptyIntSet, ReadEnd: 5}
+			overlapping = &parsley.Result{CurtailingParsers: data.EmptyIntSet, ReadEnd: 6}
+			untracked = &parsley.Result{CurtailingParsers: data.EmptyIntSet}
+			node = &shiftableNode{pos: 7}
+			after = &parsley.Result{
+				CurtailingParsers: data.EmptyIntSet,
+				Error:             parsley.NewErrorf(parsley.Pos(8), "some error"),
+				Node:              node,
+				ReadEnd:           9,
+			}
+			curtailed = &parsley.Result{CurtailingParsers: data.NewIntSet(1), ReadEnd: 2}
+		})
+
+		JustBeforeEach(func() {
+			rc.Save(1, parsley.Pos(1), before)
+			rc.Save(1, parsley.Pos(2), overlapping)
+			rc.Save(1, parsley.Pos(3), untracked)
+			rc.Save(1, parsley.Pos(7), after)
+			rc.Save(2, parsley.Pos(1), curtailed)
+			res = rc.ApplyEdit(e)
+		})
+
+		It("should keep the results which were read before the edit", func() {
+			r, found := res.Get(1, parsley.Pos(1), data.EmptyIntMap)
+			Expect(found).To(BeTrue())
+			Expect(r).To(BeIdenticalTo(before))
+		})
+
+		It("should drop the results which read the edited input", func() {
+			_, found := res.Get(1, parsley.Pos(2), data.EmptyIntMap)
+			Expect(found).To(BeFalse())
+		})
+
+		It("should drop the results without a read position", func() {
+			_, found := res.Get(1, parsley.Pos(3), data.EmptyIntMap)
+			Expect(found).To(BeFalse())
+		})
+
+		It("should drop the curtailed results", func() {
+			_, found := res.Get(2, parsley.Pos(1), data.EmptyIntMap)
+			Expect(found).To(BeFalse())
+		})
+
+		It("should shift the results after the edit", func() {
+			_, found := res.Get(1, parsley.Pos(7), data.EmptyIntMap)
+			Expect(found).To(BeFalse())
+
+			r, found := res.Get(1, parsley.Pos(10), data.EmptyIntMap)
+			Expect(found).To(BeTrue())
+			Expect(r.Node).To(BeIdenticalTo(node))
+			Expect(node.Pos()).To(Equal(parsley.Pos(10)))
+			Expect(r.Error.Pos()).To(Equal(parsley.Pos(11)))
+			Expect(r.ReadEnd).To(Equal(parsley.Pos(12)))
+		})
+
+		It("should not modify the original cache", func() {
+			_, found := rc.Get(1, parsley.Pos(2), data.EmptyIntMap)
+			Expect(found).To(BeTrue())
+		})
+	})
+}
+
+func benchmarkResultCache(b *testing.B, newResultCache func() parsley.ResultCache) {
+	result := &parsley.Result{LeftRecCtx: data.EmptyIntMap}
+	for n := 0; n < b.N; n++ {
+		rc := newResultCache()
+		for pos := parsley.Pos(0); pos < 10000; pos++ {
+			for parserIndex := 1; parserIndex <= 3; parserIndex++ {
+				if _, found := rc.Get(parserIndex, pos, data.EmptyIntMap); !found {
+					rc.Save(parserIndex, pos, result)
+				}
+				_, _ = rc.Get(parserIndex, pos, data.EmptyIntMap)
+			}
+		}
+	}
+}
+
+func BenchmarkMapResultCache(b *testing.B)   { benchmarkResultCache(b, parsley.NewResultCache) }
+func BenchmarkDenseResultCache(b *testing.B) { benchmarkResultCache(b, parsley.NewDenseResultCache) }
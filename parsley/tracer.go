@@ -0,0 +1,58 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parsley
+
+import (
+	"sync/atomic"
+
+	"github.com/conflowio/parsley/data"
+)
+
+var nextTraceID int32
+
+// NewTraceID returns with a new unique id for a traced parser, see TraceResult.ID
+func NewTraceID() int {
+	return int(atomic.AddInt32(&nextTraceID, 1))
+}
+
+// Tracer is notified on the entry and exit of the parser calls, see Context.SetTracer
+// Every Enter call is followed by exactly one Exit call with the same name and position.
+type Tracer interface {
+	Enter(name string, pos Pos)
+	Exit(name string, pos Pos, result TraceResult)
+}
+
+// TraceResult contains the result of a traced parser call
+type TraceResult struct {
+	Node              Node
+	CurtailingParsers data.IntSet
+	Err               Error
+	// ID identifies the parser instance, as different parsers can be traced with the same name (e.g. CHOICE)
+	ID int
+	// Named is true if the name was given by the user (e.g. with parser.Func.Name or as the token of a sequence)
+	Named bool
+	// Memoized is true if the parser stores its results in the result cache (e.g. combinator.Memoize)
+	Memoized bool
+	// Cached is true if the result was returned from the result cache
+	Cached bool
+}
+
+// Trace calls the parser and reports the call to the context's tracer
+// The id should be created with NewTraceID when the parser is created. If there is no tracer then the parser is simply
+// called.
+func Trace(id int, name string, named bool, p Parser, ctx *Context, leftRecCtx data.IntMap, pos Pos) (Node, data.IntSet, Error) {
+	tracer := ctx.tracer
+	if tracer == nil {
+		return p.Parse(ctx, leftRecCtx, pos)
+	}
+
+	tracer.Enter(name, pos)
+	node, cp, err := p.Parse(ctx, leftRecCtx, pos)
+	tracer.Exit(name, pos, TraceResult{Node: node, CurtailingParsers: cp, Err: err, ID: id, Named: named})
+
+	return node, cp, err
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parsley_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/parsley/parsleyfakes"
+)
+
+var _ = Describe("Trace", func() {
+	var (
+		ctx  *parsley.Context
+		p    *parsleyfakes.FakeParser
+		node *parsleyfakes.FakeNode
+		cp   data.IntSet
+		err  parsley.Error
+	)
+
+	BeforeEach(func() {
+		ctx = parsley.NewContext(parsley.NewFileSet(), &parsleyfakes.FakeReader{})
+		p = &parsleyfakes.FakeParser{}
+		node = &parsleyfakes.FakeNode{}
+		cp = data.NewIntSet(1)
+		err = parsley.NewErrorf(3, "some error")
+		p.ParseReturns(node, cp, err)
+	})
+
+	It("should call the parser", func() {
+		leftRecCtx := data.NewIntMap(map[int]int{1: 2})
+		resNode, resCP, resErr := parsley.Trace(1, "P", false, p, ctx, leftRecCtx, 2)
+		Expect(resNode).To(BeIdenticalTo(node))
+		Expect(resCP).To(Equal(cp))
+		Expect(resErr).To(BeIdenticalTo(err))
+
+		Expect(p.ParseCallCount()).To(Equal(1))
+		passedCtx, passedLeftRecCtx, passedPos := p.ParseArgsForCall(0)
+		Expect(passedCtx).To(BeIdenticalTo(ctx))
+		Expect(passedLeftRecCtx).To(Equal(leftRecCtx))
+		Expect(passedPos).To(Equal(parsley.Pos(2)))
+	})
+
+	Context("when a tracer is set", func() {
+		var tracer *recordingTracer
+
+		BeforeEach(func() {
+			tracer = &recordingTracer{}
+			ctx.SetTracer(tracer)
+		})
+
+		It("should return the tracer", func() {
+			Expect(ctx.Tracer()).To(BeIdenticalTo(tracer))
+		})
+
+		It("should report the entry and the exit", func() {
+			p.ParseStub = func(*parsley.Context, data.IntMap, parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+				Expect(tracer.events).To(Equal([]string{"enter P 2"}))
+				return node, cp, err
+			}
+
+			_, _, _ = parsley.Trace(1, "P", true, p, ctx, data.EmptyIntMap, 2)
+
+			Expect(tracer.events).To(Equal([]string{"enter P 2", "exit P 2"}))
+			Expect(tracer.results).To(Equal([]parsley.TraceResult{
+				{Node: node, CurtailingParsers: cp, Err: err, ID: 1, Named: true},
+			}))
+		})
+	})
+})
+
+type recordingTracer struct {
+	events  []string
+	results []parsley.TraceResult
+}
+
+func (r *recordingTracer) Enter(name string, pos parsley.Pos) {
+	r.events = append(r.events, fmt.Sprintf("enter %s %d", name, pos))
+}
+
+func (r *recordingTracer) Exit(name string, pos parsley.Pos, result parsley.TraceResult) {
+	r.events = append(r.events, fmt.Sprintf("exit %s %d", name, pos))
+	r.results = append(r.results, result)
+}
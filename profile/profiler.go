@@ -0,0 +1,249 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package profile contains a parser profiler which collects statistics per parser name
+//
+// The profiler is a parsley.Tracer, so it has to be set on the context with parsley.Context.SetTracer.
+package profile
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+// DefaultMinRepeated is the default minimum number of repeated calls for a memoization suggestion
+const DefaultMinRepeated = 10
+
+// Stat contains the statistics of the parsers with the same name
+// The parsers are traced with a name, e.g. the token of a sequence, the name given by parser.Func.Name or
+// MEMOIZE#<index> for combinator.Memoize (MEMOIZE#<name> for the named parsers of a combinator.MemoRegistry).
+// Different parsers can have the same name (e.g. CHOICE), their statistics are added up.
+type Stat struct {
+	Name string
+	// Named is true if the name was given by the user, see parsley.TraceResult.Named
+	Named   bool
+	Calls   int
+	Matches int
+	// Time is the total time spent in the parser including the child parsers
+	// The time of the recursive calls is counted multiple times.
+	Time time.Duration
+	// SelfTime is the time spent in the parser excluding the child parsers
+	SelfTime time.Duration
+	// BacktrackedBytes is the input read by the failed calls, measured until the position of the error
+	BacktrackedBytes int
+	// Repeated is the number of calls at a position where the same parser instance was already called before
+	// The results returned from the result cache are not counted.
+	Repeated int
+	// RepeatedBytes is the input read again by the repeated calls
+	RepeatedBytes int
+	// Memoized is true if the parser stores its results in the result cache
+	Memoized    bool
+	CacheHits   int
+	CacheMisses int
+	// Terminal is true if the parser never called any other traced parsers
+	Terminal bool
+}
+
+// CacheHitRate returns with the ratio of the calls answered from the result cache
+func (s Stat) CacheHitRate() float64 {
+	if s.CacheHits+s.CacheMisses == 0 {
+		return 0
+	}
+	return float64(s.CacheHits) / float64(s.CacheHits+s.CacheMisses)
+}
+
+type frame struct {
+	name      string
+	start     time.Time
+	childTime time.Duration
+}
+
+// Profiler collects the parser statistics
+type Profiler struct {
+	stats       map[string]*Stat
+	positions   map[int]map[parsley.Pos]struct{}
+	stack       []frame
+	now         func() time.Time
+	minRepeated int
+}
+
+// New creates a new profiler
+func New() *Profiler {
+	return &Profiler{
+		stats:       map[string]*Stat{},
+		positions:   map[int]map[parsley.Pos]struct{}{},
+		now:         time.Now,
+		minRepeated: DefaultMinRepeated,
+	}
+}
+
+// MinRepeated sets the minimum number of repeated calls for a memoization suggestion
+func (p *Profiler) MinRepeated(n int) *Profiler {
+	p.minRepeated = n
+	return p
+}
+
+// Clock overrides the function used to measure the time
+func (p *Profiler) Clock(now func() time.Time) *Profiler {
+	p.now = now
+	return p
+}
+
+// Enter registers the start of a parser call
+func (p *Profiler) Enter(name string, pos parsley.Pos) {
+	if len(p.stack) > 0 {
+		p.stat(p.stack[len(p.stack)-1].name).Terminal = false
+	}
+	p.stack = append(p.stack, frame{name: name, start: p.now()})
+}
+
+// Exit registers the end of a parser call
+func (p *Profiler) Exit(name string, pos parsley.Pos, result parsley.TraceResult) {
+	s := p.stat(name)
+	s.Calls++
+	if result.Named {
+		s.Named = true
+	}
+
+	if n := len(p.stack); n > 0 {
+		f := p.stack[n-1]
+		p.stack = p.stack[:n-1]
+		elapsed := p.now().Sub(f.start)
+		s.Time += elapsed
+		s.SelfTime += elapsed - f.childTime
+		if n > 1 {
+			p.stack[n-2].childTime += elapsed
+		}
+	}
+
+	var read int
+	if result.Node != nil {
+		s.Matches++
+		read = int(result.Node.ReaderPos() - pos)
+	} else if result.Err != nil && result.Err.Pos() > pos {
+		read = int(result.Err.Pos() - pos)
+		s.BacktrackedBytes += read
+	}
+
+	if result.Memoized {
+		s.Memoized = true
+		if result.Cached {
+			s.CacheHits++
+		} else {
+			s.CacheMisses++
+		}
+	}
+
+	if result.Cached {
+		return
+	}
+
+	positions, ok := p.positions[result.ID]
+	if !ok {
+		positions = map[parsley.Pos]struct{}{}
+		p.positions[result.ID] = positions
+	}
+	if _, ok := positions[pos]; ok {
+		s.Repeated++
+		s.RepeatedBytes += read
+	} else {
+		positions[pos] = struct{}{}
+	}
+}
+
+func (p *Profiler) stat(name string) *Stat {
+	s, ok := p.stats[name]
+	if !ok {
+		s = &Stat{Name: name, Terminal: true}
+		p.stats[name] = s
+	}
+	return s
+}
+
+// Stats returns with the statistics sorted by the self time, the call count and the name
+func (p *Profiler) Stats() []Stat {
+	res := make([]Stat, 0, len(p.stats))
+	for _, s := range p.stats {
+		res = append(res, *s)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].SelfTime != res[j].SelfTime {
+			return res[i].SelfTime > res[j].SelfTime
+		}
+		if res[i].Calls != res[j].Calls {
+			return res[i].Calls > res[j].Calls
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// Suggestions returns with the parsers which would benefit from memoization
+// These are the named non-terminal parsers without memoization which were called at least MinRepeated times at a
+// position where they were already called before. The generic combinators and terminals (e.g. CHOICE) are not
+// suggested as they can't be identified in the grammar. The result is sorted by the repeatedly read input size.
+func (p *Profiler) Suggestions() []Stat {
+	var res []Stat
+	for _, s := range p.Stats() {
+		if s.Named && !s.Memoized && !s.Terminal && s.Repeated > 0 && s.Repeated >= p.minRepeated {
+			res = append(res, s)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].RepeatedBytes != res[j].RepeatedBytes {
+			return res[i].RepeatedBytes > res[j].RepeatedBytes
+		}
+		return res[i].Repeated > res[j].Repeated
+	})
+	return res
+}
+
+// WriteReport writes the statistics as a table followed by the memoization suggestions
+func (p *Profiler) WriteReport(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "PARSER\tCALLS\tMATCHES\tTIME\tSELF\tBACKTRACKED\tREPEATED\tCACHE HITS"); err != nil {
+		return err
+	}
+	for _, s := range p.Stats() {
+		cache := "-"
+		if s.Memoized {
+			cache = fmt.Sprintf("%d/%d (%.1f%%)", s.CacheHits, s.CacheHits+s.CacheMisses, 100*s.CacheHitRate())
+		}
+		if _, err := fmt.Fprintf(
+			tw, "%s\t%d\t%d\t%s\t%s\t%d\t%d\t%s\n",
+			s.Name, s.Calls, s.Matches, s.Time, s.SelfTime, s.BacktrackedBytes, s.Repeated, cache,
+		); err != nil {
+			return err
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	suggestions := p.Suggestions()
+	if len(suggestions) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintln(w, "\nMemoization suggestions:"); err != nil {
+		return err
+	}
+	for _, s := range suggestions {
+		if _, err := fmt.Fprintf(
+			w, "- %s was parsed again at the same position %d times (%d bytes), consider using combinator.Memoize\n",
+			s.Name, s.Repeated, s.RepeatedBytes,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package profile_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/profile"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+// fakeClock returns with a clock which advances one millisecond on every call
+func fakeClock() func() time.Time {
+	t := time.Time{}
+	return func() time.Time {
+		t = t.Add(time.Millisecond)
+		return t
+	}
+}
+
+// Let's profile a grammar where the value is parsed twice at the same position
+func Example() {
+	value := combinator.SeqOf(terminal.Integer("integer")).Token("VALUE")
+	p := combinator.Choice(
+		combinator.SeqOf(value, terminal.Op("+"), value).Token("SUM"),
+		value,
+	)
+
+	f := text.NewFile("example.file", []byte("1"))
+	ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+	profiler := profile.New().MinRepeated(1).Clock(fakeClock())
+	ctx.SetTracer(profiler)
+	if _, err := parsley.Parse(ctx, p); err != nil {
+		panic(err)
+	}
+
+	_ = profiler.WriteReport(os.Stdout)
+	// Output:
+	// PARSER   CALLS  MATCHES  TIME  SELF  BACKTRACKED  REPEATED  CACHE HITS
+	// VALUE    2      2        6ms   4ms   0            1         -
+	// CHOICE   1      1        13ms  3ms   0            0         -
+	// SUM      1      0        7ms   3ms   1            0         -
+	// INTEGER  2      2        2ms   2ms   0            1         -
+	// +        1      0        1ms   1ms   0            0         -
+	//
+	// Memoization suggestions:
+	// - VALUE was parsed again at the same position 1 times (1 bytes), consider using combinator.Memoize
+}
+
+var _ = Describe("Profiler", func() {
+
+	var (
+		profiler *profile.Profiler
+		ctx      *parsley.Context
+		f        *text.File
+	)
+
+	BeforeEach(func() {
+		profiler = profile.New().Clock(fakeClock())
+		f = text.NewFile("example.file", []byte("1"))
+		ctx = parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		ctx.SetTracer(profiler)
+	})
+
+	findStat := func(name string) profile.Stat {
+		for _, s := range profiler.Stats() {
+			if s.Name == name {
+				return s
+			}
+		}
+		Fail(fmt.Sprintf("no stats for %s", name))
+		return profile.Stat{}
+	}
+
+	It("should measure the total and the self time", func() {
+		p := combinator.SeqOf(terminal.Integer("integer")).Token("VALUE")
+		_, _, _ = p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+
+		Expect(findStat("VALUE").Time).To(Equal(3 * time.Millisecond))
+		Expect(findStat("VALUE").SelfTime).To(Equal(2 * time.Millisecond))
+		Expect(findStat("INTEGER").Time).To(Equal(time.Millisecond))
+		Expect(findStat("INTEGER").SelfTime).To(Equal(time.Millisecond))
+	})
+
+	It("should mark the parsers without traced children as terminals", func() {
+		p := combinator.SeqOf(terminal.Integer("integer")).Token("VALUE")
+		_, _, _ = p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+
+		Expect(findStat("VALUE").Terminal).To(BeFalse())
+		Expect(findStat("INTEGER").Terminal).To(BeTrue())
+	})
+
+	It("should count the cache hits and misses of the memoized parsers", func() {
+		p := combinator.NewMemoRegistry().MemoizeNamed("VALUE", terminal.Integer("integer"))
+		for i := 0; i < 3; i++ {
+			_, _, _ = p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		}
+
+		s := findStat("MEMOIZE#VALUE")
+		Expect(s.Memoized).To(BeTrue())
+		Expect(s.Calls).To(Equal(3))
+		Expect(s.CacheHits).To(Equal(2))
+		Expect(s.CacheMisses).To(Equal(1))
+		Expect(s.CacheHitRate()).To(BeNumerically("~", 2.0/3.0))
+		Expect(s.Repeated).To(Equal(0))
+		Expect(findStat("INTEGER").Calls).To(Equal(1))
+	})
+
+	It("should not suggest memoization below the minimum repeated calls", func() {
+		value := combinator.SeqOf(terminal.Integer("integer")).Token("VALUE")
+		p := combinator.Choice(combinator.SeqOf(value, terminal.Op("+")), value)
+		_, _, _ = p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+
+		Expect(findStat("VALUE").Repeated).To(Equal(1))
+		Expect(profiler.Suggestions()).To(BeEmpty())
+
+		profiler.MinRepeated(1)
+		Expect(profiler.Suggestions()).To(HaveLen(1))
+		Expect(profiler.Suggestions()[0].Name).To(Equal("VALUE"))
+	})
+
+	It("should count the repeated calls per parser instance", func() {
+		integer := terminal.Integer("integer")
+		p := combinator.Choice(
+			combinator.SeqOf(combinator.Choice(integer, terminal.Float("float")), terminal.Op("+")).Token("SUM"),
+			combinator.Choice(integer, terminal.Float("float")),
+		)
+		_, _, _ = p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+
+		Expect(findStat("CHOICE").Calls).To(Equal(3))
+		Expect(findStat("CHOICE").Repeated).To(Equal(0))
+		Expect(findStat("INTEGER").Repeated).To(Equal(1))
+
+		profiler.MinRepeated(1)
+		Expect(profiler.Suggestions()).To(BeEmpty())
+	})
+
+	It("should only suggest the named parsers", func() {
+		value := combinator.Choice(terminal.Integer("integer"), terminal.Float("float"))
+		p := combinator.Choice(combinator.SeqOf(value, terminal.Op("+")), value)
+		_, _, _ = p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+
+		Expect(findStat("CHOICE").Repeated).To(Equal(1))
+		Expect(findStat("CHOICE").Named).To(BeFalse())
+
+		profiler.MinRepeated(1)
+		Expect(profiler.Suggestions()).To(BeEmpty())
+	})
+
+	It("should not write the suggestions if there are none", func() {
+		_, _, _ = terminal.Integer("integer").Parse(ctx, data.EmptyIntMap, f.Pos(0))
+
+		buf := &bytes.Buffer{}
+		Expect(profiler.WriteReport(buf)).To(Succeed())
+		Expect(buf.String()).To(Equal(
+			"PARSER   CALLS  MATCHES  TIME  SELF  BACKTRACKED  REPEATED  CACHE HITS\n" +
+				"INTEGER  1      1        1ms   1ms   0            0         -\n",
+		))
+	})
+})
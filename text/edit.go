@@ -0,0 +1,121 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package text
+
+import (
+	"bytes"
+	"sort"
+)
+
+// minGapSize is the minimum free space allocated for insertions
+const minGapSize = 4096
+
+// gapMoveSize is the minimum number of bytes the gap is moved forward when the reader needs to read through it
+const gapMoveSize = 1024
+
+// NewEditableFile creates a new file object which is prepared for editing
+//
+// The file data is stored in a gap buffer, so small edits close to each other are cheap even for large files. The
+// reader keeps track of the read positions for an editable file so the cached results before an edit can be reused
+// (see parsley.ResultCache.ApplyEdit). The regular expression engine reads a few characters ahead, so results ending
+// right before an edit might be dropped. Any file can be edited, but for files created with NewFile the results before
+// the first edit are not reused.
+func NewEditableFile(filename string, data []byte) *File {
+	f := NewFile(filename, data)
+	f.editable = true
+	return f
+}
+
+// Edit replaces the data between the start and end offsets with the given data and returns with the length of the
+// inserted data (after replacing \r\n with \n)
+// If all the data is removed then the buffer is released.
+func (f *File) Edit(start, end int, data []byte) int {
+	if f.stream != nil {
+		panic("streamed files can not be edited")
+	}
+	if start < 0 || end < start || end > f.len {
+		panic("invalid edit range")
+	}
+
+	f.editable = true
+	data = bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)
+
+	f.moveGap(start)
+	f.gapEnd += end - start
+
+	if f.gapEnd-f.gapStart < len(data) {
+		f.growGap(len(data))
+	}
+	copy(f.data[f.gapStart:], data)
+	f.gapStart += len(data)
+	f.len += len(data) - (end - start)
+
+	if f.len == 0 {
+		f.data, f.gapStart, f.gapEnd = nil, 0, 0
+	}
+
+	if f.lines != nil {
+		f.editLines(start, end, data)
+	}
+
+	return len(data)
+}
+
+// moveGap moves the gap to the given offset
+func (f *File) moveGap(pos int) {
+	if pos > f.len {
+		pos = f.len
+	}
+
+	switch {
+	case pos < f.gapStart:
+		n := f.gapStart - pos
+		copy(f.data[f.gapEnd-n:f.gapEnd], f.data[pos:f.gapStart])
+		f.gapStart -= n
+		f.gapEnd -= n
+	case pos > f.gapStart:
+		n := pos - f.gapStart
+		copy(f.data[f.gapStart:], f.data[f.gapEnd:f.gapEnd+n])
+		f.gapStart += n
+		f.gapEnd += n
+	}
+}
+
+// growGap reallocates the data so the gap can hold at least n bytes
+func (f *File) growGap(n int) {
+	gapSize := n + f.len/8
+	if gapSize < minGapSize {
+		gapSize = minGapSize
+	}
+
+	data := make([]byte, f.len+gapSize)
+	copy(data, f.data[:f.gapStart])
+	after := f.data[f.gapEnd:]
+	copy(data[len(data)-len(after):], after)
+	f.gapEnd = len(data) - len(after)
+	f.data = data
+}
+
+// editLines updates the line offsets after an edit
+func (f *File) editLines(start, end int, data []byte) {
+	i := sort.Search(len(f.lines), func(i int) bool { return f.lines[i] > start })
+	j := sort.Search(len(f.lines), func(i int) bool { return f.lines[i] > end })
+
+	delta := len(data) - (end - start)
+	tail := make([]int, len(f.lines)-j)
+	for k, offset := range f.lines[j:] {
+		tail[k] = offset + delta
+	}
+
+	lines := f.lines[:i]
+	for k, b := range data {
+		if b == '\n' {
+			lines = append(lines, start+k+1)
+		}
+	}
+	f.lines = append(lines, tail...)
+}
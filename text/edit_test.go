@@ -0,0 +1,155 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package text_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+)
+
+var _ = Describe("Editable file", func() {
+
+	var (
+		fs *parsley.FileSet
+		f  *text.File
+		r  *text.Reader
+	)
+
+	content := func() string {
+		_, b := r.ReadRegexp(f.Pos(0), "(?s).+")
+		return string(b)
+	}
+
+	BeforeEach(func() {
+		f = text.NewEditableFile("testfile", []byte("ab\ncd\nef"))
+		fs = parsley.NewFileSet(f)
+		r = text.NewReader(f)
+	})
+
+	It("should implement the parsley.EditableFile interface", func() {
+		var _ parsley.EditableFile = &text.File{}
+	})
+
+	It("should replace the data", func() {
+		Expect(f.Edit(1, 4, []byte("XYZ"))).To(Equal(3))
+		Expect(content()).To(Equal("aXYZd\nef"))
+		Expect(f.Len()).To(Equal(8))
+	})
+
+	It("should insert and delete data", func() {
+		Expect(f.Edit(8, 8, []byte("gh"))).To(Equal(2))
+		Expect(f.Edit(0, 3, nil)).To(Equal(0))
+		Expect(content()).To(Equal("cd\nefgh"))
+		Expect(f.Len()).To(Equal(7))
+	})
+
+	It("should release the buffer if all the data is removed", func() {
+		f.Edit(0, 8, nil)
+		Expect(f.Len()).To(Equal(0))
+		Expect(f.Buffered()).To(Equal(0))
+
+		f.Edit(0, 0, []byte("xy"))
+		Expect(content()).To(Equal("xy"))
+	})
+
+	It("should replace \\r\\n with \\n", func() {
+		Expect(f.Edit(2, 3, []byte("\r\n\r\n"))).To(Equal(2))
+		Expect(content()).To(Equal("ab\n\ncd\nef"))
+	})
+
+	It("should update the line positions", func() {
+		Expect(f.Position(6)).To(Equal(text.NewPosition("testfile", 3, 1)))
+
+		f.Edit(1, 4, []byte("1\n2\n3"))
+
+		Expect(f.Position(0)).To(Equal(text.NewPosition("testfile", 1, 1)))
+		Expect(f.Position(3)).To(Equal(text.NewPosition("testfile", 2, 1)))
+		Expect(f.Position(5)).To(Equal(text.NewPosition("testfile", 3, 1)))
+		Expect(f.Position(7)).To(Equal(text.NewPosition("testfile", 3, 3)))
+		Expect(f.Position(8)).To(Equal(text.NewPosition("testfile", 4, 1)))
+	})
+
+	It("should handle multiple edits at different positions", func() {
+		f.Edit(6, 6, []byte("1"))
+		f.Edit(0, 0, []byte("2"))
+		f.Edit(5, 6, []byte("3"))
+		Expect(content()).To(Equal("2ab\nc3\n1ef"))
+		Expect(f.Position(7)).To(Equal(text.NewPosition("testfile", 3, 1)))
+	})
+
+	It("should be able to insert large amounts of data", func() {
+		large := strings.Repeat("x", 10000)
+		f.Edit(3, 3, []byte(large))
+		f.Edit(10005, 10005, []byte(large))
+		Expect(content()).To(Equal("ab\n" + large + "cd" + large + "\nef"))
+	})
+
+	It("should read across the gap", func() {
+		f.Edit(3, 3, []byte("12 "))
+
+		pos, match := r.ReadRegexp(f.Pos(2), "\n[0-9]+ cd")
+		Expect(string(match)).To(Equal("\n12 cd"))
+		Expect(pos).To(Equal(f.Pos(8)))
+
+		pos, found := r.MatchString(f.Pos(4), "2 c")
+		Expect(found).To(BeTrue())
+		Expect(pos).To(Equal(f.Pos(7)))
+	})
+
+	It("should panic for an invalid range", func() {
+		Expect(func() { f.Edit(2, 1, nil) }).To(Panic())
+		Expect(func() { f.Edit(0, 9, nil) }).To(Panic())
+	})
+
+	It("should track the read positions", func() {
+		r.SetReadEnd(f.Pos(0))
+
+		_, found := r.MatchString(f.Pos(0), "ab")
+		Expect(found).To(BeTrue())
+		Expect(r.ReadEnd()).To(Equal(f.Pos(2)))
+
+		// the regexp engine might read a few characters ahead, so the read end is only guaranteed to include the
+		// character after the match
+		_, match := r.ReadRegexp(f.Pos(3), "[a-z]+")
+		Expect(string(match)).To(Equal("cd"))
+		Expect(r.ReadEnd()).To(BeNumerically(">=", f.Pos(6)))
+
+		r.SetReadEnd(f.Pos(0))
+		Expect(r.IsEOF(f.Pos(8))).To(BeTrue())
+		Expect(r.ReadEnd()).To(Equal(f.Pos(9)))
+	})
+
+	Context("when created with NewFile", func() {
+		It("should be editable", func() {
+			f = text.NewFile("testfile", []byte("abc"))
+			parsley.NewFileSet(f)
+			r = text.NewReader(f)
+
+			f.Edit(1, 2, []byte("X"))
+			Expect(content()).To(Equal("aXc"))
+		})
+	})
+
+	Context("when used in a file set", func() {
+		It("should move the positions of the subsequent files", func() {
+			f2 := text.NewFile("testfile2", []byte("xy"))
+			fs.AddFile(f2)
+			Expect(f2.Pos(0)).To(Equal(parsley.Pos(10)))
+
+			e, err := fs.Edit(f.Pos(0), f.Pos(2), []byte("a"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(e).To(Equal(parsley.Edit{Pos: f.Pos(0), OldEnd: f.Pos(2), NewEnd: f.Pos(1)}))
+			Expect(f2.Pos(0)).To(Equal(parsley.Pos(9)))
+			Expect(fs.Position(f2.Pos(1)).String()).To(Equal("testfile2:1:2"))
+		})
+	})
+})
@@ -16,12 +16,19 @@ import (
 )
 
 // File contains the contents of a file and the line offsets for quick line+column lookup
+//
+// A file created with NewStreamFile only contains a sliding window of the input, see stream.go.
+// The data of an edited file contains a gap between gapStart and gapEnd, see edit.go.
 type File struct {
 	filename string
 	data     []byte
 	lines    []int
 	len      int
 	offset   int
+	stream   *stream
+	gapStart int
+	gapEnd   int
+	editable bool
 }
 
 // NewFile creates a new file object
@@ -32,6 +39,8 @@ func NewFile(filename string, data []byte) *File {
 		offset:   1,
 	}
 	f.len = len(f.data)
+	f.gapStart = f.len
+	f.gapEnd = f.len
 	return f
 }
 
@@ -47,37 +56,157 @@ func ReadFile(filename string) (*File, error) {
 // SetLinesForContent sets the line offsets
 func (f *File) setLines() {
 	f.lines = []int{0}
-	for offset, b := range f.data {
+	for offset, b := range f.data[:f.gapStart] {
 		if b == '\n' {
 			f.lines = append(f.lines, offset+1)
 		}
 	}
+	for offset, b := range f.data[f.gapEnd:] {
+		if b == '\n' {
+			f.lines = append(f.lines, f.gapStart+offset+1)
+		}
+	}
 }
 
 // Len returns with the length of the file in bytes
+// For streams the length is unknown until the end of the input is reached, so MaxStreamLen is returned.
 func (f *File) Len() int {
+	if f.stream != nil && !f.stream.eof {
+		return MaxStreamLen
+	}
 	return f.len
 }
 
+// Buffered returns with the number of bytes held in memory
+// For streams it only contains the input which wasn't discarded yet (see NewStreamFile).
+func (f *File) Buffered() int {
+	return len(f.data)
+}
+
 // SetOffset set the offset of this file related to a file set
 func (f *File) SetOffset(offset int) {
 	f.offset = offset
 }
 
+// Filename returns with the file name
+func (f *File) Filename() string {
+	return f.filename
+}
+
 // Position returns with a Position object for the given offset
 func (f *File) Position(pos int) parsley.Position {
-	if pos > f.len {
+	line, column, ok := f.LineColumn(pos)
+	if !ok {
 		return parsley.NilPosition
 	}
+	return &Position{
+		Filename: f.filename,
+		Line:     line,
+		Column:   column,
+	}
+}
+
+// LineColumn returns with the line and column for the given offset
+// The column is the byte offset in the line plus one.
+func (f *File) LineColumn(pos int) (int, int, bool) {
+	if pos > f.len {
+		return 0, 0, false
+	}
 	if f.lines == nil {
 		f.setLines()
 	}
+	if pos < f.lines[0] {
+		return 0, 0, false
+	}
 	i := sort.Search(len(f.lines), func(i int) bool { return f.lines[i] > pos }) - 1
-	return &Position{
-		Filename: f.filename,
-		Line:     i + 1,
-		Column:   pos - f.lines[i] + 1,
+	line := i + 1
+	if f.stream != nil {
+		line += f.stream.lineBase
+	}
+	return line, pos - f.lines[i] + 1, true
+}
+
+// Offset returns with the offset for the given line and column
+// The column can point after the last character of the line. It returns false for invalid positions and for
+// the lines which were already discarded from a stream.
+func (f *File) Offset(line int, column int) (int, bool) {
+	if f.lines == nil {
+		f.setLines()
+	}
+	i := line - 1
+	if f.stream != nil {
+		i -= f.stream.lineBase
 	}
+	if i < 0 || i >= len(f.lines) || column < 1 {
+		return 0, false
+	}
+
+	end := f.len
+	if i+1 < len(f.lines) {
+		end = f.lines[i+1] - 1
+	}
+
+	offset := f.lines[i] + column - 1
+	if offset > end {
+		return 0, false
+	}
+	return offset, true
+}
+
+// Line returns with the contents of the given line without the new line character
+// It returns false if the line doesn't exist or if it's not available any more (e.g. discarded from a stream).
+func (f *File) Line(line int) ([]byte, bool) {
+	if f.lines == nil {
+		f.setLines()
+	}
+	i := line - 1
+	if f.stream != nil {
+		i -= f.stream.lineBase
+	}
+	if i < 0 || i >= len(f.lines) || f.lines[i] > f.len {
+		return nil, false
+	}
+
+	start := f.lines[i]
+	end := f.len
+	if i+1 < len(f.lines) {
+		end = f.lines[i+1] - 1
+	}
+
+	data := f.peek(start, end-start)
+	if data == nil || len(data) < end-start {
+		return nil, false
+	}
+	return data[:end-start], true
+}
+
+// peek returns with the data starting at the cur offset, loading at least n bytes if the input is streamed
+// It returns nil if the data at the cur offset is not available.
+func (f *File) peek(cur int, n int) []byte {
+	if f.stream != nil {
+		return f.stream.peek(f, cur, n)
+	}
+	if cur > f.len {
+		return nil
+	}
+	if cur >= f.gapStart {
+		return f.data[cur+f.gapEnd-f.gapStart:]
+	}
+	if cur+n > f.gapStart && f.gapEnd < len(f.data) {
+		f.moveGap(cur + n + gapMoveSize)
+	}
+	return f.data[cur:f.gapStart]
+}
+
+// segmented returns true if peek doesn't return with all the remaining data
+// It also means that the read positions are tracked precisely as regular expressions are matched rune by rune.
+func (f *File) segmented() bool {
+	return f.stream != nil || f.editable
+}
+
+// hasMore returns true if there is more data after the given offset
+func (f *File) hasMore(end int) bool {
+	return end < f.len || f.stream != nil && !f.stream.eof
 }
 
 // Pos returns with a global offset in a file set
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
 	"github.com/conflowio/parsley/parsley"
@@ -39,6 +40,54 @@ var _ = Describe("File", func() {
 		var _ parsley.File = &text.File{}
 	})
 
+	It("should implement the parsley.LineFile interface", func() {
+		var _ parsley.LineFile = &text.File{}
+	})
+
+	Describe("LineColumn()", func() {
+		It("should return with the line and column for an offset", func() {
+			line, column, ok := f.LineColumn(3)
+			Expect(ok).To(BeTrue())
+			Expect(line).To(Equal(2))
+			Expect(column).To(Equal(1))
+		})
+
+		It("should return false for an invalid offset", func() {
+			_, _, ok := f.LineColumn(5)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("Offset()", func() {
+		It("should return with the offset for a line and column", func() {
+			offset, ok := f.Offset(1, 3)
+			Expect(ok).To(BeTrue())
+			Expect(offset).To(Equal(2))
+
+			offset, ok = f.Offset(2, 2)
+			Expect(ok).To(BeTrue())
+			Expect(offset).To(Equal(4))
+		})
+
+		DescribeTable("should return false for an invalid position",
+			func(line, column int) {
+				_, ok := f.Offset(line, column)
+				Expect(ok).To(BeFalse())
+			},
+			Entry("zero line", 0, 1),
+			Entry("zero column", 1, 0),
+			Entry("column after the line end", 1, 4),
+			Entry("column after the file end", 2, 3),
+			Entry("line after the file end", 3, 1),
+		)
+	})
+
+	Describe("Filename()", func() {
+		It("should return with the file name", func() {
+			Expect(f.Filename()).To(Equal("testfile"))
+		})
+	})
+
 	Describe("Position()", func() {
 		It("should return with the position for an offset", func() {
 			Expect(f.Position(0)).To(Equal(text.NewPosition("testfile", 1, 1)))
@@ -53,6 +102,36 @@ var _ = Describe("File", func() {
 		})
 	})
 
+	Describe("Line()", func() {
+		It("should return with the contents of the line", func() {
+			line, ok := f.Line(1)
+			Expect(ok).To(BeTrue())
+			Expect(string(line)).To(Equal("ab"))
+
+			line, ok = f.Line(2)
+			Expect(ok).To(BeTrue())
+			Expect(string(line)).To(Equal("c"))
+		})
+
+		It("should return false for an invalid line", func() {
+			_, ok := f.Line(0)
+			Expect(ok).To(BeFalse())
+
+			_, ok = f.Line(3)
+			Expect(ok).To(BeFalse())
+		})
+
+		Context("when the file was edited", func() {
+			It("should return with the new contents", func() {
+				f := text.NewEditableFile("testfile", []byte("ab\nc"))
+				f.Edit(1, 1, []byte("xx\ny"))
+				line, ok := f.Line(2)
+				Expect(ok).To(BeTrue())
+				Expect(string(line)).To(Equal("yb"))
+			})
+		})
+	})
+
 	Describe("Len()", func() {
 		Context("when data is empty", func() {
 			BeforeEach(func() {
@@ -10,11 +10,15 @@ import (
 	"bytes"
 	"fmt"
 	"regexp"
+	"strconv"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/conflowio/parsley/parsley"
 )
 
+const maxDescribedWordLength = 20
+
 var (
 	wsNoneErr          = parsley.NewWhitespaceError("whitespaces are not allowed")
 	wsSpacesForceNlErr = parsley.NewWhitespaceError("was expecting a new line")
@@ -26,6 +30,7 @@ var (
 type Reader struct {
 	file        *File
 	regexpCache map[string]*regexp.Regexp
+	readEnd     int
 }
 
 // NewReader creates a new reader instance
@@ -40,16 +45,22 @@ func NewReader(file *File) *Reader {
 // ReadRune matches the given rune
 func (r *Reader) ReadRune(pos parsley.Pos, ch rune) (parsley.Pos, bool) { // nolint
 	cur := int(pos) - r.file.offset
-	if cur >= r.file.len {
+	n := utf8.UTFMax
+	if ch < utf8.RuneSelf {
+		n = 1
+	}
+
+	b := r.peek(cur, n)
+	if len(b) == 0 {
 		return pos, false
 	}
 
 	if ch < utf8.RuneSelf {
-		if int8(ch) == int8(r.file.data[cur]) {
+		if int8(ch) == int8(b[0]) {
 			return r.file.Pos(cur + 1), true
 		}
 	} else {
-		nextRune, width := utf8.DecodeRune(r.file.data[cur:])
+		nextRune, width := utf8.DecodeRune(b)
 		if nextRune == ch {
 			return r.file.Pos(cur + width), true
 		}
@@ -65,12 +76,13 @@ func (r *Reader) MatchString(pos parsley.Pos, str string) (parsley.Pos, bool) {
 	}
 
 	cur := int(pos) - r.file.offset
+	b := r.peek(cur, len(str))
 
-	if len(str) > len(r.file.data)-cur {
+	if len(str) > len(b) {
 		return pos, false
 	}
 
-	if bytes.HasPrefix(r.file.data[cur:], []byte(str)) {
+	if bytes.HasPrefix(b, []byte(str)) {
 		return r.file.Pos(cur + len(str)), true
 	}
 	return pos, false
@@ -84,8 +96,9 @@ func (r *Reader) MatchWord(pos parsley.Pos, word string) (parsley.Pos, bool) {
 	}
 
 	cur := int(pos) - r.file.offset
+	data := r.peek(cur, len(word)+1)
 
-	if len(word) > len(r.file.data)-cur {
+	if len(word) > len(data) {
 		return pos, false
 	}
 
@@ -93,12 +106,12 @@ func (r *Reader) MatchWord(pos parsley.Pos, word string) (parsley.Pos, bool) {
 		if b >= utf8.RuneSelf {
 			panic("MatchWord() should not be called with UTF8 strings")
 		}
-		if b != r.file.data[cur+i] {
+		if b != data[i] {
 			return pos, false
 		}
 	}
 
-	if len(r.file.data)-cur-len(word) == 0 || !isWordCharacter(r.file.data[cur+len(word)]) {
+	if len(data) == len(word) || !isWordCharacter(data[len(word)]) {
 		return r.file.Pos(cur + len(word)), true
 	}
 	return pos, false
@@ -108,29 +121,56 @@ func (r *Reader) MatchWord(pos parsley.Pos, word string) (parsley.Pos, bool) {
 // and returns with the full match
 func (r *Reader) ReadRegexp(pos parsley.Pos, expr string) (parsley.Pos, []byte) {
 	cur := int(pos) - r.file.offset
+	b := r.peek(cur, 1)
 
-	if cur >= r.file.len {
+	if len(b) == 0 {
 		return pos, nil
 	}
 
-	indices := r.getPattern(expr).FindIndex(r.file.data[cur:])
+	var indices []int
+	if r.file.segmented() {
+		indices = r.getPattern(expr).FindReaderIndex(&runeReader{reader: r, cur: cur})
+		if indices != nil {
+			b = r.peek(cur, indices[1])
+		}
+	} else {
+		indices = r.getPattern(expr).FindIndex(b)
+		r.markRead(cur + len(b))
+	}
 	if indices == nil {
 		return pos, nil
 	}
 
-	return r.file.Pos(cur + indices[1]), r.file.data[cur : cur+indices[1]]
+	return r.file.Pos(cur + indices[1]), b[:indices[1]]
 }
 
 // ReadRegexpSubmatch matches part of the input based on the given regular expression
 // and returns with all capturing groups
 func (r *Reader) ReadRegexpSubmatch(pos parsley.Pos, expr string) (parsley.Pos, [][]byte) {
 	cur := int(pos) - r.file.offset
+	b := r.peek(cur, 1)
 
-	if cur >= r.file.len {
+	if len(b) == 0 {
 		return pos, nil
 	}
 
-	matches := r.getPattern(expr).FindSubmatch(r.file.data[cur:])
+	var matches [][]byte
+	if r.file.segmented() {
+		indices := r.getPattern(expr).FindReaderSubmatchIndex(&runeReader{reader: r, cur: cur})
+		if indices == nil {
+			return pos, nil
+		}
+		b = r.peek(cur, indices[1])
+		matches = make([][]byte, len(indices)/2)
+		for i := range matches {
+			if indices[2*i] >= 0 {
+				matches[i] = b[indices[2*i]:indices[2*i+1]]
+			}
+		}
+	} else {
+		matches = r.getPattern(expr).FindSubmatch(b)
+		r.markRead(cur + len(b))
+	}
 	if matches == nil {
 		return pos, nil
 	}
@@ -139,36 +179,48 @@ func (r *Reader) ReadRegexpSubmatch(pos parsley.Pos, expr string) (parsley.Pos,
 }
 
 // Readf uses the given function to match the next token
+// For streamed or edited files the function is called again with more input if it consumed all the available data.
+// The function shouldn't examine more than one byte after the returned position.
 func (r *Reader) Readf(pos parsley.Pos, f func(b []byte) ([]byte, int)) (parsley.Pos, []byte) {
 	cur := int(pos) - r.file.offset
+	b := r.peek(cur, 1)
 
-	if cur >= r.file.len {
+	if len(b) == 0 {
 		return pos, nil
 	}
 
-	value, nextPos := f(r.file.data[cur:])
+	value, nextPos := f(b)
+	for nextPos == len(b) && r.file.hasMore(cur+len(b)) {
+		b = r.peek(cur, 2*len(b))
+		value, nextPos = f(b)
+	}
+
 	if nextPos == 0 {
+		r.markRead(cur + len(b))
 		if value != nil {
 			panic("no value should be returned if next position is zero")
 		}
 		return pos, nil
 	}
 
-	if nextPos < len(value) || cur+nextPos > r.file.len {
+	if nextPos < len(value) || nextPos > len(b) {
 		panic("invalid length was returned by the custom reader function")
 	}
 
+	r.markRead(cur + nextPos + 1)
+
 	return r.file.Pos(cur + nextPos), value
 }
 
 // Remaining returns with the remaining character count
+// For streams the length is unknown until the end of the input is reached, so it's counted from MaxStreamLen.
 func (r *Reader) Remaining(pos parsley.Pos) int {
-	return r.file.len - (int(pos) - r.file.offset)
+	return r.file.Len() - (int(pos) - r.file.offset)
 }
 
 // IsEOF returns true if we reached the end of the buffer
 func (r *Reader) IsEOF(pos parsley.Pos) bool {
-	return int(pos)-r.file.offset >= r.file.len
+	return len(r.peek(int(pos)-r.file.offset, 1)) == 0
 }
 
 // SkipWhitespaces skips all the whitespaces and returns true if it only encountered the required whitespace characters
@@ -176,12 +228,20 @@ func (r *Reader) SkipWhitespaces(pos parsley.Pos, wsMode WsMode) (parsley.Pos, p
 	cur := int(pos) - r.file.offset
 
 	var nlPos parsley.Pos
-	for cur < r.file.len && (r.file.data[cur] == ' ' || r.file.data[cur] == '\t' || r.file.data[cur] == '\n' || r.file.data[cur] == '\f') {
-		if (r.file.data[cur] == '\n' || r.file.data[cur] == '\f') && nlPos == 0 {
-			nlPos = r.file.Pos(cur)
+	for b := r.peek(cur, 1); len(b) > 0; b = r.peek(cur, 1) {
+		i := 0
+		for i < len(b) && isWhitespaceCharacter(b[i]) {
+			if (b[i] == '\n' || b[i] == '\f') && nlPos == 0 {
+				nlPos = r.file.Pos(cur + i)
+			}
+			i++
+		}
+		cur += i
+		if i < len(b) {
+			break
 		}
-		cur++
 	}
+	r.markRead(cur + 1)
 
 	switch {
 	case wsMode == WsNone && cur > int(pos)-r.file.offset:
@@ -195,11 +255,102 @@ func (r *Reader) SkipWhitespaces(pos parsley.Pos, wsMode WsMode) (parsley.Pos, p
 	return r.file.Pos(cur), nil
 }
 
+// DescribeInput returns with a short description of the input at the given position for error messages
+// Any whitespaces are skipped first. If the next character is a word character then the whole word is returned.
+func (r *Reader) DescribeInput(pos parsley.Pos) string {
+	pos, _ = r.SkipWhitespaces(pos, WsSpacesNl)
+	cur := int(pos) - r.file.offset
+
+	b := r.peek(cur, maxDescribedWordLength)
+	if len(b) == 0 {
+		return "end of input"
+	}
+
+	if isWordCharacter(b[0]) {
+		end := 1
+		for end < len(b) && end < maxDescribedWordLength && isWordCharacter(b[end]) {
+			end++
+		}
+		return strconv.Quote(string(b[:end]))
+	}
+
+	ch, _ := utf8.DecodeRune(b)
+	return strconv.Quote(string(ch))
+}
+
+// DescribeMatch returns with the quoted input between the start and end positions without the trailing whitespaces
+// Long inputs are truncated.
+func (r *Reader) DescribeMatch(start parsley.Pos, end parsley.Pos) string {
+	n := int(end - start)
+	if n > maxDescribedWordLength {
+		n = maxDescribedWordLength
+	}
+
+	b := r.peek(int(start)-r.file.offset, n)
+	if len(b) > n {
+		b = b[:n]
+	}
+	for len(b) > 0 && !utf8.Valid(b) {
+		b = b[:len(b)-1]
+	}
+	b = bytes.TrimRightFunc(b, unicode.IsSpace)
+	if len(b) == 0 {
+		return r.DescribeInput(start)
+	}
+
+	return strconv.Quote(string(b))
+}
+
+// Discard allows a streamed file to drop the input before the given position
+func (r *Reader) Discard(pos parsley.Pos) {
+	if r.file.stream != nil {
+		r.file.stream.discard(int(pos) - r.file.offset)
+	}
+}
+
+// AutoDiscard returns true for streamed files, so the input is discarded before the outermost backtrack point
+func (r *Reader) AutoDiscard() bool {
+	return r.file.stream != nil
+}
+
+// Err returns with the error if a streamed file couldn't be read or the parser tried to read discarded input
+func (r *Reader) Err() error {
+	if r.file.stream != nil {
+		return r.file.stream.err
+	}
+	return nil
+}
+
+// ReadEnd returns with the position after the furthest byte read since the last SetReadEnd call
+func (r *Reader) ReadEnd() parsley.Pos {
+	return r.file.Pos(r.readEnd)
+}
+
+// SetReadEnd resets the read tracking to the given position
+func (r *Reader) SetReadEnd(pos parsley.Pos) {
+	r.readEnd = int(pos) - r.file.offset
+}
+
 // Pos returns with the global position for the given cursor
 func (r *Reader) Pos(cur int) parsley.Pos {
 	return r.file.Pos(cur)
 }
 
+// peek returns with the available data at the cur offset (see File.peek) and records that n bytes were read
+func (r *Reader) peek(cur int, n int) []byte {
+	if cur+n > r.readEnd {
+		r.readEnd = cur + n
+	}
+	return r.file.peek(cur, n)
+}
+
+// markRead records that the input was read until the given offset
+func (r *Reader) markRead(end int) {
+	if end > r.readEnd {
+		r.readEnd = end
+	}
+}
+
 func (r *Reader) getPattern(expr string) *regexp.Regexp {
 	rc, ok := r.regexpCache[expr]
 	if !ok {
@@ -214,6 +365,10 @@ func (r *Reader) getPattern(expr string) *regexp.Regexp {
 	return rc
 }
 
+func isWhitespaceCharacter(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\f'
+}
+
 func isWordCharacter(b byte) bool {
 	return 'a' <= b && b <= 'z' ||
 		'A' <= b && b <= 'Z' ||
@@ -461,6 +461,66 @@ var _ = Describe("Reader", func() {
 		})
 	})
 
+	Describe("DescribeInput()", func() {
+		It("should return the next word", func() {
+			Expect(r.DescribeInput(f.Pos(0))).To(Equal(`"abc"`))
+			Expect(r.DescribeInput(f.Pos(1))).To(Equal(`"bc"`))
+		})
+
+		It("should skip the whitespaces", func() {
+			Expect(r.DescribeInput(f.Pos(3))).To(Equal(`"def"`))
+		})
+
+		It("should return end of input at the end", func() {
+			Expect(r.DescribeInput(f.Pos(7))).To(Equal("end of input"))
+		})
+
+		Context("When the next character is not a word character", func() {
+			BeforeEach(func() {
+				data = []byte(inputWithUTF8)
+			})
+
+			It("should return the next character", func() {
+				Expect(r.DescribeInput(f.Pos(0))).To(Equal(`"🍕"`))
+			})
+		})
+
+		Context("When the next word is long", func() {
+			BeforeEach(func() {
+				data = []byte("abcdefghijklmnopqrstuvwxyz")
+			})
+
+			It("should return only the beginning of the word", func() {
+				Expect(r.DescribeInput(f.Pos(0))).To(Equal(`"abcdefghijklmnopqrst"`))
+			})
+		})
+	})
+
+	Describe("DescribeMatch()", func() {
+		It("should return the matched input", func() {
+			Expect(r.DescribeMatch(f.Pos(0), f.Pos(1))).To(Equal(`"a"`))
+			Expect(r.DescribeMatch(f.Pos(1), f.Pos(3))).To(Equal(`"bc"`))
+		})
+
+		It("should not include the trailing whitespaces", func() {
+			Expect(r.DescribeMatch(f.Pos(0), f.Pos(4))).To(Equal(`"abc"`))
+		})
+
+		It("should describe the input if the match is empty", func() {
+			Expect(r.DescribeMatch(f.Pos(1), f.Pos(1))).To(Equal(`"bc"`))
+		})
+
+		Context("When the match is long", func() {
+			BeforeEach(func() {
+				data = []byte("abcdefghijklmnopqrstuvwxyz")
+			})
+
+			It("should return only the beginning of the match", func() {
+				Expect(r.DescribeMatch(f.Pos(0), f.Pos(26))).To(Equal(`"abcdefghijklmnopqrst"`))
+			})
+		})
+	})
+
 	Describe("SkipWhitespaces()", func() {
 		BeforeEach(func() {
 			data = []byte("abc \t\n\fdef  ")
@@ -0,0 +1,200 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package text
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+// DefaultTabWidth is the default number of columns a tab character is expanded to in the snippets
+const DefaultTabWidth = 4
+
+const (
+	ansiGutter = "\x1b[34m"
+	ansiMarker = "\x1b[1;31m"
+	ansiReset  = "\x1b[0m"
+)
+
+// SnippetFormatter is an error formatter which prints the line of the error with a caret under the error column
+//
+// The output looks like this:
+//
+//	was expecting "]", found "b" at example.file:1:4
+//	1 | [a b]
+//	  |    ^
+//
+// Tabs are expanded to spaces and every rune is counted as a single column.
+// If the error position is not in a text file then the error is formatted by parsley.FileSet.ErrorWithPosition.
+type SnippetFormatter struct {
+	contextLines int
+	tabWidth     int
+	underline    bool
+	color        bool
+}
+
+// NewSnippetFormatter creates a new snippet formatter
+func NewSnippetFormatter() *SnippetFormatter {
+	return &SnippetFormatter{
+		tabWidth: DefaultTabWidth,
+	}
+}
+
+// ContextLines sets how many lines are displayed before and after the line of the error
+func (s *SnippetFormatter) ContextLines(n int) *SnippetFormatter {
+	s.contextLines = n
+	return s
+}
+
+// TabWidth sets the tab stop distance used for expanding the tabs
+func (s *SnippetFormatter) TabWidth(n int) *SnippetFormatter {
+	s.tabWidth = n
+	return s
+}
+
+// Underline will underline the whole word at the error position instead of only marking the first character
+func (s *SnippetFormatter) Underline(enabled bool) *SnippetFormatter {
+	s.underline = enabled
+	return s
+}
+
+// Color will highlight the line numbers and the marker with ANSI escape codes
+func (s *SnippetFormatter) Color(enabled bool) *SnippetFormatter {
+	s.color = enabled
+	return s
+}
+
+// FormatError returns with the error message, the position and the source snippet
+func (s *SnippetFormatter) FormatError(fs *parsley.FileSet, err parsley.Error) error {
+	f, _ := fs.File(err.Pos())
+	file, ok := f.(*File)
+	if !ok {
+		return fs.ErrorWithPosition(err)
+	}
+
+	pos, ok := fs.Position(err.Pos()).(*Position)
+	if !ok {
+		return fs.ErrorWithPosition(err)
+	}
+
+	line, ok := file.Line(pos.Line)
+	if !ok {
+		return fs.ErrorWithPosition(err)
+	}
+
+	first, last := pos.Line, pos.Line
+	for first > 1 && first > pos.Line-s.contextLines {
+		if _, ok := file.Line(first - 1); !ok {
+			break
+		}
+		first--
+	}
+	for last < pos.Line+s.contextLines {
+		if _, ok := file.Line(last + 1); !ok {
+			break
+		}
+		last++
+	}
+
+	gutterWidth := len(fmt.Sprint(last))
+
+	var sb strings.Builder
+	for i := first; i <= last; i++ {
+		content := line
+		if i != pos.Line {
+			content, _ = file.Line(i)
+		}
+		s.writeLine(&sb, fmt.Sprintf("%*d", gutterWidth, i), s.expandTabs(content))
+
+		if i == pos.Line {
+			col := pos.Column - 1
+			if col > len(line) {
+				col = len(line)
+			}
+			marker := "^"
+			if s.underline {
+				if n := wordLen(line[col:]); n > 1 {
+					marker += strings.Repeat("~", n-1)
+				}
+			}
+			indent := strings.Repeat(" ", utf8.RuneCountInString(s.expandTabs(line[:col])))
+			if s.color {
+				marker = ansiMarker + marker + ansiReset
+			}
+			s.writeLine(&sb, strings.Repeat(" ", gutterWidth), indent+marker)
+		}
+	}
+
+	return &snippetError{
+		err:     err,
+		msg:     fs.ErrorWithPosition(err).Error(),
+		snippet: strings.TrimSuffix(sb.String(), "\n"),
+	}
+}
+
+func (s *SnippetFormatter) writeLine(sb *strings.Builder, gutter string, content string) {
+	if s.color {
+		gutter = ansiGutter + gutter + " |" + ansiReset
+	} else {
+		gutter += " |"
+	}
+	sb.WriteString(gutter)
+	if content != "" {
+		sb.WriteByte(' ')
+		sb.WriteString(content)
+	}
+	sb.WriteByte('\n')
+}
+
+func (s *SnippetFormatter) expandTabs(line []byte) string {
+	var sb strings.Builder
+	col := 0
+	for _, r := range string(line) {
+		if r == '\t' && s.tabWidth > 0 {
+			n := s.tabWidth - col%s.tabWidth
+			sb.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		sb.WriteRune(r)
+		col++
+	}
+	return sb.String()
+}
+
+// wordLen returns with the number of runes until the first whitespace
+func wordLen(b []byte) int {
+	n := 0
+	for _, r := range string(b) {
+		if unicode.IsSpace(r) {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// snippetError is an error with a source snippet
+type snippetError struct {
+	err     parsley.Error
+	msg     string
+	snippet string
+}
+
+// Error returns with the error message and position followed by the snippet
+func (e *snippetError) Error() string {
+	return e.msg + "\n" + e.snippet
+}
+
+// Unwrap returns with the original error
+func (e *snippetError) Unwrap() error {
+	return e.err
+}
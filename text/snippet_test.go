@@ -0,0 +1,177 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package text_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/parsley/parsleyfakes"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+// Let's display the errors with the source line
+func ExampleSnippetFormatter() {
+	p := combinator.SeqOf(terminal.Rune('['), terminal.Integer("int"), terminal.Rune(']'))
+
+	f := text.NewFile("example.file", []byte("[1 2]"))
+	ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+	ctx.SetErrorFormatter(text.NewSnippetFormatter())
+
+	_, err := parsley.Parse(ctx, p)
+	fmt.Println(err)
+	// Output:
+	// failed to parse the input: was expecting "]", found "2" at example.file:1:3
+	// 1 | [1 2]
+	//   |   ^
+}
+
+var _ = Describe("SnippetFormatter", func() {
+
+	var (
+		formatter *text.SnippetFormatter
+		data      []byte
+		fs        *parsley.FileSet
+		f         *text.File
+		offset    int
+		err       error
+	)
+
+	BeforeEach(func() {
+		formatter = text.NewSnippetFormatter()
+		data = []byte("first\nsecond line\nthird\nfourth")
+		offset = 13
+	})
+
+	JustBeforeEach(func() {
+		f = text.NewFile("testfile", data)
+		fs = parsley.NewFileSet(f)
+		err = formatter.FormatError(fs, parsley.NewError(f.Pos(offset), errors.New("some error")))
+	})
+
+	It("should display the line with a caret under the error column", func() {
+		Expect(err).To(MatchError("some error at testfile:2:8\n" +
+			"2 | second line\n" +
+			"  |        ^"))
+	})
+
+	It("should keep the original error", func() {
+		var parsleyErr parsley.Error
+		Expect(errors.As(err, &parsleyErr)).To(BeTrue())
+		Expect(parsleyErr.Pos()).To(Equal(f.Pos(offset)))
+	})
+
+	Context("when context lines are requested", func() {
+		BeforeEach(func() {
+			formatter.ContextLines(1)
+		})
+
+		It("should display the lines around the error", func() {
+			Expect(err).To(MatchError("some error at testfile:2:8\n" +
+				"1 | first\n" +
+				"2 | second line\n" +
+				"  |        ^\n" +
+				"3 | third"))
+		})
+	})
+
+	Context("when there are less context lines available", func() {
+		BeforeEach(func() {
+			formatter.ContextLines(5)
+			offset = 0
+		})
+
+		It("should display the available lines", func() {
+			Expect(err).To(MatchError("some error at testfile:1:1\n" +
+				"1 | first\n" +
+				"  | ^\n" +
+				"2 | second line\n" +
+				"3 | third\n" +
+				"4 | fourth"))
+		})
+	})
+
+	Context("when underlining is enabled", func() {
+		BeforeEach(func() {
+			formatter.Underline(true)
+		})
+
+		It("should underline the word at the error position", func() {
+			Expect(err).To(MatchError("some error at testfile:2:8\n" +
+				"2 | second line\n" +
+				"  |        ^~~~"))
+		})
+	})
+
+	Context("when the line contains tabs and multi-byte characters", func() {
+		BeforeEach(func() {
+			data = []byte("\tá\tb")
+			offset = 4
+		})
+
+		It("should align the caret with the expanded line", func() {
+			Expect(err).To(MatchError("some error at testfile:1:5\n" +
+				"1 |     á   b\n" +
+				"  |         ^"))
+		})
+
+		Context("when the tab width is changed", func() {
+			BeforeEach(func() {
+				formatter.TabWidth(2)
+			})
+
+			It("should use the new tab width", func() {
+				Expect(err).To(MatchError("some error at testfile:1:5\n" +
+					"1 |   á b\n" +
+					"  |     ^"))
+			})
+		})
+	})
+
+	Context("when the error is at the end of the input", func() {
+		BeforeEach(func() {
+			data = []byte("abc")
+			offset = 3
+		})
+
+		It("should put the caret after the last character", func() {
+			Expect(err).To(MatchError("some error at testfile:1:4\n" +
+				"1 | abc\n" +
+				"  |    ^"))
+		})
+	})
+
+	Context("when colors are enabled", func() {
+		BeforeEach(func() {
+			formatter.Color(true)
+		})
+
+		It("should add ANSI escape codes", func() {
+			Expect(err).To(MatchError("some error at testfile:2:8\n" +
+				"\x1b[34m2 |\x1b[0m second line\n" +
+				"\x1b[34m  |\x1b[0m        \x1b[1;31m^\x1b[0m"))
+		})
+	})
+
+	Context("when the error is not in a text file", func() {
+		It("should only add the position", func() {
+			ff := &parsleyfakes.FakeFile{}
+			ff.LenReturns(10)
+			position := &parsleyfakes.FakePosition{}
+			position.StringReturns("testpos")
+			ff.PositionReturns(position)
+			err := formatter.FormatError(parsley.NewFileSet(ff), parsley.NewError(parsley.Pos(1), errors.New("some error")))
+			Expect(err).To(MatchError("some error at testpos"))
+		})
+	})
+})
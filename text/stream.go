@@ -0,0 +1,184 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package text
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"unicode/utf8"
+)
+
+// DefaultStreamChunkSize is the default number of bytes read from a stream at once
+const DefaultStreamChunkSize = 64 * 1024
+
+// MaxStreamLen is the length reported for a stream until the end of the input is reached
+const MaxStreamLen = int(^uint(0)>>1) / 2
+
+// stream holds the state of a file which is read from an io.Reader on demand
+//
+// The file data only contains the input from the start offset. The input before the discard offset is dropped when
+// the buffer needs to be grown.
+type stream struct {
+	src        io.Reader
+	buf        []byte
+	start      int
+	discardPos int
+	lineBase   int
+	pendingCR  bool
+	eof        bool
+	err        error
+}
+
+// NewStreamFile creates a new file object which reads the input from r on demand
+//
+// Only a sliding window of the input is kept in memory: the input is dropped before the position passed to
+// Reader.Discard, which is called by parsley.Context.Commit (e.g. by the combinator.Commit parser) and automatically
+// with the position of every outermost backtrack point (see parsley.Context.EnterBacktrackPoint).
+//
+// The backtrack points keep the input until the parsers can't go back to it, e.g. while a Choice tries its
+// alternatives the input is kept from the position of the Choice. Committing the processed input allows to drop it
+// even inside these parsers.
+func NewStreamFile(filename string, r io.Reader) *File {
+	return NewStreamFileSize(filename, r, DefaultStreamChunkSize)
+}
+
+// NewStreamFileSize creates a new streamed file object which reads the input in chunks of the given size
+func NewStreamFileSize(filename string, r io.Reader, chunkSize int) *File {
+	if chunkSize <= 0 {
+		panic("chunk size must be positive")
+	}
+
+	return &File{
+		filename: filename,
+		lines:    []int{0},
+		offset:   1,
+		stream: &stream{
+			src: r,
+			buf: make([]byte, chunkSize),
+		},
+	}
+}
+
+// peek returns with the buffered data from the cur offset after loading at least n bytes (if available)
+func (s *stream) peek(f *File, cur int, n int) []byte {
+	if cur < s.start {
+		if s.err == nil {
+			s.err = fmt.Errorf(
+				"can not read %s at offset %d, the input before offset %d was already discarded",
+				f.filename, cur, s.start,
+			)
+		}
+		return nil
+	}
+
+	if f.len < cur+n {
+		s.fill(f, cur+n)
+	}
+
+	if cur > f.len {
+		return nil
+	}
+	return f.data[cur-s.start:]
+}
+
+// fill reads the input until the given offset is loaded or the end of the input is reached
+func (s *stream) fill(f *File, end int) {
+	for f.len < end && !s.eof {
+		s.compact(f)
+
+		n, err := s.src.Read(s.buf)
+		s.append(f, s.buf[:n])
+
+		if err != nil {
+			if err != io.EOF {
+				s.err = fmt.Errorf("can not read %s: %w", f.filename, err)
+			}
+			if s.pendingCR {
+				s.pendingCR = false
+				f.data = append(f.data, '\r')
+				f.len++
+			}
+			s.eof = true
+		}
+	}
+}
+
+// append adds the new data to the buffer while replacing \r\n with \n and registering the new lines
+func (s *stream) append(f *File, b []byte) {
+	for _, c := range b {
+		if s.pendingCR {
+			s.pendingCR = false
+			if c != '\n' {
+				f.data = append(f.data, '\r')
+				f.len++
+			}
+		}
+
+		if c == '\r' {
+			s.pendingCR = true
+			continue
+		}
+
+		f.data = append(f.data, c)
+		f.len++
+		if c == '\n' {
+			f.lines = append(f.lines, f.len)
+		}
+	}
+}
+
+// compact drops the discarded input if it's at least half of the buffer
+// The remaining data is always copied to a new array, so the slices previously returned by the reader stay intact.
+func (s *stream) compact(f *File) {
+	discardPos := s.discardPos
+	if discardPos > f.len {
+		discardPos = f.len
+	}
+
+	discarded := discardPos - s.start
+	if discarded < len(s.buf) || discarded < len(f.data)/2 {
+		return
+	}
+
+	data := make([]byte, f.len-discardPos, f.len-discardPos+len(s.buf))
+	copy(data, f.data[discarded:])
+	f.data = data
+	s.start = discardPos
+
+	i := sort.Search(len(f.lines), func(i int) bool { return f.lines[i] > discardPos }) - 1
+	f.lines = append([]int(nil), f.lines[i:]...)
+	s.lineBase += i
+}
+
+// discard allows dropping the input before the given offset
+func (s *stream) discard(pos int) {
+	if pos > s.discardPos {
+		s.discardPos = pos
+	}
+}
+
+// runeReader reads runes from a reader starting at a given offset, loading the input on demand
+// It's used for matching regular expressions on segmented files.
+type runeReader struct {
+	reader *Reader
+	cur    int
+}
+
+// ReadRune reads the next rune
+func (r *runeReader) ReadRune() (rune, int, error) {
+	b := r.reader.file.peek(r.cur, utf8.UTFMax)
+	if len(b) == 0 {
+		r.reader.markRead(r.cur + 1)
+		return 0, 0, io.EOF
+	}
+
+	ch, size := utf8.DecodeRune(b)
+	r.cur += size
+	r.reader.markRead(r.cur)
+	return ch, size, nil
+}
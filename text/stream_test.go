@@ -0,0 +1,242 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package text_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing/iotest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parser"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+)
+
+var _ = Describe("Stream", func() {
+
+	var (
+		r     *text.Reader
+		f     *text.File
+		input string
+	)
+
+	BeforeEach(func() {
+		input = "abc def\n  123 \"xyz\""
+	})
+
+	JustBeforeEach(func() {
+		f = text.NewStreamFileSize("testfile", iotest.OneByteReader(strings.NewReader(input)), 2)
+		parsley.NewFileSet(f)
+		r = text.NewReader(f)
+	})
+
+	It("should read the input on demand", func() {
+		pos, found := r.MatchWord(f.Pos(0), "abc")
+		Expect(found).To(BeTrue())
+
+		pos, err := r.SkipWhitespaces(pos, text.WsSpaces)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pos).To(Equal(f.Pos(4)))
+
+		pos, found = r.ReadRune(pos, 'd')
+		Expect(found).To(BeTrue())
+
+		pos, match := r.ReadRegexp(pos, "[a-z]+")
+		Expect(string(match)).To(Equal("ef"))
+		Expect(pos).To(Equal(f.Pos(7)))
+
+		pos, err = r.SkipWhitespaces(pos, text.WsSpacesNl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pos).To(Equal(f.Pos(10)))
+
+		pos, matches := r.ReadRegexpSubmatch(pos, "([0-9])([0-9]+)")
+		Expect(matches).To(Equal([][]byte{[]byte("123"), []byte("1"), []byte("23")}))
+
+		pos, found = r.MatchString(pos, ` "`)
+		Expect(found).To(BeTrue())
+
+		pos, value := r.Readf(pos, func(b []byte) ([]byte, int) {
+			i := bytes.IndexByte(b, '"')
+			if i == -1 {
+				return b, len(b)
+			}
+			return b[:i], i
+		})
+		Expect(string(value)).To(Equal("xyz"))
+		Expect(r.IsEOF(pos)).To(BeFalse())
+		Expect(r.Remaining(pos)).To(Equal(text.MaxStreamLen - len(input) + 1))
+		Expect(r.IsEOF(pos + 1)).To(BeTrue())
+		Expect(r.Remaining(pos)).To(Equal(1))
+		Expect(r.Err()).ToNot(HaveOccurred())
+	})
+
+	It("should return with the positions", func() {
+		r.IsEOF(f.Pos(12))
+		Expect(f.Position(11)).To(Equal(text.NewPosition("testfile", 2, 4)))
+	})
+
+	Describe("Len()", func() {
+		It("should return with MaxStreamLen until the end of input is reached", func() {
+			Expect(f.Len()).To(Equal(text.MaxStreamLen))
+			r.IsEOF(f.Pos(len(input)))
+			Expect(f.Len()).To(Equal(len(input)))
+		})
+	})
+
+	Context("when the input contains Windows-style line endings", func() {
+		BeforeEach(func() {
+			input = "a\r\nb\r\r\nc\r"
+		})
+
+		It("should remove the \r characters", func() {
+			Expect(r.IsEOF(f.Pos(8))).To(BeTrue())
+			Expect(f.Len()).To(Equal(7))
+			_, found := r.MatchString(f.Pos(0), "a\nb\r\nc\r")
+			Expect(found).To(BeTrue())
+			Expect(f.Position(5).(*text.Position).Line).To(Equal(3))
+		})
+	})
+
+	Context("when the input was discarded", func() {
+		BeforeEach(func() {
+			input = "line1\nline2\nline3\nline4\n"
+		})
+
+		JustBeforeEach(func() {
+			_, found := r.MatchString(f.Pos(0), "line1\nline2\n")
+			Expect(found).To(BeTrue())
+			r.Discard(f.Pos(12))
+			_, found = r.MatchString(f.Pos(12), "line3\nline4\n")
+			Expect(found).To(BeTrue())
+		})
+
+		It("should keep the line numbers", func() {
+			Expect(f.Position(13)).To(Equal(text.NewPosition("testfile", 3, 2)))
+			Expect(f.Position(19)).To(Equal(text.NewPosition("testfile", 4, 2)))
+		})
+
+		It("should not return a position for the discarded input", func() {
+			Expect(f.Position(1)).To(Equal(parsley.NilPosition))
+		})
+
+		It("should return an error when going back", func() {
+			_, found := r.ReadRune(f.Pos(0), 'l')
+			Expect(found).To(BeFalse())
+			Expect(r.Err()).To(MatchError(
+				"can not read testfile at offset 0, the input before offset 12 was already discarded",
+			))
+		})
+	})
+
+	Context("when the input can not be read", func() {
+		JustBeforeEach(func() {
+			f = text.NewStreamFile("testfile", iotest.ErrReader(errors.New("some error")))
+			r = text.NewReader(f)
+		})
+
+		It("should return an error", func() {
+			Expect(r.IsEOF(f.Pos(0))).To(BeTrue())
+			Expect(r.Err()).To(MatchError("can not read testfile: some error"))
+		})
+	})
+
+	Context("when a parser goes back to the discarded input", func() {
+		It("should return the error from Parse", func() {
+			ctx := parsley.NewContext(parsley.NewFileSet(f), r)
+			p := parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+				ctx.Commit(f.Pos(10))
+				r.IsEOF(f.Pos(19))
+				if _, found := r.ReadRune(pos, 'a'); !found {
+					return nil, data.EmptyIntSet, parsley.NewErrorf(pos, "was expecting 'a'")
+				}
+				return ast.EmptyNode(pos), data.EmptyIntSet, nil
+			})
+
+			_, err := parsley.Parse(ctx, p)
+			Expect(err).To(MatchError(
+				"failed to parse the input: can not read testfile at offset 0, the input before offset 10 was already discarded",
+			))
+		})
+	})
+
+	Context("when a large input is parsed", func() {
+		var (
+			peak *peakBufferReader
+			line parsley.Parser
+		)
+
+		JustBeforeEach(func() {
+			var sb strings.Builder
+			for i := 0; i < 50000; i++ {
+				fmt.Fprintf(&sb, "line %05d\n", i)
+			}
+			peak = &peakBufferReader{r: strings.NewReader(sb.String())}
+			f = text.NewStreamFileSize("testfile", peak, 1024)
+			peak.f = f
+			r = text.NewReader(f)
+			line = terminal.Regexp(nil, "LINE", "line", `line [0-9]+\n`, 0)
+		})
+
+		It("should keep the memory usage bounded if the processed input is committed", func() {
+			ctx := parsley.NewContext(parsley.NewFileSet(f), r)
+			_, err := parsley.Parse(ctx, combinator.Sentence(combinator.Many(combinator.Commit(line))))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(peak.max).To(BeNumerically("<=", 4*1024))
+		})
+
+		It("should discard the processed input automatically", func() {
+			ctx := parsley.NewContext(parsley.NewFileSet(f), r)
+			_, err := parsley.Parse(ctx, combinator.Sentence(combinator.Many(line)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(peak.max).To(BeNumerically("<=", 4*1024))
+		})
+
+		It("should keep the input while a parser can go back to it", func() {
+			ctx := parsley.NewContext(parsley.NewFileSet(f), r)
+			_, err := parsley.Parse(ctx, combinator.Sentence(combinator.Choice(
+				combinator.SeqOf(combinator.Many(line), terminal.Rune('x')),
+				combinator.Many(line),
+			)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(peak.max).To(BeNumerically(">=", 500000))
+		})
+
+		It("should keep the input before a lookahead", func() {
+			ctx := parsley.NewContext(parsley.NewFileSet(f), r)
+			_, err := parsley.Parse(ctx, combinator.Sentence(combinator.SeqOf(
+				combinator.And(combinator.Many(line)),
+				combinator.Many(line),
+			)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(peak.max).To(BeNumerically(">=", 500000))
+		})
+	})
+})
+
+// peakBufferReader records the highest number of bytes buffered by the file before every read
+type peakBufferReader struct {
+	r   io.Reader
+	f   *text.File
+	max int
+}
+
+func (p *peakBufferReader) Read(b []byte) (int, error) {
+	if n := p.f.Buffered(); n > p.max {
+		p.max = n
+	}
+	return p.r.Read(b)
+}
@@ -63,6 +63,13 @@ func (b *BoolNode) SetReaderPos(fun func(parsley.Pos) parsley.Pos) {
 	b.readerPos = fun(b.readerPos)
 }
 
+// ShiftPos moves the node by the shifter's delta
+func (b *BoolNode) ShiftPos(s *parsley.Shifter) parsley.Node {
+	b.pos = s.Pos(b.pos)
+	b.readerPos = s.Pos(b.readerPos)
+	return b
+}
+
 // String returns with a string representation of the node
 func (b *BoolNode) String() string {
 	return fmt.Sprintf("%s{%v, %d..%d}", b.Token(), b.value, b.pos, b.readerPos)
@@ -85,5 +92,5 @@ func Bool(schema interface{}, trueStr string, falseStr string) parser.Func {
 			return NewBoolNode(schema, false, pos, readerPos), data.EmptyIntSet, nil
 		}
 		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
-	})
+	}).Trace("BOOL")
 }
@@ -64,6 +64,13 @@ func (c *CharNode) SetReaderPos(fun func(parsley.Pos) parsley.Pos) {
 	c.readerPos = fun(c.readerPos)
 }
 
+// ShiftPos moves the node by the shifter's delta
+func (c *CharNode) ShiftPos(s *parsley.Shifter) parsley.Node {
+	c.pos = s.Pos(c.pos)
+	c.readerPos = s.Pos(c.readerPos)
+	return c
+}
+
 // String returns with a string representation of the node
 func (c *CharNode) String() string {
 	return fmt.Sprintf("%s{%v, %d..%d}", c.Token(), c.value, c.pos, c.readerPos)
@@ -98,5 +105,5 @@ func Char(schema interface{}) parser.Func {
 		}
 
 		return NewCharNode(schema, value, pos, readerPos), data.EmptyIntSet, nil
-	})
+	}).Trace("CHAR")
 }
@@ -64,6 +64,13 @@ func (f *FloatNode) SetReaderPos(fun func(parsley.Pos) parsley.Pos) {
 	f.readerPos = fun(f.readerPos)
 }
 
+// ShiftPos moves the node by the shifter's delta
+func (f *FloatNode) ShiftPos(s *parsley.Shifter) parsley.Node {
+	f.pos = s.Pos(f.pos)
+	f.readerPos = s.Pos(f.readerPos)
+	return f
+}
+
 // String returns with a string representation of the node
 func (f *FloatNode) String() string {
 	return fmt.Sprintf("%s{%v, %d..%d}", f.Token(), f.value, f.pos, f.readerPos)
@@ -83,5 +90,5 @@ func Float(schema interface{}) parser.Func {
 			return NewFloatNode(schema, val, pos, readerPos), data.EmptyIntSet, nil
 		}
 		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
-	})
+	}).Trace("FLOAT")
 }
@@ -64,6 +64,13 @@ func (i *IntegerNode) SetReaderPos(fun func(parsley.Pos) parsley.Pos) {
 	i.readerPos = fun(i.readerPos)
 }
 
+// ShiftPos moves the node by the shifter's delta
+func (i *IntegerNode) ShiftPos(s *parsley.Shifter) parsley.Node {
+	i.pos = s.Pos(i.pos)
+	i.readerPos = s.Pos(i.readerPos)
+	return i
+}
+
 // String returns with a string representation of the node
 func (i *IntegerNode) String() string {
 	return fmt.Sprintf("%s{%v, %d..%d}", i.Token(), i.value, i.pos, i.readerPos)
@@ -87,5 +94,5 @@ func Integer(schema interface{}) parser.Func {
 			return NewIntegerNode(schema, intValue, pos, readerPos), data.EmptyIntSet, nil
 		}
 		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
-	})
+	}).Trace("INTEGER")
 }
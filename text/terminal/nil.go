@@ -61,6 +61,13 @@ func (n *NilNode) SetReaderPos(fun func(parsley.Pos) parsley.Pos) {
 	n.readerPos = fun(n.readerPos)
 }
 
+// ShiftPos moves the node by the shifter's delta
+func (n *NilNode) ShiftPos(s *parsley.Shifter) parsley.Node {
+	n.pos = s.Pos(n.pos)
+	n.readerPos = s.Pos(n.readerPos)
+	return n
+}
+
 // String returns with a string representation of the node
 func (n *NilNode) String() string {
 	return fmt.Sprintf("%s{%d..%d}", n.Token(), n.pos, n.readerPos)
@@ -81,5 +88,5 @@ func Nil(schema interface{}, nilStr string) parser.Func {
 		}
 
 		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
-	})
+	}).Trace("NIL")
 }
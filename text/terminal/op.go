@@ -62,6 +62,13 @@ func (o *OpNode) SetReaderPos(fun func(parsley.Pos) parsley.Pos) {
 	o.readerPos = fun(o.readerPos)
 }
 
+// ShiftPos moves the node by the shifter's delta
+func (o *OpNode) ShiftPos(s *parsley.Shifter) parsley.Node {
+	o.pos = s.Pos(o.pos)
+	o.readerPos = s.Pos(o.readerPos)
+	return o
+}
+
 // String returns with a string representation of the node
 func (o *OpNode) String() string {
 	return fmt.Sprintf("%s{%d..%d}", o.Token(), o.pos, o.readerPos)
@@ -81,5 +88,5 @@ func Op(op string) parser.Func {
 			return NewOpNode(op, pos, readerPos), data.EmptyIntSet, nil
 		}
 		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
-	})
+	}).Trace(op)
 }
@@ -38,5 +38,5 @@ func Regexp(schema interface{}, token string, name string, regexp string, groupI
 			}
 		}
 		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
-	})
+	}).Trace(token)
 }
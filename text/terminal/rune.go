@@ -26,5 +26,5 @@ func Rune(ch rune) parser.Func {
 			return ast.NewTerminalNode(nil, string(ch), ch, pos, readerPos), data.EmptyIntSet, nil
 		}
 		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
-	})
+	}).Trace(string(ch))
 }
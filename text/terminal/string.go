@@ -65,6 +65,13 @@ func (s *StringNode) SetReaderPos(fun func(parsley.Pos) parsley.Pos) {
 	s.readerPos = fun(s.readerPos)
 }
 
+// ShiftPos moves the node by the shifter's delta
+func (s *StringNode) ShiftPos(sh *parsley.Shifter) parsley.Node {
+	s.pos = sh.Pos(s.pos)
+	s.readerPos = sh.Pos(s.readerPos)
+	return s
+}
+
 // String returns with a string representation of the node
 func (s *StringNode) String() string {
 	return fmt.Sprintf("%s{%v, %d..%d}", s.Token(), s.value, s.pos, s.readerPos)
@@ -107,7 +114,7 @@ func String(schema interface{}, allowBackquote bool) parser.Func {
 			return nil, data.EmptyIntSet, parsley.NewErrorf(readerPos, "was expecting '%s'", string(quote))
 		}
 		return NewStringNode(schema, string(value), pos, readerPos), data.EmptyIntSet, nil
-	})
+	}).Trace("STRING")
 }
 
 func unquoteString(b []byte) ([]byte, int) {
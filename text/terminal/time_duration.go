@@ -67,6 +67,13 @@ func (t *TimeDurationNode) SetReaderPos(fun func(parsley.Pos) parsley.Pos) {
 	t.readerPos = fun(t.readerPos)
 }
 
+// ShiftPos moves the node by the shifter's delta
+func (t *TimeDurationNode) ShiftPos(s *parsley.Shifter) parsley.Node {
+	t.pos = s.Pos(t.pos)
+	t.readerPos = s.Pos(t.readerPos)
+	return t
+}
+
 // String returns with a string representation of the node
 func (t *TimeDurationNode) String() string {
 	return fmt.Sprintf("%s{%v, %d..%d}", t.Token(), t.value, t.pos, t.readerPos)
@@ -88,5 +95,5 @@ func TimeDuration(schema interface{}) parser.Func {
 			return NewTimeDurationNode(schema, duration, pos, readerPos), data.EmptyIntSet, nil
 		}
 		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
-	})
+	}).Trace("TIME_DURATION")
 }
@@ -32,5 +32,5 @@ func Word(schema interface{}, word string, value interface{}) parser.Func {
 			return ast.NewTerminalNode(schema, token, value, pos, readerPos), data.EmptyIntSet, nil
 		}
 		return nil, data.EmptyIntSet, parsley.NewError(pos, notFoundErr)
-	})
+	}).Trace(token)
 }
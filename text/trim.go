@@ -15,10 +15,11 @@ import (
 
 // LeftTrim skips the whitespaces before it tries to match the given parser
 func LeftTrim(p parsley.Parser, wsMode WsMode) parser.Func {
-	return func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
 		tr := ctx.Reader().(*Reader)
 
 		originalPos := pos
+		notFoundErrs := ctx.NotFoundErrors()
 
 		pos, wsErr := tr.SkipWhitespaces(pos, wsMode)
 
@@ -37,6 +38,7 @@ func LeftTrim(p parsley.Parser, wsMode WsMode) parser.Func {
 				}
 
 				if parsley.IsNotFoundError(err) {
+					moveNotFoundErrors(ctx, notFoundErrs, pos, originalPos)
 					return res, cp, parsley.NewError(originalPos, err.Cause())
 				}
 			}
@@ -49,17 +51,35 @@ func LeftTrim(p parsley.Parser, wsMode WsMode) parser.Func {
 		}
 
 		return res, cp, nil
+	}).Trace("LEFT_TRIM")
+}
+
+// moveNotFoundErrors moves the expected inputs registered at the from position to the to position,
+// merging them with the ones previously registered at the to position
+func moveNotFoundErrors(ctx *parsley.Context, prev parsley.NotFoundErrors, from parsley.Pos, to parsley.Pos) {
+	notFoundErrs := ctx.NotFoundErrors()
+	if notFoundErrs.Pos != from {
+		return
+	}
+
+	names := notFoundErrs.Names
+	if prev.Pos == to {
+		names = append(prev.Names[0:len(prev.Names):len(prev.Names)], names...)
 	}
+
+	ctx.SetNotFoundErrors(parsley.NotFoundErrors{Pos: to, Names: names})
 }
 
 // RightTrim reads and skips the whitespaces after any parser matches and updates the reader position
 func RightTrim(p parsley.Parser, wsMode WsMode) parser.Func {
-	return func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
+	return parser.Func(func(ctx *parsley.Context, leftRecCtx data.IntMap, pos parsley.Pos) (parsley.Node, data.IntSet, parsley.Error) {
 		tr := ctx.Reader().(*Reader)
+		notFoundErrs := ctx.NotFoundErrors()
 		res, cp, err := p.Parse(ctx, leftRecCtx, pos)
 		if err != nil {
 			errPos, _ := tr.SkipWhitespaces(err.Pos(), wsMode)
 			if errPos > err.Pos() {
+				moveNotFoundErrors(ctx, notFoundErrs, err.Pos(), errPos)
 				err = parsley.NewError(errPos, err.Cause())
 			}
 			return res, cp, err
@@ -77,7 +97,7 @@ func RightTrim(p parsley.Parser, wsMode WsMode) parser.Func {
 		}
 
 		return res, cp, nil
-	}
+	}).Trace("RIGHT_TRIM")
 }
 
 // Trim removes all whitespaces before and after the result token
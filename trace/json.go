@@ -0,0 +1,105 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package trace
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+// JSONTracer writes every parser call entry and exit as a separate JSON object on a new line
+//
+// The objects have the following fields:
+//   - event: "enter" or "exit"
+//   - parser: the parser name
+//   - depth: the call depth, starting from zero
+//   - pos: the start position
+//   - token, start, end: the result node's token and span (exit only, if there was a result)
+//   - error: the error message (exit only, if there was an error)
+//   - curtailing: the curtailing parser indices (exit only, if not empty)
+//   - cached: true if the result came from the result cache (exit only)
+type JSONTracer struct {
+	enc     *json.Encoder
+	fileSet *parsley.FileSet
+	depth   int
+	err     error
+}
+
+type jsonEvent struct {
+	Event      string `json:"event"`
+	Parser     string `json:"parser"`
+	Depth      int    `json:"depth"`
+	Pos        string `json:"pos"`
+	Token      string `json:"token,omitempty"`
+	Start      string `json:"start,omitempty"`
+	End        string `json:"end,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Curtailing []int  `json:"curtailing,omitempty"`
+	Cached     bool   `json:"cached,omitempty"`
+}
+
+// NewJSONTracer creates a new JSON lines tracer
+// The positions are written as line:column if the file set is not nil.
+func NewJSONTracer(w io.Writer, fileSet *parsley.FileSet) *JSONTracer {
+	return &JSONTracer{
+		enc:     json.NewEncoder(w),
+		fileSet: fileSet,
+	}
+}
+
+// Enter writes the entry of a parser call
+func (t *JSONTracer) Enter(name string, pos parsley.Pos) {
+	t.write(jsonEvent{
+		Event:  "enter",
+		Parser: name,
+		Depth:  t.depth,
+		Pos:    position(t.fileSet, pos),
+	})
+	t.depth++
+}
+
+// Exit writes the result of a parser call
+func (t *JSONTracer) Exit(name string, pos parsley.Pos, result parsley.TraceResult) {
+	if t.depth > 0 {
+		t.depth--
+	}
+
+	e := jsonEvent{
+		Event:  "exit",
+		Parser: name,
+		Depth:  t.depth,
+		Pos:    position(t.fileSet, pos),
+		Cached: result.Cached,
+	}
+	if result.Node != nil {
+		e.Token = result.Node.Token()
+		e.Start = position(t.fileSet, result.Node.Pos())
+		e.End = position(t.fileSet, result.Node.ReaderPos())
+	}
+	if result.Err != nil {
+		e.Error = result.Err.Error()
+	}
+	if result.CurtailingParsers.Len() > 0 {
+		e.Curtailing = intSetValues(result.CurtailingParsers)
+	}
+
+	t.write(e)
+}
+
+// Err returns with the first write error (if any)
+func (t *JSONTracer) Err() error {
+	return t.err
+}
+
+func (t *JSONTracer) write(e jsonEvent) {
+	if t.err != nil {
+		return
+	}
+	t.err = t.enc.Encode(e)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package trace
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/conflowio/parsley/parsley"
+)
+
+// TextTracer writes the parser calls as an indented tree
+//
+// The entries are written as "> NAME pos" and the exits as "< NAME pos = TOKEN start..end",
+// "< NAME pos ! error" or "< NAME pos = nil" if there was no match and no error.
+// Cached results and the curtailing parsers are also displayed.
+type TextTracer struct {
+	w       io.Writer
+	fileSet *parsley.FileSet
+	indent  string
+	depth   int
+	err     error
+}
+
+// NewTextTracer creates a new text tracer
+// The positions are displayed as line:column if the file set is not nil.
+func NewTextTracer(w io.Writer, fileSet *parsley.FileSet) *TextTracer {
+	return &TextTracer{
+		w:       w,
+		fileSet: fileSet,
+		indent:  "  ",
+	}
+}
+
+// Indent sets the indentation string, the default is two spaces
+func (t *TextTracer) Indent(indent string) *TextTracer {
+	t.indent = indent
+	return t
+}
+
+// Enter writes the entry of a parser call
+func (t *TextTracer) Enter(name string, pos parsley.Pos) {
+	t.write(fmt.Sprintf("> %s %s", name, position(t.fileSet, pos)))
+	t.depth++
+}
+
+// Exit writes the result of a parser call
+func (t *TextTracer) Exit(name string, pos parsley.Pos, result parsley.TraceResult) {
+	if t.depth > 0 {
+		t.depth--
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "< %s %s", name, position(t.fileSet, pos))
+	switch {
+	case result.Err != nil:
+		fmt.Fprintf(&sb, " ! %s at %s", result.Err.Error(), position(t.fileSet, result.Err.Pos()))
+	case result.Node != nil:
+		fmt.Fprintf(
+			&sb, " = %s %s..%s",
+			result.Node.Token(), position(t.fileSet, result.Node.Pos()), position(t.fileSet, result.Node.ReaderPos()),
+		)
+	default:
+		sb.WriteString(" = nil")
+	}
+	if result.Cached {
+		sb.WriteString(" (cached)")
+	}
+	if result.CurtailingParsers.Len() > 0 {
+		fmt.Fprintf(&sb, " curtailed by %v", intSetValues(result.CurtailingParsers))
+	}
+
+	t.write(sb.String())
+}
+
+// Err returns with the first write error (if any)
+func (t *TextTracer) Err() error {
+	return t.err
+}
+
+func (t *TextTracer) write(line string) {
+	if t.err != nil {
+		return
+	}
+	_, t.err = io.WriteString(t.w, strings.Repeat(t.indent, t.depth)+line+"\n")
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package trace contains tracers which write the parser calls as an indented tree or as JSON lines
+//
+// The tracers have to be set on the context with parsley.Context.SetTracer.
+package trace
+
+import (
+	"strconv"
+
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parsley"
+)
+
+// position returns with the position as line:column if possible, otherwise as a raw position
+func position(fs *parsley.FileSet, pos parsley.Pos) string {
+	if fs != nil {
+		if r, ok := fs.Range(pos, pos); ok && r.Start.Line > 0 {
+			return strconv.Itoa(r.Start.Line) + ":" + strconv.Itoa(r.Start.Column)
+		}
+	}
+	return strconv.Itoa(int(pos))
+}
+
+func intSetValues(s data.IntSet) []int {
+	res := make([]int, 0, s.Len())
+	s.Each(func(val int) {
+		res = append(res, val)
+	})
+	return res
+}
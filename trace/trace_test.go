@@ -0,0 +1,174 @@
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package trace_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/conflowio/parsley/ast"
+	"github.com/conflowio/parsley/combinator"
+	"github.com/conflowio/parsley/data"
+	"github.com/conflowio/parsley/parsley"
+	"github.com/conflowio/parsley/text"
+	"github.com/conflowio/parsley/text/terminal"
+	"github.com/conflowio/parsley/trace"
+)
+
+// Let's trace how a simple sum is parsed
+func Example() {
+	p := combinator.SeqOf(
+		terminal.Integer("integer"),
+		text.LeftTrim(combinator.Choice(terminal.Op("-"), terminal.Op("+")), text.WsSpaces),
+		text.LeftTrim(terminal.Integer("integer"), text.WsSpaces),
+	).Token("SUM")
+
+	f := text.NewFile("example.file", []byte("1 + 2"))
+	fs := parsley.NewFileSet(f)
+	ctx := parsley.NewContext(fs, text.NewReader(f))
+	ctx.SetTracer(trace.NewTextTracer(os.Stdout, fs))
+	if _, err := parsley.Parse(ctx, p); err != nil {
+		panic(err)
+	}
+	// Output:
+	// > SUM 1:1
+	//   > INTEGER 1:1
+	//   < INTEGER 1:1 = INTEGER 1:1..1:2
+	//   > LEFT_TRIM 1:2
+	//     > CHOICE 1:3
+	//       > - 1:3
+	//       < - 1:3 ! was expecting "-" at 1:3
+	//       > + 1:3
+	//       < + 1:3 = + 1:3..1:4
+	//     < CHOICE 1:3 = + 1:3..1:4
+	//   < LEFT_TRIM 1:2 = + 1:3..1:4
+	//   > LEFT_TRIM 1:4
+	//     > INTEGER 1:5
+	//     < INTEGER 1:5 = INTEGER 1:5..1:6
+	//   < LEFT_TRIM 1:4 = INTEGER 1:5..1:6
+	// < SUM 1:1 = SUM 1:1..1:6
+}
+
+var _ = Describe("TextTracer", func() {
+
+	var (
+		buf    *bytes.Buffer
+		tracer *trace.TextTracer
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		tracer = trace.NewTextTracer(buf, nil)
+	})
+
+	It("should write the raw positions without a file set", func() {
+		tracer.Enter("P", 1)
+		tracer.Exit("P", 1, parsley.TraceResult{Node: ast.NewTerminalNode(nil, "A", "a", 1, 2)})
+		Expect(buf.String()).To(Equal("> P 1\n< P 1 = A 1..2\n"))
+	})
+
+	It("should indent the nested calls", func() {
+		tracer.Indent("\t")
+		tracer.Enter("P1", 1)
+		tracer.Enter("P2", 1)
+		tracer.Exit("P2", 1, parsley.TraceResult{})
+		tracer.Exit("P1", 1, parsley.TraceResult{})
+		Expect(buf.String()).To(Equal("> P1 1\n\t> P2 1\n\t< P2 1 = nil\n< P1 1 = nil\n"))
+	})
+
+	It("should write the error", func() {
+		tracer.Exit("P", 1, parsley.TraceResult{Err: parsley.NewErrorf(2, "some error")})
+		Expect(buf.String()).To(Equal("< P 1 ! some error at 2\n"))
+	})
+
+	It("should write the cached flag and the curtailing parsers", func() {
+		tracer.Exit("P", 1, parsley.TraceResult{CurtailingParsers: data.NewIntSet(2, 1), Cached: true})
+		Expect(buf.String()).To(Equal("< P 1 = nil (cached) curtailed by [1 2]\n"))
+	})
+
+	It("should return with the first write error", func() {
+		w := &failingWriter{err: errors.New("write failed")}
+		tracer = trace.NewTextTracer(w, nil)
+		tracer.Enter("P", 1)
+		tracer.Exit("P", 1, parsley.TraceResult{})
+		Expect(tracer.Err()).To(MatchError("write failed"))
+		Expect(w.calls).To(Equal(1))
+	})
+
+	It("should report the cached results of the memoized parsers", func() {
+		f := text.NewFile("example.file", []byte("a"))
+		ctx := parsley.NewContext(parsley.NewFileSet(f), text.NewReader(f))
+		ctx.SetTracer(tracer)
+
+		p := combinator.NewMemoRegistry().MemoizeNamed("A", terminal.Rune('a'))
+		_, _, _ = p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+		_, _, _ = p.Parse(ctx, data.EmptyIntMap, f.Pos(0))
+
+		Expect(buf.String()).To(Equal(
+			"> MEMOIZE#A 1\n  > a 1\n  < a 1 = a 1..2\n< MEMOIZE#A 1 = a 1..2\n> MEMOIZE#A 1\n< MEMOIZE#A 1 = a 1..2 (cached)\n",
+		))
+	})
+})
+
+var _ = Describe("JSONTracer", func() {
+
+	var (
+		buf    *bytes.Buffer
+		fs     *parsley.FileSet
+		tracer *trace.JSONTracer
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		f := text.NewFile("example.file", []byte("ab\ncd"))
+		fs = parsley.NewFileSet(f)
+		tracer = trace.NewJSONTracer(buf, fs)
+	})
+
+	It("should write a JSON object for every event", func() {
+		tracer.Enter("P1", 1)
+		tracer.Enter("P2", 4)
+		tracer.Exit("P2", 4, parsley.TraceResult{
+			Node:              ast.NewTerminalNode(nil, "A", "a", 4, 6),
+			CurtailingParsers: data.NewIntSet(3),
+			Cached:            true,
+		})
+		tracer.Exit("P1", 1, parsley.TraceResult{Err: parsley.NewErrorf(4, "some error")})
+		Expect(tracer.Err()).ToNot(HaveOccurred())
+
+		Expect(strings.Split(strings.TrimSpace(buf.String()), "\n")).To(Equal([]string{
+			`{"event":"enter","parser":"P1","depth":0,"pos":"1:1"}`,
+			`{"event":"enter","parser":"P2","depth":1,"pos":"2:1"}`,
+			`{"event":"exit","parser":"P2","depth":1,"pos":"2:1","token":"A","start":"2:1","end":"2:3","curtailing":[3],"cached":true}`,
+			`{"event":"exit","parser":"P1","depth":0,"pos":"1:1","error":"some error"}`,
+		}))
+	})
+
+	It("should return with the first write error", func() {
+		w := &failingWriter{err: errors.New("write failed")}
+		tracer = trace.NewJSONTracer(w, fs)
+		tracer.Enter("P", 1)
+		tracer.Exit("P", 1, parsley.TraceResult{})
+		Expect(tracer.Err()).To(MatchError("write failed"))
+		Expect(w.calls).To(Equal(1))
+	})
+})
+
+type failingWriter struct {
+	err   error
+	calls int
+}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	f.calls++
+	return 0, f.err
+}